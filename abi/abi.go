@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/LatticeBCLab/go-lattice/common/types"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/rs/zerolog/log"
@@ -38,6 +39,24 @@ type LatticeAbi interface {
 	GetConstructor(args ...interface{}) LatticeFunction
 	// GetLatticeFunction get function from abi string.
 	GetLatticeFunction(methodName string, args ...interface{}) (LatticeFunction, error)
+	// DecodeEvent 解码合约事件，同时实现了 types.EventDecoder，可用于 types.Receipt.DecodeEvents
+	//
+	// Parameters:
+	//   - event *types.Event
+	//
+	// Returns:
+	//   - *types.DecodedEvent
+	//   - error: 事件与ABI不匹配时为 types.ErrEventNotMatched
+	DecodeEvent(event *types.Event) (*types.DecodedEvent, error)
+	// BindEvent 解码合约事件并绑定到结构体
+	//
+	// Parameters:
+	//   - event *types.Event
+	//   - out interface{}: 结构体指针，参数名转为驼峰后与字段名匹配
+	//
+	// Returns:
+	//   - error
+	BindEvent(event *types.Event, out interface{}) error
 }
 
 type latticeAbi struct {
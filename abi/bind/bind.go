@@ -0,0 +1,566 @@
+// Package bind 根据合约的ABI生成类型化的Go绑定代码，并提供生成代码运行时依赖的辅助方法
+//
+// 生成的绑定代码包括：
+//   - Encode<Method> 编码方法的参数，address、address[] 和 address[N] 参数使用zltc地址字符串
+//   - Decode<Method> 将 ContractRet 解码为类型化的返回值，address、address[] 和 address[N] 返回值转为zltc地址
+//   - <Method> 只读方法通过 Lattice.PreCallContract 预执行，其他方法通过 Lattice.CallContractWaitReceipt 发起交易
+//   - Parse<Event> 将回执中的事件解码为类型化的结构体，address、address[] 和 address[N] 字段转为zltc地址
+//   - Deploy<Type> 提供了合约字节码时，部署合约并等待回执
+//
+// 地址转换只作用于参数、返回值和事件字段本身，tuple 结构体的字段和 address[][] 等嵌套类型中的地址
+// 仍为 common.Address，需要通过 bind.ToAddress 和 bind.FromAddress 自行转换
+//
+// 配合 go:generate 使用：
+//
+//	//go:generate go run github.com/LatticeBCLab/go-lattice/cmd/latticegen -abi Token.abi -pkg token -type Token -out token.go
+package bind
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+
+	latticeabi "github.com/LatticeBCLab/go-lattice/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+)
+
+// Config 生成绑定代码的配置
+//
+//   - Package  生成代码的包名
+//   - Type     绑定的类型名，如 Token
+//   - Abi      合约的ABI JSON
+//   - Bytecode 合约的字节码，不为空时生成 Deploy<Type> 方法
+//   - Address  合约地址，不为空时生成 <Type>Address 常量
+type Config struct {
+	Package  string
+	Type     string
+	Abi      string
+	Bytecode string
+	Address  string
+}
+
+func (config *Config) validate() error {
+	if !token.IsIdentifier(config.Package) {
+		return fmt.Errorf("无效的包名：%s", config.Package)
+	}
+	if !token.IsIdentifier(config.Type) || !token.IsExported(config.Type) {
+		return fmt.Errorf("无效的类型名：%s，类型名需要以大写字母开头", config.Type)
+	}
+	if strings.Contains(config.Abi, "`") {
+		return errors.New("ABI中不能包含反引号")
+	}
+	return nil
+}
+
+// reservedMethodNames 绑定类型已有的方法名
+var reservedMethodNames = map[string]bool{"MyAbi": true, "ContractAddress": true}
+
+// reservedParamNames 生成的方法已使用的参数名
+var reservedParamNames = map[string]bool{"c": true, "ctx": true, "credentials": true, "chainId": true, "owner": true, "opts": true, "svc": true, "data": true, "err": true, "args": true, "contractRet": true, "values": true}
+
+// Generate 根据ABI生成类型化的Go绑定代码
+//
+// Parameters:
+//   - config *Config
+//
+// Returns:
+//   - []byte: 格式化后的Go源码
+//   - error
+func Generate(config *Config) ([]byte, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	myabi := latticeabi.FromJson(config.Abi)
+	if myabi == nil {
+		return nil, errors.New("解析ABI失败")
+	}
+
+	if config.Bytecode != "" && !strings.HasPrefix(config.Bytecode, "0x") {
+		config.Bytecode = "0x" + config.Bytecode
+	}
+
+	g := &generator{config: config, abi: myabi, structNames: make(map[string]string)}
+	g.header()
+	if config.Bytecode != "" {
+		g.deploy()
+	}
+	for _, name := range sortedKeys(myabi.Methods) {
+		if err := g.method(myabi.Methods[name]); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range sortedKeys(myabi.Events) {
+		g.event(myabi.Events[name])
+	}
+
+	g.buf.Write(g.structs.Bytes())
+
+	source, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("格式化生成的代码失败：%w", err)
+	}
+	return source, nil
+}
+
+type generator struct {
+	config      *Config
+	abi         *abi.ABI
+	buf         bytes.Buffer
+	structs     bytes.Buffer      // 合约中的结构体类型
+	structNames map[string]string // 结构体的ABI签名 -> 类型名
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) header() {
+	t := g.config.Type
+	g.printf("// Code generated by latticegen. DO NOT EDIT.\n\n")
+	g.printf("package %s\n\n", g.config.Package)
+	g.printf(`import (
+	"context"
+	"math/big"
+
+	"github.com/LatticeBCLab/go-lattice/abi"
+	"github.com/LatticeBCLab/go-lattice/abi/bind"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice"
+	gethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	_ = context.Background
+	_ = big.NewInt
+	_ = bind.ToAddress
+	_ = types.ErrEventNotMatched
+	_ = gethabi.ConvertType
+	_ = common.BytesToAddress
+	_ = hexutil.Encode
+)
+
+`)
+	g.printf("// %sAbiString %s合约的ABI\n", t, t)
+	g.printf("const %sAbiString = `%s`\n\n", t, g.config.Abi)
+	if g.config.Bytecode != "" {
+		g.printf("// %sBytecode %s合约的字节码\n", t, t)
+		g.printf("const %sBytecode = %q\n\n", t, g.config.Bytecode)
+	}
+	if g.config.Address != "" {
+		g.printf("// %sAddress %s合约的地址\n", t, t)
+		g.printf("const %sAddress = %q\n\n", t, g.config.Address)
+	}
+
+	g.printf(`// %[1]s %[1]s合约的类型化绑定
+type %[1]s struct {
+	abi     abi.LatticeAbi
+	svc     lattice.Lattice
+	address string
+}
+
+// New%[1]s 创建%[1]s合约的绑定，并向 svc 注册合约的ABI用于解码合约执行失败的原因
+//
+// Parameters:
+//   - svc lattice.Lattice: 为nil时只能编码和解码，不能发起交易
+//   - contractAddress string: 合约地址
+//
+// Returns:
+//   - *%[1]s
+func New%[1]s(svc lattice.Lattice, contractAddress string) *%[1]s {
+	contractAbi := abi.NewAbi(%[1]sAbiString)
+	if svc != nil {
+		svc.RegisterContractAbi(contractAddress, contractAbi)
+	}
+	return &%[1]s{abi: contractAbi, svc: svc, address: contractAddress}
+}
+
+// MyAbi 获取合约的ABI
+func (c *%[1]s) MyAbi() *gethabi.ABI {
+	return c.abi.RawAbi()
+}
+
+// ContractAddress 获取合约地址
+func (c *%[1]s) ContractAddress() string {
+	return c.address
+}
+
+`, t)
+}
+
+func (g *generator) deploy() {
+	t := g.config.Type
+	params := g.params(g.abi.Constructor.Inputs)
+	g.printf("// EncodeDeploy%s 编码部署合约的数据，包括合约字节码和构造函数的参数\n", t)
+	g.printf("func EncodeDeploy%s(%s) (string, error) {\n", t, params.decl)
+	g.printf("contractAbi := abi.NewAbi(%sAbiString)\n", t)
+	params.convert(g, `""`)
+	g.printf(`args, err := contractAbi.RawAbi().Pack(""%s)
+	if err != nil {
+		return "", err
+	}
+	return %sBytecode + common.Bytes2Hex(args), nil
+}
+
+`, params.call, t)
+
+	g.printf(`// Deploy%[1]s 部署%[1]s合约并等待回执，回执的 ContractAddress 为合约地址
+//
+// Returns:
+//   - *common.Hash
+//   - *types.Receipt
+//   - error
+func Deploy%[1]s(ctx context.Context, svc lattice.Lattice, credentials *lattice.Credentials, chainId string, opts *bind.TransactOpts%[2]s) (*common.Hash, *types.Receipt, error) {
+	data, err := EncodeDeploy%[1]s(%[3]s)
+	if err != nil {
+		return nil, nil, err
+	}
+	return bind.Deploy(ctx, svc, credentials, chainId, data, opts)
+}
+
+`, t, prefixComma(params.decl), params.names)
+}
+
+func (g *generator) method(method abi.Method) error {
+	t := g.config.Type
+	goName := abi.ToCamelCase(method.Name)
+	if reservedMethodNames[goName] {
+		return fmt.Errorf("合约方法【%s】与绑定类型的方法重名", method.Name)
+	}
+	params := g.params(method.Inputs)
+
+	g.printf("// Encode%s 编码合约方法%s的参数\n", goName, method.Sig)
+	g.printf("func (c *%s) Encode%s(%s) (string, error) {\n", t, goName, params.decl)
+	params.convert(g, `""`)
+	g.printf(`data, err := c.abi.RawAbi().Pack(%q%s)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(data), nil
+}
+
+`, method.Name, params.call)
+
+	outputs := make([]string, len(method.Outputs))
+	for i, output := range method.Outputs {
+		outputs[i] = fmt.Sprintf("out%d %s", i, g.bindType(output.Type))
+	}
+	results := strings.Join(append(outputs, "err error"), ", ")
+
+	if len(method.Outputs) > 0 {
+		g.printf("// Decode%s 解码合约方法%s的返回值\n", goName, method.Sig)
+		g.printf("func (c *%s) Decode%s(contractRet string) (%s) {\n", t, goName, results)
+		g.printf(`values, err := c.abi.RawAbi().Methods[%q].Outputs.UnpackValues(common.FromHex(contractRet))
+	if err != nil {
+		return
+	}
+`, method.Name)
+		for i, output := range method.Outputs {
+			switch {
+			case isAddress(output.Type):
+				g.printf("out%d = bind.FromAddress(values[%d].(common.Address))\n", i, i)
+			case isAddressSlice(output.Type):
+				g.printf("out%d = bind.FromAddresses(values[%d].([]common.Address))\n", i, i)
+			case isAddressArray(output.Type):
+				g.printf("out%[1]dAddress := values[%[1]d].(%[2]s)\ncopy(out%[1]d[:], bind.FromAddresses(out%[1]dAddress[:]))\n", i, g.goType(output.Type))
+			default:
+				g.printf("out%[1]d = *gethabi.ConvertType(values[%[1]d], new(%[2]s)).(*%[2]s)\n", i, g.goType(output.Type))
+			}
+		}
+		g.printf("return\n}\n\n")
+	}
+
+	if method.IsConstant() {
+		g.printf("// %s 预执行合约的只读方法%s\n", goName, method.Sig)
+		g.printf("func (c *%s) %s(ctx context.Context, chainId, owner string%s) (%s) {\n", t, goName, prefixComma(params.decl), results)
+		g.printf(`data, err := c.Encode%s(%s)
+	if err != nil {
+		return
+	}
+`, goName, params.names)
+		if len(method.Outputs) == 0 {
+			g.printf("_, err = bind.Call(ctx, c.svc, chainId, owner, c.address, data)\nreturn\n}\n\n")
+			return nil
+		}
+		g.printf(`contractRet, err := bind.Call(ctx, c.svc, chainId, owner, c.address, data)
+	if err != nil {
+		return
+	}
+	return c.Decode%s(contractRet)
+}
+
+`, goName)
+		return nil
+	}
+
+	g.printf(`// %[1]s 发起调用合约方法%[2]s的交易并等待回执，交易的返回值可以通过 Decode%[1]s 解码回执的 ContractRet
+func (c *%[3]s) %[1]s(ctx context.Context, credentials *lattice.Credentials, chainId string, opts *bind.TransactOpts%[4]s) (*common.Hash, *types.Receipt, error) {
+	data, err := c.Encode%[1]s(%[5]s)
+	if err != nil {
+		return nil, nil, err
+	}
+	return bind.Transact(ctx, c.svc, credentials, chainId, c.address, data, opts)
+}
+
+`, goName, method.Sig, t, prefixComma(params.decl), params.names)
+	return nil
+}
+
+func (g *generator) event(event abi.Event) {
+	t := g.config.Type
+	goName := abi.ToCamelCase(event.Name)
+	g.printf("// %s%s 合约事件%s，indexed的动态类型参数为其哈希\n", t, goName, event.Sig)
+	g.printf("type %s%s struct {\n", t, goName)
+	for i, input := range event.Inputs {
+		g.printf("%s %s\n", eventFieldName(input, i), g.eventFieldType(input))
+	}
+	g.printf("Raw *types.Event // 原始事件\n}\n\n")
+
+	g.printf(`// Parse%[1]s 解码合约事件%[3]s，事件不是%[1]s时返回 types.ErrEventNotMatched
+func (c *%[2]s) Parse%[1]s(event *types.Event) (*%[2]s%[1]s, error) {
+	decoded, err := c.abi.DecodeEvent(event)
+	if err != nil {
+		return nil, err
+	}
+	if decoded.Name != %[4]q {
+		return nil, types.ErrEventNotMatched
+	}
+	out := &%[2]s%[1]s{Raw: event}
+`, goName, t, event.Sig, event.Name)
+	if !g.eventHasAddress(event) {
+		g.printf("if err := c.abi.BindEvent(event, out); err != nil {\nreturn nil, err\n}\nreturn out, nil\n}\n\n")
+		return
+	}
+
+	// 先绑定到ETH地址类型的字段，再转为zltc地址
+	g.printf("var raw struct {\n")
+	for i, input := range event.Inputs {
+		g.printf("%s %s\n", eventFieldName(input, i), g.eventRawType(input))
+	}
+	g.printf("}\nif err := c.abi.BindEvent(event, &raw); err != nil {\nreturn nil, err\n}\n")
+	for i, input := range event.Inputs {
+		fieldName := eventFieldName(input, i)
+		dst, src := "out."+fieldName, "raw."+fieldName
+		switch {
+		case input.Indexed && isHashedTopic(input.Type):
+			g.printf("%s = %s\n", dst, src)
+		case isAddress(input.Type):
+			g.printf("%s = bind.FromAddress(%s)\n", dst, src)
+		case isAddressSlice(input.Type):
+			g.printf("%s = bind.FromAddresses(%s)\n", dst, src)
+		case isAddressArray(input.Type):
+			g.printf("copy(%s[:], bind.FromAddresses(%s[:]))\n", dst, src)
+		default:
+			g.printf("%s = %s\n", dst, src)
+		}
+	}
+	g.printf("return out, nil\n}\n\n")
+}
+
+// eventHasAddress 事件是否有需要转为zltc地址的字段
+func (g *generator) eventHasAddress(event abi.Event) bool {
+	for _, input := range event.Inputs {
+		if g.eventFieldType(input) != g.eventRawType(input) {
+			return true
+		}
+	}
+	return false
+}
+
+// eventFieldName 事件字段名，参数未命名时为 Arg<index>
+func eventFieldName(input abi.Argument, index int) string {
+	if fieldName := abi.ToCamelCase(input.Name); fieldName != "" {
+		return fieldName
+	}
+	return fmt.Sprintf("Arg%d", index)
+}
+
+// params 方法参数的声明、转换和调用代码
+type params struct {
+	decl      string // 参数声明，如 to string, amount *big.Int
+	names     string // 参数名，如 to, amount
+	call      string // 调用Pack时的参数，如 , toAddress, amount
+	addresses []param
+}
+
+type param struct {
+	name string
+	t    abi.Type
+}
+
+func (g *generator) params(arguments abi.Arguments) *params {
+	decls := make([]string, len(arguments))
+	names := make([]string, len(arguments))
+	var call strings.Builder
+	result := &params{}
+	for i, argument := range arguments {
+		name := paramName(argument.Name, i)
+		decls[i] = fmt.Sprintf("%s %s", name, g.bindType(argument.Type))
+		names[i] = name
+		if isAddress(argument.Type) || isAddressSlice(argument.Type) || isAddressArray(argument.Type) {
+			result.addresses = append(result.addresses, param{name: name, t: argument.Type})
+			call.WriteString(", " + name + "Address")
+		} else {
+			call.WriteString(", " + name)
+		}
+	}
+	result.decl = strings.Join(decls, ", ")
+	result.names = strings.Join(names, ", ")
+	result.call = call.String()
+	return result
+}
+
+// convert 生成将zltc地址参数转为ETH地址的代码
+func (p *params) convert(g *generator, zero string) {
+	for _, address := range p.addresses {
+		switch {
+		case isAddress(address.t):
+			g.printf(`%[1]sAddress, err := bind.ToAddress(%[1]s)
+	if err != nil {
+		return %[2]s, err
+	}
+`, address.name, zero)
+		case isAddressSlice(address.t):
+			g.printf(`%[1]sAddress, err := bind.ToAddresses(%[1]s)
+	if err != nil {
+		return %[2]s, err
+	}
+`, address.name, zero)
+		default:
+			g.printf(`var %[1]sAddress %[3]s
+	%[1]sAddresses, err := bind.ToAddresses(%[1]s[:])
+	if err != nil {
+		return %[2]s, err
+	}
+	copy(%[1]sAddress[:], %[1]sAddresses)
+`, address.name, zero, g.goType(address.t))
+		}
+	}
+}
+
+// bindType 方法参数、返回值和事件字段的Go类型，address、address[] 和 address[N] 使用zltc地址字符串
+func (g *generator) bindType(t abi.Type) string {
+	switch {
+	case isAddress(t):
+		return "string"
+	case isAddressSlice(t):
+		return "[]string"
+	case isAddressArray(t):
+		return fmt.Sprintf("[%d]string", t.Size)
+	default:
+		return g.goType(t)
+	}
+}
+
+// eventFieldType 事件字段的Go类型，indexed的动态类型参数在topic中只保存了哈希
+func (g *generator) eventFieldType(argument abi.Argument) string {
+	if argument.Indexed && isHashedTopic(argument.Type) {
+		return "common.Hash"
+	}
+	return g.bindType(argument.Type)
+}
+
+// eventRawType 绑定事件时使用的Go类型，地址为 common.Address
+func (g *generator) eventRawType(argument abi.Argument) string {
+	if argument.Indexed && isHashedTopic(argument.Type) {
+		return "common.Hash"
+	}
+	return g.goType(argument.Type)
+}
+
+// isHashedTopic indexed参数在topic中是否只保存了哈希
+func isHashedTopic(t abi.Type) bool {
+	switch t.T {
+	case abi.StringTy, abi.BytesTy, abi.SliceTy, abi.ArrayTy, abi.TupleTy:
+		return true
+	}
+	return false
+}
+
+// goType ABI类型对应的Go类型，tuple生成具名的结构体
+func (g *generator) goType(t abi.Type) string {
+	switch t.T {
+	case abi.TupleTy:
+		return g.structType(t)
+	case abi.SliceTy:
+		return "[]" + g.goType(*t.Elem)
+	case abi.ArrayTy:
+		return fmt.Sprintf("[%d]%s", t.Size, g.goType(*t.Elem))
+	default:
+		return t.GetType().String()
+	}
+}
+
+// structType 生成tuple对应的结构体，字段名和tag与 go-ethereum 解码tuple得到的匿名结构体一致，以便类型转换，
+// 因此结构体中的地址字段保持为 common.Address
+func (g *generator) structType(t abi.Type) string {
+	signature := t.String()
+	if name, ok := g.structNames[signature]; ok {
+		return name
+	}
+	rawName := t.TupleRawName
+	if rawName == "" {
+		rawName = fmt.Sprintf("Tuple%d", len(g.structNames))
+	}
+	name := g.config.Type + abi.ToCamelCase(rawName)
+	g.structNames[signature] = name
+
+	fields := make([]string, len(t.TupleElems))
+	for i, elem := range t.TupleElems {
+		fields[i] = fmt.Sprintf("%s %s `json:\"%s\"`", abi.ToCamelCase(t.TupleRawNames[i]), g.goType(*elem), t.TupleRawNames[i])
+	}
+	comment := fmt.Sprintf("// %s 合约中的结构体%s\n", name, signature)
+	if strings.Contains(signature, "address") {
+		comment += "// 地址字段为 common.Address，可通过 bind.ToAddress 和 bind.FromAddress 与zltc地址互相转换\n"
+	}
+	_, _ = fmt.Fprintf(&g.structs, "%stype %s struct {\n%s\n}\n\n", comment, name, strings.Join(fields, "\n"))
+	return name
+}
+
+func isAddress(t abi.Type) bool {
+	return t.T == abi.AddressTy
+}
+
+func isAddressSlice(t abi.Type) bool {
+	return t.T == abi.SliceTy && t.Elem.T == abi.AddressTy
+}
+
+func isAddressArray(t abi.Type) bool {
+	return t.T == abi.ArrayTy && t.Elem.T == abi.AddressTy
+}
+
+// paramName 将ABI的参数名转为Go的参数名，参数未命名或与关键字、生成代码中的变量重名时加以区分
+func paramName(name string, index int) string {
+	if name == "" {
+		return fmt.Sprintf("arg%d", index)
+	}
+	camel := []rune(abi.ToCamelCase(name))
+	camel[0] = unicode.ToLower(camel[0])
+	goName := string(camel)
+	if token.IsKeyword(goName) || reservedParamNames[goName] || !token.IsIdentifier(goName) {
+		return goName + "_"
+	}
+	return goName
+}
+
+func prefixComma(s string) string {
+	if s == "" {
+		return ""
+	}
+	return ", " + s
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
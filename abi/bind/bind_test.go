@@ -0,0 +1,138 @@
+package bind
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generatedTokenTest 在生成的绑定代码所在的包中运行，验证编码、解码和地址转换
+const generatedTokenTest = `package gentest
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/LatticeBCLab/go-lattice/abi/bind"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+func TestGeneratedToken(t *testing.T) {
+	token := NewToken(nil, TokenAddress)
+	holder := "zltc_QLbz7JHiBTszrxNq8kcAzz8TYKsgttvgf"
+
+	if _, err := token.EncodeTransfer(holder, big.NewInt(1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := token.EncodeTransfer("zltc_invalid", big.NewInt(1)); err == nil {
+		t.Fatal("expected invalid address error")
+	}
+	if _, err := token.EncodeGrant(TokenTParams{AssetId: "a", Data: [][32]uint8{{1}}}, []string{holder}, "t"); err != nil {
+		t.Fatal(err)
+	}
+
+	params := TokenTParams{AssetId: "asset", User: common.HexToAddress("0x01"), Data: [][32]uint8{{2}}}
+	ret, err := token.MyAbi().Methods["getGrant"].Outputs.Pack(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := token.DecodeGetGrant(hexutil.Encode(ret))
+	if err != nil || decoded.AssetId != "asset" || decoded.User != params.User {
+		t.Fatalf("decode getGrant: %+v, %v", decoded, err)
+	}
+
+	ret, err = token.MyAbi().Methods["holders"].Outputs.Pack([]common.Address{common.HexToAddress("0x01")}, common.HexToAddress("0x02"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	holders, admin, err := token.DecodeHolders(hexutil.Encode(ret))
+	if err != nil || len(holders) != 1 || admin[:5] != "zltc_" {
+		t.Fatalf("decode holders: %v, %s, %v", holders, admin, err)
+	}
+
+	value, _ := token.MyAbi().Events["Transfer"].Inputs.NonIndexed().Pack(big.NewInt(9))
+	event := &types.Event{Topics: []common.Hash{token.MyAbi().Events["Transfer"].ID, common.BytesToHash(common.HexToAddress("0x03").Bytes()), {}}, Data: value}
+	transfer, err := token.ParseTransfer(event)
+	if err != nil || transfer.Value.Int64() != 9 || transfer.From != bind.FromAddress(common.HexToAddress("0x03")) {
+		t.Fatalf("parse transfer: %+v, %v", transfer, err)
+	}
+
+	pair := [2]common.Address{common.HexToAddress("0x04"), common.HexToAddress("0x05")}
+	value, _ = token.MyAbi().Events["Approval"].Inputs.NonIndexed().Pack(common.HexToAddress("0x06"), []common.Address{common.HexToAddress("0x07")}, pair)
+	event = &types.Event{Topics: []common.Hash{token.MyAbi().Events["Approval"].ID, common.BytesToHash(common.HexToAddress("0x03").Bytes())}, Data: value}
+	approval, err := token.ParseApproval(event)
+	if err != nil || approval.Owner != bind.FromAddress(common.HexToAddress("0x03")) || approval.Spender != bind.FromAddress(common.HexToAddress("0x06")) ||
+		len(approval.Delegates) != 1 || approval.Pair[1] != bind.FromAddress(pair[1]) {
+		t.Fatalf("parse approval: %+v, %v", approval, err)
+	}
+
+	if _, err := token.EncodePair([2]string{holder, "zltc_invalid"}); err == nil {
+		t.Fatal("expected invalid address error")
+	}
+	encoded, err := token.EncodePair([2]string{holder, bind.FromAddress(pair[1])})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret, err = token.MyAbi().Methods["pair"].Outputs.Pack(pair)
+	if err != nil {
+		t.Fatal(err)
+	}
+	members, err := token.DecodePair(hexutil.Encode(ret))
+	if err != nil || members[0] != bind.FromAddress(pair[0]) || encoded[len(encoded)-40:] != common.Bytes2Hex(pair[1].Bytes()) {
+		t.Fatalf("decode pair: %v, %s, %v", members, encoded, err)
+	}
+
+	if data, err := EncodeDeployToken(holder, "name"); err != nil || data[:6] != "0x6080" {
+		t.Fatalf("encode deploy: %s, %v", data, err)
+	}
+}
+`
+
+func TestGenerate(t *testing.T) {
+	abiJson, err := os.ReadFile("testdata/token.abi")
+	require.NoError(t, err)
+	source, err := Generate(&Config{Package: "gentest", Type: "Token", Abi: string(abiJson), Bytecode: "6080", Address: "zltc_QLbz7JHiBTszrxNq8kcAzz8TYKsgttvgf"})
+	require.NoError(t, err)
+	assert.Contains(t, string(source), "func (c *Token) BalanceOf(ctx context.Context, chainId, owner string, owner_ string) (out0 *big.Int, err error)")
+	assert.Contains(t, string(source), "func (c *Token) Transfer(ctx context.Context, credentials *lattice.Credentials, chainId string, opts *bind.TransactOpts, to string, amount *big.Int)")
+	assert.Contains(t, string(source), "type TokenTParams struct")
+
+	if testing.Short() {
+		t.Skip("跳过编译生成的代码")
+	}
+	dir, err := os.MkdirTemp(".", "gentest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "token.go"), source, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "token_test.go"), []byte(generatedTokenTest), 0o644))
+
+	output, err := exec.Command("go", "test", "-count=1", "./"+dir).CombinedOutput()
+	require.NoError(t, err, string(output))
+}
+
+func TestGenerateInvalidConfig(t *testing.T) {
+	_, err := Generate(&Config{Package: "gentest", Type: "token", Abi: "[]"})
+	assert.Error(t, err)
+	_, err = Generate(&Config{Package: "gentest", Type: "Token", Abi: "not json"})
+	assert.Error(t, err)
+}
+
+func TestToAddress(t *testing.T) {
+	address, err := ToAddress("zltc_QLbz7JHiBTszrxNq8kcAzz8TYKsgttvgf")
+	require.NoError(t, err)
+	assert.Equal(t, "zltc_QLbz7JHiBTszrxNq8kcAzz8TYKsgttvgf", FromAddress(address))
+
+	address, err = ToAddress("0x9293c604c644bfac34f498998cc3402f203d4d6b")
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToAddress("0x9293c604c644bfac34f498998cc3402f203d4d6b"), address)
+
+	_, err = ToAddresses([]string{"zltc_QLbz7JHiBTszrxNq8kcAzz8TYKsgttvgf", "invalid"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,139 @@
+package bind
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/LatticeBCLab/go-lattice/common/constant"
+	"github.com/LatticeBCLab/go-lattice/common/convert"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TransactOpts 生成的绑定代码发起交易时的可选参数，为nil时使用默认值
+//
+//   - Payload       交易备注，为空时使用 constant.ZeroPayload
+//   - Amount        转账金额，调用payable方法时使用
+//   - Joule         交易手续费
+//   - RetryStrategy 等待回执的策略，为nil时使用 lattice.DefaultBackOffRetryStrategy
+type TransactOpts struct {
+	Payload       string
+	Amount        uint64
+	Joule         uint64
+	RetryStrategy *lattice.RetryStrategy
+}
+
+func (opts *TransactOpts) normalize() *TransactOpts {
+	normalized := &TransactOpts{}
+	if opts != nil {
+		*normalized = *opts
+	}
+	if normalized.Payload == "" {
+		normalized.Payload = constant.ZeroPayload
+	}
+	if normalized.RetryStrategy == nil {
+		normalized.RetryStrategy = lattice.DefaultBackOffRetryStrategy()
+	}
+	return normalized
+}
+
+// Transact 发起调用合约的交易并等待回执，供生成的绑定代码使用
+//
+// Parameters:
+//   - ctx context.Context
+//   - svc lattice.Lattice
+//   - credentials *lattice.Credentials
+//   - chainId, contractAddress, data string
+//   - opts *TransactOpts
+//
+// Returns:
+//   - *common.Hash
+//   - *types.Receipt
+//   - error
+func Transact(ctx context.Context, svc lattice.Lattice, credentials *lattice.Credentials, chainId, contractAddress, data string, opts *TransactOpts) (*common.Hash, *types.Receipt, error) {
+	opts = opts.normalize()
+	return svc.CallContractWaitReceipt(ctx, credentials, chainId, contractAddress, data, opts.Payload, opts.Amount, opts.Joule, opts.RetryStrategy)
+}
+
+// Deploy 发起部署合约的交易并等待回执，回执的 ContractAddress 为合约地址，供生成的绑定代码使用
+//
+// Parameters:
+//   - ctx context.Context
+//   - svc lattice.Lattice
+//   - credentials *lattice.Credentials
+//   - chainId, data string: data 为合约字节码加上编码后的构造函数参数
+//   - opts *TransactOpts
+//
+// Returns:
+//   - *common.Hash
+//   - *types.Receipt
+//   - error
+func Deploy(ctx context.Context, svc lattice.Lattice, credentials *lattice.Credentials, chainId, data string, opts *TransactOpts) (*common.Hash, *types.Receipt, error) {
+	opts = opts.normalize()
+	return svc.DeployContractWaitReceipt(ctx, credentials, chainId, data, opts.Payload, opts.Amount, opts.Joule, opts.RetryStrategy)
+}
+
+// Call 预执行合约的只读方法，返回回执的 ContractRet，供生成的绑定代码使用
+//
+// Parameters:
+//   - ctx context.Context
+//   - svc lattice.Lattice
+//   - chainId, owner, contractAddress, data string
+//
+// Returns:
+//   - string: ContractRet
+//   - error
+func Call(ctx context.Context, svc lattice.Lattice, chainId, owner, contractAddress, data string) (string, error) {
+	receipt, err := svc.PreCallContract(ctx, chainId, owner, contractAddress, data, constant.ZeroPayload)
+	if err != nil {
+		return "", err
+	}
+	return receipt.ContractRet, nil
+}
+
+// ToAddress 将合约的address参数转为ETH地址，支持zltc地址和0x地址
+//
+// Parameters:
+//   - address string: zltc_dhdfbm9JEoyDvYoCDVsABiZj52TAo9Ei6 或 0x9293c604c644bfac34f498998cc3402f203d4d6b
+//
+// Returns:
+//   - common.Address
+//   - error
+func ToAddress(address string) (common.Address, error) {
+	if strings.HasPrefix(address, types.AddressTitle+"_") {
+		return convert.ZltcToAddress(address)
+	}
+	if !common.IsHexAddress(address) {
+		return common.Address{}, fmt.Errorf("invalid address: %s", address)
+	}
+	return common.HexToAddress(address), nil
+}
+
+// ToAddresses 将合约的address[]参数转为ETH地址
+func ToAddresses(addresses []string) ([]common.Address, error) {
+	result := make([]common.Address, len(addresses))
+	for i, address := range addresses {
+		converted, err := ToAddress(address)
+		if err != nil {
+			return nil, err
+		}
+		result[i] = converted
+	}
+	return result, nil
+}
+
+// FromAddress 将合约返回的address转为zltc地址
+func FromAddress(address common.Address) string {
+	return convert.AddressToZltc(address)
+}
+
+// FromAddresses 将合约返回的address[]转为zltc地址
+func FromAddresses(addresses []common.Address) []string {
+	result := make([]string, len(addresses))
+	for i, address := range addresses {
+		result[i] = convert.AddressToZltc(address)
+	}
+	return result
+}
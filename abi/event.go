@@ -0,0 +1,110 @@
+package abi
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func (i *latticeAbi) DecodeEvent(event *types.Event) (*types.DecodedEvent, error) {
+	return DecodeEvent(i.abi, event)
+}
+
+func (i *latticeAbi) BindEvent(event *types.Event, out interface{}) error {
+	return BindEvent(i.abi, event, out)
+}
+
+// DecodeEvent 解码合约事件，非匿名事件通过 Topics[0] 匹配事件签名，匿名事件依次尝试ABI中参数数量一致的匿名事件
+//
+// Parameters:
+//   - myabi *abi.ABI
+//   - event *types.Event: 回执中或订阅得到的事件
+//
+// Returns:
+//   - *types.DecodedEvent
+//   - error: 事件与ABI不匹配时为 types.ErrEventNotMatched
+func DecodeEvent(myabi *abi.ABI, event *types.Event) (*types.DecodedEvent, error) {
+	if myabi == nil || event == nil {
+		return nil, types.ErrEventNotMatched
+	}
+	data := event.GetData()
+
+	if len(event.Topics) > 0 {
+		if abiEvent, err := myabi.EventByID(event.Topics[0]); err == nil && !abiEvent.Anonymous {
+			args, err := unpackEvent(abiEvent, event.Topics[1:], data)
+			if err != nil {
+				return nil, fmt.Errorf("解码合约事件【%s】失败：%w", abiEvent.Name, err)
+			}
+			return &types.DecodedEvent{Name: abiEvent.Name, Address: event.Address, Args: args, Event: event}, nil
+		}
+	}
+
+	for _, abiEvent := range myabi.Events {
+		if !abiEvent.Anonymous {
+			continue
+		}
+		if args, err := unpackEvent(&abiEvent, event.Topics, data); err == nil {
+			return &types.DecodedEvent{Name: abiEvent.Name, Address: event.Address, Args: args, Event: event}, nil
+		}
+	}
+	return nil, types.ErrEventNotMatched
+}
+
+// BindEvent 解码合约事件并绑定到结构体，参数名转为驼峰后与结构体的字段名匹配，没有对应字段的参数会被忽略
+//
+// Parameters:
+//   - myabi *abi.ABI
+//   - event *types.Event
+//   - out interface{}: 结构体指针
+//
+// Returns:
+//   - error
+func BindEvent(myabi *abi.ABI, event *types.Event, out interface{}) error {
+	value := reflect.ValueOf(out)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("绑定合约事件需要结构体指针，实际为%T", out)
+	}
+	decodedEvent, err := DecodeEvent(myabi, event)
+	if err != nil {
+		return err
+	}
+
+	for name, arg := range decodedEvent.Args {
+		field := value.Elem().FieldByName(abi.ToCamelCase(name))
+		if !field.IsValid() || !field.CanSet() {
+			continue
+		}
+		argValue := reflect.ValueOf(arg)
+		switch {
+		case argValue.Type().AssignableTo(field.Type()):
+			field.Set(argValue)
+		case argValue.Type().ConvertibleTo(field.Type()):
+			field.Set(argValue.Convert(field.Type()))
+		default:
+			return fmt.Errorf("合约事件【%s】的参数【%s】的类型%s无法绑定到字段类型%s", decodedEvent.Name, name, argValue.Type(), field.Type())
+		}
+	}
+	return nil
+}
+
+// unpackEvent 解码事件的indexed参数和非indexed参数，topics不包含事件签名
+func unpackEvent(abiEvent *abi.Event, topics []common.Hash, data []byte) (map[string]interface{}, error) {
+	var indexed abi.Arguments
+	for _, input := range abiEvent.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+
+	args := make(map[string]interface{}, len(abiEvent.Inputs))
+	if err := abiEvent.Inputs.UnpackIntoMap(args, data); err != nil {
+		return nil, err
+	}
+	if err := abi.ParseTopicsIntoMap(args, indexed, topics); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
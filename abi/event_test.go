@@ -0,0 +1,96 @@
+package abi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	tokenEventAbi  = "[{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"},{\"anonymous\":true,\"inputs\":[{\"indexed\":true,\"internalType\":\"uint256\",\"name\":\"id\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"string\",\"name\":\"note\",\"type\":\"string\"}],\"name\":\"Noted\",\"type\":\"event\"}]"
+	ledgerEventAbi = "[{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"dataId\",\"type\":\"bytes32\"},{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"isNew\",\"type\":\"bool\"}],\"name\":\"Written\",\"type\":\"event\"}]"
+)
+
+func TestDecodeEvent(t *testing.T) {
+	tokenAbi := NewAbi(tokenEventAbi)
+	ledgerAbi := NewAbi(ledgerEventAbi)
+	from := common.HexToAddress("0x01")
+	to := common.HexToAddress("0x02")
+
+	value, err := tokenAbi.RawAbi().Events["Transfer"].Inputs.NonIndexed().Pack(big.NewInt(100))
+	require.NoError(t, err)
+	transfer := &types.Event{
+		Address: "zltc_token",
+		Topics:  []common.Hash{tokenAbi.RawAbi().Events["Transfer"].ID, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+		Data:    value,
+	}
+	note, err := tokenAbi.RawAbi().Events["Noted"].Inputs.NonIndexed().Pack("备注")
+	require.NoError(t, err)
+	noted := &types.Event{Address: "zltc_token", Topics: []common.Hash{common.BigToHash(big.NewInt(7))}, DataHex: hexutil.Encode(note)}
+	isNew, err := ledgerAbi.RawAbi().Events["Written"].Inputs.NonIndexed().Pack(true)
+	require.NoError(t, err)
+	written := &types.Event{
+		Address: "zltc_ledger",
+		Topics:  []common.Hash{ledgerAbi.RawAbi().Events["Written"].ID, common.HexToHash("0xabcd")},
+		Data:    isNew,
+	}
+
+	t.Run("map", func(t *testing.T) {
+		decoded, err := tokenAbi.DecodeEvent(transfer)
+		require.NoError(t, err)
+		assert.Equal(t, "Transfer", decoded.Name)
+		assert.Equal(t, from, decoded.Args["from"])
+		assert.Equal(t, to, decoded.Args["to"])
+		assert.Equal(t, big.NewInt(100), decoded.Args["value"])
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		decoded, err := tokenAbi.DecodeEvent(noted)
+		require.NoError(t, err)
+		assert.Equal(t, "Noted", decoded.Name)
+		assert.Equal(t, big.NewInt(7), decoded.Args["id"])
+		assert.Equal(t, "备注", decoded.Args["note"])
+	})
+
+	t.Run("bind", func(t *testing.T) {
+		var out struct {
+			From  common.Address
+			To    common.Address
+			Value *big.Int
+		}
+		require.NoError(t, tokenAbi.BindEvent(transfer, &out))
+		assert.Equal(t, from, out.From)
+		assert.Equal(t, int64(100), out.Value.Int64())
+	})
+
+	t.Run("not matched", func(t *testing.T) {
+		_, err := tokenAbi.DecodeEvent(written)
+		assert.ErrorIs(t, err, types.ErrEventNotMatched)
+	})
+
+	t.Run("receipt with multiple contracts", func(t *testing.T) {
+		receipt := &types.Receipt{Events: []*types.Event{transfer, written, {Topics: []common.Hash{common.HexToHash("0xff")}}}}
+		decoded := receipt.DecodeEvents(map[string]types.EventDecoder{"zltc_token": tokenAbi, "zltc_ledger": ledgerAbi})
+		require.Len(t, decoded, 2)
+		assert.Equal(t, "Transfer", decoded[0].Name)
+		assert.Equal(t, "Written", decoded[1].Name)
+		assert.Equal(t, "zltc_ledger", decoded[1].Address)
+		assert.Equal(t, true, decoded[1].Args["isNew"])
+	})
+	t.Run("receipt keyed by contract address", func(t *testing.T) {
+		// 其它合约的匿名事件不会被 tokenAbi 解码
+		foreignNoted := &types.Event{Address: "zltc_other", Topics: noted.Topics, DataHex: noted.DataHex}
+		// indexed参数数量与ABI不一致的同名事件解码失败时视为不匹配
+		malformed := &types.Event{Address: "zltc_token", Topics: transfer.Topics[:2], Data: transfer.Data}
+		receipt := &types.Receipt{Events: []*types.Event{foreignNoted, malformed, transfer}}
+		decoded := receipt.DecodeEvents(map[string]types.EventDecoder{"zltc_token": tokenAbi, "zltc_ledger": ledgerAbi})
+		require.Len(t, decoded, 1)
+		assert.Equal(t, "Transfer", decoded[0].Name)
+		assert.Same(t, transfer, decoded[0].Event)
+	})
+}
@@ -0,0 +1,136 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	// errorSelector Error(string) 的函数选择器
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+	// panicSelector Panic(uint256) 的函数选择器
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// panicReasons Solidity Panic(uint256) 的错误码
+var panicReasons = map[uint64]string{
+	0x00: "编译器插入的通用panic",
+	0x01: "assert断言失败",
+	0x11: "算术运算溢出",
+	0x12: "除数或模数为0",
+	0x21: "无效的枚举值",
+	0x22: "存储中的字节数组编码错误",
+	0x31: "对空数组执行pop",
+	0x32: "数组越界",
+	0x41: "内存分配过大",
+	0x51: "调用未初始化的内部函数",
+}
+
+// ContractRevertError 合约执行失败的原因，由回执的 ContractRet 解码得到
+//
+//   - Name    错误名称，Error(string) 为 Error，Panic(uint256) 为 Panic，自定义错误为ABI中的名称，无法解码时为空
+//   - Message 可读的错误信息
+//   - Args    解码后的参数，key为参数名，参数未命名时为参数的下标
+//   - Data    原始的 ContractRet
+type ContractRevertError struct {
+	Name    string
+	Message string
+	Args    map[string]interface{}
+	Data    string
+}
+
+func (e *ContractRevertError) Error() string {
+	return fmt.Sprintf("合约执行失败：%s", e.Message)
+}
+
+// DecodeRevertError 解码合约执行失败时的 ContractRet，依次尝试 Error(string)、Panic(uint256) 和ABI中的自定义错误
+//
+// Parameters:
+//   - myabi *abi.ABI: 被调用合约的ABI，为nil时只解码 Error(string) 和 Panic(uint256)
+//   - contractRet string: 回执中带0x前缀的 ContractRet
+//
+// Returns:
+//   - *ContractRevertError: 无法解码时 Name 为空，Message 为原始数据
+func DecodeRevertError(myabi *abi.ABI, contractRet string) *ContractRevertError {
+	revertErr := &ContractRevertError{Data: contractRet, Message: contractRet}
+	if contractRet == "" || contractRet == "0x" {
+		revertErr.Message = "未返回错误信息"
+		return revertErr
+	}
+	data, err := hexutil.Decode(contractRet)
+	if err != nil || len(data) < 4 {
+		return revertErr
+	}
+	selector, payload := data[:4], data[4:]
+
+	switch {
+	case bytes.Equal(selector, errorSelector):
+		values, err := abi.Arguments{{Name: "reason", Type: mustNewType("string")}}.UnpackValues(payload)
+		if err != nil {
+			return revertErr
+		}
+		revertErr.Name = "Error"
+		revertErr.Message = values[0].(string)
+		revertErr.Args = map[string]interface{}{"reason": values[0]}
+	case bytes.Equal(selector, panicSelector):
+		values, err := abi.Arguments{{Name: "code", Type: mustNewType("uint256")}}.UnpackValues(payload)
+		if err != nil {
+			return revertErr
+		}
+		code := values[0].(*big.Int)
+		reason, ok := panicReasons[code.Uint64()]
+		if !ok || !code.IsUint64() {
+			reason = "未知的panic错误码"
+		}
+		revertErr.Name = "Panic"
+		revertErr.Message = fmt.Sprintf("panic 0x%x：%s", code, reason)
+		revertErr.Args = map[string]interface{}{"code": code}
+	case myabi != nil:
+		for _, abiErr := range myabi.Errors {
+			if !bytes.Equal(abiErr.ID[:4], selector) {
+				continue
+			}
+			values, err := abiErr.Inputs.UnpackValues(payload)
+			if err != nil {
+				return revertErr
+			}
+			revertErr.Name = abiErr.Name
+			revertErr.Args = make(map[string]interface{}, len(values))
+			formatted := make([]string, len(values))
+			for i, v := range values {
+				name := abiErr.Inputs[i].Name
+				if name == "" {
+					name = strconv.Itoa(i)
+				}
+				revertErr.Args[name] = v
+				formatted[i] = fmt.Sprintf("%s=%s", name, formatRevertArg(v))
+			}
+			revertErr.Message = fmt.Sprintf("%s(%s)", abiErr.Name, strings.Join(formatted, ", "))
+			break
+		}
+	}
+	return revertErr
+}
+
+func formatRevertArg(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func mustNewType(t string) abi.Type {
+	ty, err := abi.NewType(t, "", nil)
+	if err != nil {
+		panic(err)
+	}
+	return ty
+}
@@ -0,0 +1,50 @@
+package abi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const revertTestAbi = "[{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"available\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"required\",\"type\":\"uint256\"}],\"name\":\"InsufficientBalance\",\"type\":\"error\"}]"
+
+func TestDecodeRevertError(t *testing.T) {
+	myabi := FromJson(revertTestAbi)
+
+	t.Run("Error(string)", func(t *testing.T) {
+		payload, err := abi.Arguments{{Type: mustNewType("string")}}.Pack("余额不足")
+		require.NoError(t, err)
+		revertErr := DecodeRevertError(nil, hexutil.Encode(append(errorSelector, payload...)))
+		assert.Equal(t, "Error", revertErr.Name)
+		assert.Equal(t, "余额不足", revertErr.Message)
+		assert.Equal(t, "合约执行失败：余额不足", revertErr.Error())
+	})
+
+	t.Run("Panic(uint256)", func(t *testing.T) {
+		payload, err := abi.Arguments{{Type: mustNewType("uint256")}}.Pack(big.NewInt(0x11))
+		require.NoError(t, err)
+		revertErr := DecodeRevertError(nil, hexutil.Encode(append(panicSelector, payload...)))
+		assert.Equal(t, "Panic", revertErr.Name)
+		assert.Equal(t, "panic 0x11：算术运算溢出", revertErr.Message)
+	})
+
+	t.Run("custom error", func(t *testing.T) {
+		abiErr := myabi.Errors["InsufficientBalance"]
+		payload, err := abiErr.Inputs.Pack(big.NewInt(1), big.NewInt(2))
+		require.NoError(t, err)
+		revertErr := DecodeRevertError(myabi, hexutil.Encode(append(abiErr.ID[:4], payload...)))
+		assert.Equal(t, "InsufficientBalance", revertErr.Name)
+		assert.Equal(t, "InsufficientBalance(available=1, required=2)", revertErr.Message)
+		assert.Equal(t, big.NewInt(2), revertErr.Args["required"])
+	})
+
+	t.Run("undecodable", func(t *testing.T) {
+		revertErr := DecodeRevertError(myabi, "0x01020304")
+		assert.Empty(t, revertErr.Name)
+		assert.Equal(t, "0x01020304", revertErr.Message)
+	})
+}
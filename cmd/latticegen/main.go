@@ -0,0 +1,77 @@
+// latticegen 根据合约的ABI生成类型化的Go绑定代码
+//
+// Usage:
+//
+//	latticegen -abi Token.abi [-bin Token.bin] [-address zltc_...] -pkg token -type Token [-out token.go]
+//
+// 配合 go:generate 使用：
+//
+//	//go:generate go run github.com/LatticeBCLab/go-lattice/cmd/latticegen -abi Token.abi -pkg token -type Token -out token.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/LatticeBCLab/go-lattice/abi/bind"
+)
+
+func main() {
+	abiFile := flag.String("abi", "", "合约ABI的JSON文件，为 - 时从标准输入读取")
+	binFile := flag.String("bin", "", "合约字节码文件，指定时生成部署合约的方法")
+	address := flag.String("address", "", "合约地址，指定时生成合约地址常量")
+	pkg := flag.String("pkg", "", "生成代码的包名")
+	typeName := flag.String("type", "", "绑定的类型名，如 Token")
+	out := flag.String("out", "", "输出文件，为空时输出到标准输出")
+	flag.Parse()
+
+	if err := run(*abiFile, *binFile, *address, *pkg, *typeName, *out); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "latticegen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(abiFile, binFile, address, pkg, typeName, out string) error {
+	if abiFile == "" || pkg == "" || typeName == "" {
+		flag.Usage()
+		return fmt.Errorf("-abi、-pkg 和 -type 参数不能为空")
+	}
+	abiJson, err := readFile(abiFile)
+	if err != nil {
+		return err
+	}
+	var bytecode string
+	if binFile != "" {
+		if bytecode, err = readFile(binFile); err != nil {
+			return err
+		}
+	}
+
+	source, err := bind.Generate(&bind.Config{
+		Package:  pkg,
+		Type:     typeName,
+		Abi:      strings.TrimSpace(abiJson),
+		Bytecode: strings.TrimSpace(bytecode),
+		Address:  address,
+	})
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(source)
+		return err
+	}
+	return os.WriteFile(out, source, 0o644)
+}
+
+func readFile(name string) (string, error) {
+	if name == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		return string(data), err
+	}
+	data, err := os.ReadFile(name)
+	return string(data), err
+}
@@ -85,6 +85,7 @@ type TransactionBlock struct {
 	Amount     string      `json:"amount"`
 	Joule      uint64      `json:"joule"`
 	Sign       string      `json:"sign"`
+	Difficulty uint64      `json:"difficulty"`
 	Pow        string      `json:"proofOfWork"`
 	Size       uint64      `json:"size"`
 }
@@ -1,9 +1,11 @@
 package types
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/rs/zerolog/log"
 )
 
@@ -43,3 +45,75 @@ type Event struct {
 	Removed      bool          `json:"removed"`
 	DataHex      string        `json:"dataHex"`
 }
+
+// ErrEventNotMatched 事件与ABI中的任何事件都不匹配
+var ErrEventNotMatched = errors.New("事件与ABI中的事件不匹配")
+
+// GetData 获取事件的非indexed数据，Data 为空时使用 DataHex
+func (e *Event) GetData() []byte {
+	if len(e.Data) > 0 || e.DataHex == "" {
+		return e.Data
+	}
+	data, err := hexutil.Decode(e.DataHex)
+	if err != nil {
+		log.Error().Err(err).Msgf("解析事件的DataHex的值%s失败", e.DataHex)
+		return nil
+	}
+	return data
+}
+
+// DecodedEvent 使用ABI解码后的合约事件
+//
+//   - Name    事件名称
+//   - Address 发出事件的合约地址
+//   - Args    事件参数，包括indexed和非indexed参数，string、bytes等动态类型的indexed参数只能得到其哈希
+//   - Event   原始事件
+type DecodedEvent struct {
+	Name    string
+	Address string
+	Args    map[string]interface{}
+	Event   *Event
+}
+
+// EventDecoder 合约事件的解码器，abi.LatticeAbi 实现了该接口
+type EventDecoder interface {
+	// DecodeEvent 解码事件，事件与ABI不匹配时返回 ErrEventNotMatched
+	DecodeEvent(event *Event) (*DecodedEvent, error)
+}
+
+// DecodeEvents 按发出事件的合约地址选择ABI解码回执中的事件，没有对应ABI或与ABI不匹配的事件会被忽略
+//
+// 不同合约的同名事件可能有相同的topic0（如ERC20和ERC721的Transfer），匿名事件没有topic0，
+// 所以只使用 Event.Address 对应的ABI解码，不会将事件归属到其它合约
+//
+// Parameters:
+//   - decoders map[string]EventDecoder: 合约地址 -> 合约的ABI，如 abi.NewAbi(abiString)，地址格式与 Event.Address 一致
+//
+// Returns:
+//   - []*DecodedEvent: 按事件在回执中的顺序排列
+func (r *Receipt) DecodeEvents(decoders map[string]EventDecoder) []*DecodedEvent {
+	decodedEvents := make([]*DecodedEvent, 0, len(r.Events))
+	for _, event := range r.Events {
+		decoder, ok := decoders[event.Address]
+		if !ok {
+			continue
+		}
+		decodedEvent, err := decoder.DecodeEvent(event)
+		if err != nil {
+			if !errors.Is(err, ErrEventNotMatched) {
+				log.Debug().Err(err).Msgf("解码合约%s的事件失败，忽略该事件，logIndex: %d", event.Address, event.Index)
+			}
+			continue
+		}
+		decodedEvents = append(decodedEvents, decodedEvent)
+	}
+	return decodedEvents
+}
+
+// LogsSubscribeCondition 合约日志订阅条件
+type LogsSubscribeCondition struct {
+	// 合约地址，为空时订阅所有合约的日志
+	Addresses []string `json:"address,omitempty"`
+	// 按位置匹配的事件主题，某个位置为空时匹配任意主题，有多个时匹配其中任意一个
+	Topics [][]common.Hash `json:"topics,omitempty"`
+}
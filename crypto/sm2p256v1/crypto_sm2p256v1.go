@@ -193,13 +193,60 @@ func (i *GmApi) Sign(hash []byte, sk *ecdsa.PrivateKey) (signature []byte, err e
 	return signature, nil
 }
 
-// SignatureToPK 从签名恢复公钥
+// SignatureToPK 从签名恢复公钥，签名的后32个字节为签名时的摘要e，用于从候选公钥中确定签名者的公钥
 func (i *GmApi) SignatureToPK(hash, signature []byte) (*ecdsa.PublicKey, error) {
+	if len(signature) != 97 {
+		return nil, fmt.Errorf("signature is required to be exactly 97 bytes (%d)", len(signature))
+	}
+	curve := i.GetCurve()
+	params := curve.Params()
+	r := new(big.Int).SetBytes(signature[:32])
+	s := new(big.Int).SetBytes(signature[32:64])
 	e := new(big.Int).SetBytes(signature[65:])
-	signature = signature[:65]
+	if r.Sign() == 0 || r.Cmp(params.N) >= 0 || s.Sign() == 0 || s.Cmp(params.N) >= 0 {
+		return nil, errors.New("invalid signature")
+	}
+	// s = (1 + d)^-1 * (k - r * d)  =>  P = (s + r)^-1 * (kG - sG)
+	sr := new(big.Int).Add(s, r)
+	sr.Mod(sr, params.N)
+	if sr.Sign() == 0 {
+		return nil, errors.New("invalid signature")
+	}
+	srInv := new(big.Int).ModInverse(sr, params.N)
+	negSx, negSy := curve.ScalarBaseMult(new(big.Int).Sub(params.N, s).Bytes())
+
+	// r = (e + x1) mod n，kG = (x1, y1)
+	x1 := new(big.Int).Sub(r, e)
+	x1.Mod(x1, params.N)
+	for ; x1.Cmp(params.P) < 0; x1 = new(big.Int).Add(x1, params.N) {
+		y1 := sm2CurveY(params, x1)
+		if y1 == nil {
+			continue
+		}
+		for _, y := range []*big.Int{y1, new(big.Int).Sub(params.P, y1)} {
+			x, y := curve.Add(x1, y, negSx, negSy)
+			x, y = curve.ScalarMult(x, y, srInv.Bytes())
+			pk := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+			// 摘要e由签名者的公钥计算得出，只有正确的公钥能计算出相同的e
+			digest, err := convert.EcdsaPKToSm2PK(pk).Sm3Digest(hash, nil)
+			if err == nil && new(big.Int).SetBytes(digest).Cmp(e) == 0 && i.Verify(hash, signature, pk) {
+				return pk, nil
+			}
+		}
+	}
+	return nil, errors.New("failed to recover public key from signature")
+}
 
-	fmt.Println(e)
-	return nil, nil
+// sm2CurveY 计算曲线上横坐标为x的点的纵坐标，不存在时返回nil
+func sm2CurveY(params *elliptic.CurveParams, x *big.Int) *big.Int {
+	// y^2 = x^3 - 3x + b
+	y2 := new(big.Int).Exp(x, big.NewInt(3), params.P)
+	threeX := new(big.Int).Lsh(x, 1)
+	threeX.Add(threeX, x)
+	y2.Sub(y2, threeX)
+	y2.Add(y2, params.B)
+	y2.Mod(y2, params.P)
+	return new(big.Int).ModSqrt(y2, params.P)
 }
 
 // Verify 验证签名
@@ -74,3 +74,24 @@ func TestSm2p256v1Api_PKToAddress(t *testing.T) {
 	assert.Len(t, addr, 20)
 	// 0x04376db6870f8ca937c94a49761db33fc71c5de643f07cb1501504644ef86360f7fb7974b11058c76a56c03bee897c0b5f640613cb6a3ff41fb23426d2b5e17cbb
 }
+
+func TestSm2p256v1Api_SignatureToPK(t *testing.T) {
+	crypto := New()
+	sk, err := crypto.GenerateKeyPair()
+	assert.NoError(t, err)
+	hash := crypto.Hash([]byte("lattice"))
+	for range 10 {
+		signature, err := crypto.Sign(hash.Bytes(), sk)
+		assert.NoError(t, err)
+		pk, err := crypto.SignatureToPK(hash.Bytes(), signature)
+		assert.NoError(t, err)
+		assert.True(t, sk.PublicKey.Equal(pk))
+	}
+
+	signature, err := crypto.Sign(hash.Bytes(), sk)
+	assert.NoError(t, err)
+	pk, err := crypto.SignatureToPK(crypto.Hash([]byte("tampered")).Bytes(), signature)
+	if err == nil {
+		assert.False(t, sk.PublicKey.Equal(pk))
+	}
+}
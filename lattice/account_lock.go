@@ -1,16 +1,35 @@
 package lattice
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// NewAccountLock 初始化进程内的账户锁
+//
+// 每个账户的锁按引用计数维护，最后一个持有或等待者释放后即删除，不会随账户数量增长而泄漏内存。
+//
+// Returns:
+//   - AccountLock
 func NewAccountLock() AccountLock {
-	return &accountLock{}
+	return &accountLock{
+		locks: make(map[string]*accountMutex),
+	}
+}
+
+// accountMutex 支持context取消的互斥锁，refs为正在持有和等待该锁的数量
+type accountMutex struct {
+	ch   chan struct{}
+	refs int
 }
 
 type accountLock struct {
-	locks sync.Map
+	mu      sync.Mutex               // 保护locks
+	locks   map[string]*accountMutex // key: chainId_address
+	metrics lockMetrics              // 锁等待的统计指标
 }
 
 type AccountLock interface {
@@ -21,25 +40,156 @@ type AccountLock interface {
 	//   - address string: 账户地址
 	Obtain(chainId, address string)
 
+	// ObtainContext 获取账户锁，ctx取消或超时时放弃等待
+	//
+	// Parameters:
+	//   - ctx context.Context
+	//   - chainId string: 链ID
+	//   - address string: 账户地址
+	//
+	// Returns:
+	//   - error: ctx取消或超时时返回ctx.Err()
+	ObtainContext(ctx context.Context, chainId, address string) error
+
 	// Unlock 释放账户锁
 	//
 	// Parameters:
 	//   - chainId string: 链ID
 	//   - address string: 账户地址
 	Unlock(chainId, address string)
+
+	// Metrics 获取账户锁等待耗时的统计指标
+	//
+	// Returns:
+	//   - AccountLockMetrics
+	Metrics() AccountLockMetrics
+}
+
+// AccountLockMetrics 账户锁的统计指标
+type AccountLockMetrics struct {
+	Acquired  uint64        // 成功获取锁的次数
+	Canceled  uint64        // 等待期间ctx取消或超时的次数
+	Waiting   int64         // 当前正在等待锁的数量
+	TotalWait time.Duration // 获取锁的累计等待耗时
+	MaxWait   time.Duration // 获取锁的最大等待耗时
+	Entries   int           // 当前仍被持有或等待的账户锁数量
+}
+
+// AvgWait 平均等待耗时
+func (m AccountLockMetrics) AvgWait() time.Duration {
+	if m.Acquired == 0 {
+		return 0
+	}
+	return m.TotalWait / time.Duration(m.Acquired)
+}
+
+// lockMetrics 以原子操作记录锁等待的统计指标
+type lockMetrics struct {
+	acquired  atomic.Uint64
+	canceled  atomic.Uint64
+	waiting   atomic.Int64
+	totalWait atomic.Int64
+	maxWait   atomic.Int64
+}
+
+// begin 开始等待锁，返回结束等待时调用的函数
+func (m *lockMetrics) begin() func(acquired bool) {
+	start := time.Now()
+	m.waiting.Add(1)
+	return func(acquired bool) {
+		m.waiting.Add(-1)
+		if !acquired {
+			m.canceled.Add(1)
+			return
+		}
+		wait := int64(time.Since(start))
+		m.acquired.Add(1)
+		m.totalWait.Add(wait)
+		for {
+			current := m.maxWait.Load()
+			if wait <= current || m.maxWait.CompareAndSwap(current, wait) {
+				break
+			}
+		}
+	}
+}
+
+func (m *lockMetrics) snapshot() AccountLockMetrics {
+	return AccountLockMetrics{
+		Acquired:  m.acquired.Load(),
+		Canceled:  m.canceled.Load(),
+		Waiting:   m.waiting.Load(),
+		TotalWait: time.Duration(m.totalWait.Load()),
+		MaxWait:   time.Duration(m.maxWait.Load()),
+	}
 }
 
 func (l *accountLock) Obtain(chainId, address string) {
-	v, _ := l.locks.LoadOrStore(fmt.Sprintf("%s_%s", chainId, address), &sync.Mutex{})
-	mutex := v.(*sync.Mutex)
-	mutex.Lock()
+	_ = l.ObtainContext(context.Background(), chainId, address)
+}
+
+func (l *accountLock) ObtainContext(ctx context.Context, chainId, address string) error {
+	key := fmt.Sprintf("%s_%s", chainId, address)
+	done := l.metrics.begin()
+
+	l.mu.Lock()
+	mutex, ok := l.locks[key]
+	if !ok {
+		mutex = &accountMutex{ch: make(chan struct{}, 1)}
+		l.locks[key] = mutex
+	}
+	mutex.refs++
+	l.mu.Unlock()
+
+	// 锁空闲时直接获取，避免ctx已取消时与空闲的锁随机选择
+	select {
+	case mutex.ch <- struct{}{}:
+		done(true)
+		return nil
+	default:
+	}
+
+	select {
+	case mutex.ch <- struct{}{}:
+		done(true)
+		return nil
+	case <-ctx.Done():
+		l.release(key, mutex)
+		done(false)
+		return ctx.Err()
+	}
 }
 
 func (l *accountLock) Unlock(chainId, address string) {
-	if v, ok := l.locks.Load(fmt.Sprintf("%s_%s", chainId, address)); ok {
-		mutex := v.(*sync.Mutex)
-		mutex.Unlock()
-		// fixme whether to delete the value
-		// _i.locks.Delete(mutexName)
+	key := fmt.Sprintf("%s_%s", chainId, address)
+	l.mu.Lock()
+	mutex, ok := l.locks[key]
+	l.mu.Unlock()
+	if !ok {
+		return
 	}
+	select {
+	case <-mutex.ch:
+		l.release(key, mutex)
+	default:
+		// 未持有锁时释放，忽略
+	}
+}
+
+// release 减少引用计数，没有持有和等待者时删除该账户的锁
+func (l *accountLock) release(key string, mutex *accountMutex) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	mutex.refs--
+	if mutex.refs == 0 {
+		delete(l.locks, key)
+	}
+}
+
+func (l *accountLock) Metrics() AccountLockMetrics {
+	metrics := l.metrics.snapshot()
+	l.mu.Lock()
+	metrics.Entries = len(l.locks)
+	l.mu.Unlock()
+	return metrics
 }
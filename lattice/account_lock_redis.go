@@ -0,0 +1,258 @@
+package lattice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	redisKeyPrefix               = "lattice"
+	defaultRedisLockLease        = 30 * time.Second
+	defaultRedisLockRetryBackoff = 20 * time.Millisecond
+	defaultRedisRequestTimeout   = 3 * time.Second
+)
+
+// ErrAccountLockLeaseExpired 分布式账户锁的租约已过期，锁可能已被其它副本获取，交易未发送
+var ErrAccountLockLeaseExpired = errors.New("账户锁的租约已过期")
+
+// 锁空闲时获取锁并分配栅栏令牌，只有获取成功才递增令牌，返回0表示锁已被持有
+//
+// KEYS[1]: 锁的key, KEYS[2]: 栅栏令牌的key
+// ARGV[1]: 租约时长(ms)
+var redisObtainScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return 0
+end
+local token = redis.call('INCR', KEYS[2])
+redis.call('SET', KEYS[1], token, 'PX', ARGV[1])
+return token
+`)
+
+// 仅当锁仍由当前持有者（令牌一致）持有时才续约
+var redisRenewScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// 仅当锁仍由当前持有者（令牌一致）持有时才释放
+var redisUnlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// FencingAccountLock 支持栅栏令牌（fencing token）的账户锁
+//
+// 每次成功获取锁时都会分配一个单调递增的令牌，写入共享状态时携带该令牌，
+// 租约过期后仍在执行的旧持有者的写入会因令牌更小而被拒绝。
+type FencingAccountLock interface {
+	AccountLock
+
+	// FencingToken 获取当前进程持有的账户锁的栅栏令牌
+	//
+	// Parameters:
+	//   - chainId string: 链ID
+	//   - address string: 账户地址
+	//
+	// Returns:
+	//   - uint64: 栅栏令牌，租约已过期时仍返回获取锁时分配的令牌
+	//   - bool: 当前进程是否持有该账户锁且租约未过期
+	FencingToken(chainId, address string) (uint64, bool)
+}
+
+// NewRedisAccountLock 初始化基于Redis的分布式账户锁，多个服务副本共享同一个账户时使用
+//
+// 进程内同一账户的锁先通过 NewAccountLock 串行化，同一时刻只有一个持有者，持有期间每隔租约的1/3续约一次，
+// 续约失败直到租约到期时 FencingToken 返回false，发送交易前检查到租约过期时返回 ErrAccountLockLeaseExpired。
+//
+// Parameters:
+//   - client redis.UniversalClient: Redis客户端，支持单机、哨兵和集群
+//   - leaseDuration time.Duration: 锁的租约时长，持有者异常退出后锁在租约到期后自动释放，为0时默认30秒
+//
+// Returns:
+//   - FencingAccountLock
+func NewRedisAccountLock(client redis.UniversalClient, leaseDuration time.Duration) FencingAccountLock {
+	if leaseDuration <= 0 {
+		leaseDuration = defaultRedisLockLease
+	}
+	return &redisAccountLock{
+		client:        client,
+		leaseDuration: leaseDuration,
+		retryBackoff:  defaultRedisLockRetryBackoff,
+		local:         NewAccountLock(),
+	}
+}
+
+type redisAccountLock struct {
+	client        redis.UniversalClient // Redis客户端
+	leaseDuration time.Duration         // 锁的租约时长
+	retryBackoff  time.Duration         // 获取锁失败后的重试间隔
+	local         AccountLock           // 进程内的账户锁，保证同一账户在进程内只有一个持有者
+	holders       sync.Map              // 当前进程持有的锁, key: chainId_address, value: *redisLockHolder
+	metrics       lockMetrics           // 锁等待的统计指标
+}
+
+// redisLockHolder 一次成功获取的锁
+//
+//   - token     获取锁时分配的栅栏令牌
+//   - expiresAt 租约的到期时间(unix nano)，以发起请求的时间计算，续约成功后延后
+//   - stop      通知续约的goroutine退出
+//   - done      续约的goroutine已退出
+type redisLockHolder struct {
+	token     uint64
+	expiresAt atomic.Int64
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+func (h *redisLockHolder) expired() bool {
+	return time.Now().UnixNano() >= h.expiresAt.Load()
+}
+
+// 锁和栅栏令牌使用相同的hash tag，保证在Redis集群中位于同一个slot，可以在同一个脚本中操作
+func redisLockKey(chainId, address string) string {
+	return fmt.Sprintf("%s:lock:{%s_%s}", redisKeyPrefix, chainId, address)
+}
+
+func redisFenceKey(chainId, address string) string {
+	return fmt.Sprintf("%s:fence:{%s_%s}", redisKeyPrefix, chainId, address)
+}
+
+// tryObtain 尝试获取一次锁
+//
+// Returns:
+//   - *redisLockHolder: 获取成功时为锁的持有者，锁已被持有时为nil
+//   - error
+func (l *redisAccountLock) tryObtain(ctx context.Context, chainId, address string) (*redisLockHolder, error) {
+	cancelCtx, cancelFunc := context.WithTimeout(ctx, defaultRedisRequestTimeout)
+	defer cancelFunc()
+
+	start := time.Now()
+	keys := []string{redisLockKey(chainId, address), redisFenceKey(chainId, address)}
+	token, err := redisObtainScript.Run(cancelCtx, l.client, keys, l.leaseDuration.Milliseconds()).Uint64()
+	if err != nil || token == 0 {
+		return nil, err
+	}
+	holder := &redisLockHolder{token: token, stop: make(chan struct{}), done: make(chan struct{})}
+	holder.expiresAt.Store(start.Add(l.leaseDuration).UnixNano())
+	return holder, nil
+}
+
+func (l *redisAccountLock) Obtain(chainId, address string) {
+	_ = l.ObtainContext(context.Background(), chainId, address)
+}
+
+func (l *redisAccountLock) ObtainContext(ctx context.Context, chainId, address string) error {
+	done := l.metrics.begin()
+	if err := l.local.ObtainContext(ctx, chainId, address); err != nil {
+		done(false)
+		return err
+	}
+	for {
+		holder, err := l.tryObtain(ctx, chainId, address)
+		if err != nil {
+			log.Error().Err(err).Msgf("获取Redis账户锁失败，chainId: %s, accountAddress: %s", chainId, address)
+		}
+		if holder != nil {
+			l.holders.Store(fmt.Sprintf("%s_%s", chainId, address), holder)
+			go l.renew(chainId, address, holder)
+			done(true)
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			l.local.Unlock(chainId, address)
+			done(false)
+			return ctx.Err()
+		case <-time.After(l.retryBackoff):
+		}
+	}
+}
+
+// renew 持有锁期间每隔租约的1/3续约一次，锁已被其它持有者获取或租约已到期时停止续约
+func (l *redisAccountLock) renew(chainId, address string, holder *redisLockHolder) {
+	defer close(holder.done)
+	ticker := time.NewTicker(l.leaseDuration / 3)
+	defer ticker.Stop()
+
+	key := redisLockKey(chainId, address)
+	token := strconv.FormatUint(holder.token, 10)
+	for {
+		select {
+		case <-holder.stop:
+			return
+		case <-ticker.C:
+		}
+		if holder.expired() {
+			log.Warn().Msgf("Redis账户锁续约失败，租约已过期，chainId: %s, accountAddress: %s", chainId, address)
+			return
+		}
+
+		start := time.Now()
+		ctx, cancelFunc := context.WithTimeout(context.Background(), defaultRedisRequestTimeout)
+		renewed, err := redisRenewScript.Run(ctx, l.client, []string{key}, token, l.leaseDuration.Milliseconds()).Int()
+		cancelFunc()
+		if err != nil {
+			log.Error().Err(err).Msgf("Redis账户锁续约失败，chainId: %s, accountAddress: %s", chainId, address)
+			continue
+		}
+		if renewed == 0 {
+			holder.expiresAt.Store(0)
+			log.Warn().Msgf("Redis账户锁已被其它持有者获取，停止续约，chainId: %s, accountAddress: %s", chainId, address)
+			return
+		}
+		holder.expiresAt.Store(start.Add(l.leaseDuration).UnixNano())
+	}
+}
+
+func (l *redisAccountLock) Unlock(chainId, address string) {
+	v, ok := l.holders.LoadAndDelete(fmt.Sprintf("%s_%s", chainId, address))
+	if !ok {
+		return
+	}
+	defer l.local.Unlock(chainId, address)
+
+	holder := v.(*redisLockHolder)
+	close(holder.stop)
+	<-holder.done
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), defaultRedisRequestTimeout)
+	defer cancelFunc()
+	deleted, err := redisUnlockScript.Run(ctx, l.client, []string{redisLockKey(chainId, address)}, strconv.FormatUint(holder.token, 10)).Int()
+	if err != nil {
+		log.Error().Err(err).Msgf("释放Redis账户锁失败，chainId: %s, accountAddress: %s", chainId, address)
+		return
+	}
+	if deleted == 0 {
+		log.Warn().Msgf("Redis账户锁的租约已过期，chainId: %s, accountAddress: %s", chainId, address)
+	}
+}
+
+func (l *redisAccountLock) FencingToken(chainId, address string) (uint64, bool) {
+	v, ok := l.holders.Load(fmt.Sprintf("%s_%s", chainId, address))
+	if !ok {
+		return 0, false
+	}
+	holder := v.(*redisLockHolder)
+	return holder.token, !holder.expired()
+}
+
+func (l *redisAccountLock) Metrics() AccountLockMetrics {
+	metrics := l.metrics.snapshot()
+	l.holders.Range(func(_, _ any) bool {
+		metrics.Entries++
+		return true
+	})
+	return metrics
+}
@@ -0,0 +1,62 @@
+package lattice
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountLock(t *testing.T) {
+	t.Run("entries are released", func(t *testing.T) {
+		lock := NewAccountLock()
+		var wg sync.WaitGroup
+		counter := 0
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				address := fmt.Sprintf("zltc_%d", i%5)
+				lock.Obtain(chainId, address)
+				if i%5 == 0 {
+					counter++
+				}
+				lock.Unlock(chainId, address)
+			}(i)
+		}
+		wg.Wait()
+		assert.Equal(t, 20, counter)
+		metrics := lock.Metrics()
+		assert.Equal(t, 0, metrics.Entries)
+		assert.Equal(t, uint64(100), metrics.Acquired)
+		assert.Equal(t, int64(0), metrics.Waiting)
+	})
+
+	t.Run("obtain with context", func(t *testing.T) {
+		lock := NewAccountLock()
+		assert.NoError(t, lock.ObtainContext(context.Background(), chainId, "zltc_a"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		err := lock.ObtainContext(ctx, chainId, "zltc_a")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, uint64(1), lock.Metrics().Canceled)
+
+		// 其他账户不受影响
+		assert.NoError(t, lock.ObtainContext(ctx, chainId, "zltc_b"))
+		lock.Unlock(chainId, "zltc_b")
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			lock.Unlock(chainId, "zltc_a")
+		}()
+		assert.NoError(t, lock.ObtainContext(context.Background(), chainId, "zltc_a"))
+		lock.Unlock(chainId, "zltc_a")
+		metrics := lock.Metrics()
+		assert.Equal(t, 0, metrics.Entries)
+		assert.GreaterOrEqual(t, metrics.MaxWait, 10*time.Millisecond)
+	})
+}
@@ -0,0 +1,100 @@
+package lattice
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchPost(t *testing.T) {
+	// 节点以相反的顺序返回响应，按id匹配
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reqs []*mockRequest
+		_ = json.NewDecoder(r.Body).Decode(&reqs)
+		resps := make([]*mockResponse, 0, len(reqs))
+		for i := len(reqs) - 1; i >= 0; i-- {
+			resp := &mockResponse{Id: reqs[i].Id, JsonRpc: "2.0"}
+			var hash string
+			_ = json.Unmarshal(reqs[i].Params[0], &hash)
+			if hash == "0xbad" {
+				resp.Error = &client.JsonRpcError{Code: -32000, Message: "receipt not found"}
+			} else {
+				resp.Result = &types.Receipt{TBlockHash: common.HexToHash(hash)}
+			}
+			resps = append(resps, resp)
+		}
+		_ = json.NewEncoder(w).Encode(resps)
+	}))
+	t.Cleanup(server.Close)
+
+	bodies := []*client.JsonRpcBody{
+		client.NewJsonRpcBody("latc_getReceipt", "0x01"),
+		client.NewJsonRpcBody("latc_getReceipt", "0xbad"),
+		client.NewJsonRpcBody("latc_getReceipt", "0x03"),
+	}
+	responses, err := client.BatchPost[types.Receipt](context.Background(), server.URL, bodies, nil, http.DefaultTransport)
+	require.NoError(t, err)
+	require.Len(t, responses, 3)
+	assert.NoError(t, responses[0].Error)
+	assert.Equal(t, common.HexToHash("0x01"), responses[0].Result.TBlockHash)
+	assert.EqualError(t, responses[1].Error, "-32000:receipt not found")
+	assert.Nil(t, responses[1].Result)
+	assert.Equal(t, common.HexToHash("0x03"), responses[2].Result.TBlockHash)
+	// 不修改传入的请求体
+	assert.Equal(t, 1, bodies[2].Id)
+}
+
+func TestBatch(t *testing.T) {
+	node := newMockNode(t)
+	node.handle("latc_getReceipt", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		var hash string
+		_ = json.Unmarshal(params[0], &hash)
+		if hash == "0x00" {
+			return nil, nil
+		}
+		return &types.Receipt{TBlockHash: common.HexToHash(hash)}, nil
+	})
+	node.handle("latc_getDBlockByNumber", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		var height uint64
+		_ = json.Unmarshal(params[0], &height)
+		return map[string]any{"number": height}, nil
+	})
+	svc := newMockLattice(t, node, nil)
+
+	batch := client.NewBatch(svc.HttpApi(), chainId)
+	receipts := make([]*client.BatchResult[types.Receipt], 0, 100)
+	for i := 1; i <= 100; i++ {
+		receipts = append(receipts, batch.GetReceipt(common.BigToHash(big.NewInt(int64(i))).Hex()))
+	}
+	missing := batch.GetReceipt("0x00")
+	daemonBlock := batch.GetDaemonBlockByHeight(42)
+	unknown := client.Queue[string](batch, "latc_unknown")
+
+	_, err := daemonBlock.Get()
+	assert.Error(t, err, "批量请求发送前不能读取结果")
+
+	require.NoError(t, batch.Send(context.Background()))
+	assert.Equal(t, 1, node.callCount("latc_getDBlockByNumber"))
+	for i, result := range receipts {
+		receipt, err := result.Get()
+		require.NoError(t, err)
+		assert.Equal(t, common.BigToHash(big.NewInt(int64(i+1))), receipt.TBlockHash)
+	}
+	receipt, err := missing.Get()
+	assert.NoError(t, err)
+	assert.Nil(t, receipt)
+	block, err := daemonBlock.Get()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(42), block.Height.Uint64())
+	_, err = unknown.Get()
+	assert.Error(t, err)
+}
@@ -0,0 +1,153 @@
+package block
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"runtime"
+	"sync"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/crypto"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrProofOfWorkNotFound 在 ProofOfWork 的取值范围内没有找到满足难度的工作量证明
+var ErrProofOfWorkNotFound = errors.New("没有找到满足难度的工作量证明")
+
+// powCheckInterval 每个goroutine计算多少次哈希后检查一次ctx是否取消
+const powCheckInterval = 1024
+
+// maxUint256 工作量证明的哈希取值上限 2^256
+var maxUint256 = new(big.Int).Lsh(big.NewInt(1), 256)
+
+// PowTarget 根据难度计算工作量证明的目标值，交易的 RlpEncodeHash 小于等于目标值时满足难度
+//
+// Parameters:
+//   - difficulty uint64: 难度，为0时不需要工作量证明
+//
+// Returns:
+//   - *big.Int: 2^256 / difficulty
+func PowTarget(difficulty uint64) *big.Int {
+	if difficulty == 0 {
+		return new(big.Int).Set(maxUint256)
+	}
+	return new(big.Int).Div(maxUint256, new(big.Int).SetUint64(difficulty))
+}
+
+// SolvePoW 计算交易的工作量证明，多个goroutine并行搜索 ProofOfWork，
+// 找到后设置交易的 Difficulty 和 ProofOfWork，需要在 SignTX 之前调用，难度为0时不计算
+//
+// Parameters:
+//   - ctx context.Context: 取消或超时后停止搜索
+//   - chainId uint64
+//   - curve types.Curve: 椭圆曲线，决定使用的哈希算法
+//   - difficulty uint64: 节点要求的难度
+//   - workers int: 并行的goroutine数量，小于等于0时使用 runtime.NumCPU
+//
+// Returns:
+//   - error: ctx取消时为 ctx.Err()
+func (tx *Transaction) SolvePoW(ctx context.Context, chainId uint64, curve types.Curve, difficulty uint64, workers int) error {
+	tx.Difficulty = difficulty
+	tx.ProofOfWork = big.NewInt(0)
+	if difficulty <= 1 {
+		return nil
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	// 在启动goroutine之前初始化加密实例，避免并发初始化
+	crypto.NewCrypto(curve)
+
+	target := PowTarget(difficulty)
+	cancelCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		once     sync.Once
+		found    *big.Int
+		solveErr error
+		wg       sync.WaitGroup
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(start int64) {
+			defer wg.Done()
+			nonce, err := tx.searchPoW(cancelCtx, chainId, curve, target, start, int64(workers))
+			if err != nil {
+				if !errors.Is(err, context.Canceled) || ctx.Err() != nil {
+					once.Do(func() { solveErr = err })
+				}
+				return
+			}
+			once.Do(func() {
+				found = nonce
+				cancel()
+			})
+		}(int64(i))
+	}
+	wg.Wait()
+
+	if found == nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if solveErr != nil {
+			return solveErr
+		}
+		return ErrProofOfWorkNotFound
+	}
+	tx.ProofOfWork = found
+	return nil
+}
+
+// searchPoW 从start开始以step为步长搜索满足目标值的 ProofOfWork，搜索时使用交易的副本
+func (tx *Transaction) searchPoW(ctx context.Context, chainId uint64, curve types.Curve, target *big.Int, start, step int64) (*big.Int, error) {
+	candidate := *tx
+	candidate.ProofOfWork = big.NewInt(start)
+	bigStep := big.NewInt(step)
+
+	for i := 0; ; i++ {
+		if i%powCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		if candidate.ProofOfWork.BitLen() > 256 {
+			return nil, ErrProofOfWorkNotFound
+		}
+		hash, err := candidate.RlpEncodeHash(chainId, curve)
+		if err != nil {
+			return nil, err
+		}
+		if powHashMeetsTarget(hash, target) {
+			return new(big.Int).Set(candidate.ProofOfWork), nil
+		}
+		candidate.ProofOfWork.Add(candidate.ProofOfWork, bigStep)
+	}
+}
+
+// VerifyPoW 验证交易的工作量证明是否满足交易的难度
+//
+// Parameters:
+//   - chainId uint64
+//   - curve types.Curve
+//
+// Returns:
+//   - bool
+//   - error
+func (tx *Transaction) VerifyPoW(chainId uint64, curve types.Curve) (bool, error) {
+	if tx.Difficulty <= 1 {
+		return true, nil
+	}
+	hash, err := tx.RlpEncodeHash(chainId, curve)
+	if err != nil {
+		return false, err
+	}
+	return powHashMeetsTarget(hash, PowTarget(tx.Difficulty)), nil
+}
+
+// powHashMeetsTarget 哈希作为大端无符号整数小于等于目标值
+func powHashMeetsTarget(hash common.Hash, target *big.Int) bool {
+	return new(big.Int).SetBytes(hash.Bytes()).Cmp(target) <= 0
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/LatticeBCLab/go-lattice/common/convert"
 	"github.com/LatticeBCLab/go-lattice/common/types"
 	"github.com/LatticeBCLab/go-lattice/crypto"
+	"github.com/LatticeBCLab/go-lattice/lattice/signer"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -262,6 +263,33 @@ func (tx *Transaction) SignTX(chainId uint64, curve types.Curve, skHex string) e
 	return tx.SignHash(hash, curve, skHex)
 }
 
+// SignTXWithSigner 使用签名者签名交易，待签名哈希按签名者的曲线计算
+//
+// Parameters:
+//   - chainId uint64
+//   - s signer.Signer
+//
+// Returns:
+//   - error
+func (tx *Transaction) SignTXWithSigner(chainId uint64, s signer.Signer) error {
+	hash, err := tx.RlpEncodeHash(chainId, s.Curve())
+	if err != nil {
+		return err
+	}
+	return tx.SignHashWithSigner(hash, s)
+}
+
+// SignHashWithSigner 使用签名者签名哈希
+func (tx *Transaction) SignHashWithSigner(hash common.Hash, s signer.Signer) error {
+	signature, err := s.SignHash(hash)
+	if err != nil {
+		return err
+	}
+	tx.Sign = hexutil.Encode(signature)
+
+	return nil
+}
+
 // SignHash 签名哈希
 func (tx *Transaction) SignHash(hash common.Hash, curve types.Curve, skHex string) error {
 	signature, err := tx.sign(curve, hash[:], skHex)
@@ -1,6 +1,7 @@
 package block
 
 import (
+	"context"
 	"math/big"
 	"time"
 
@@ -10,6 +11,8 @@ import (
 
 type TransactionBuilder interface {
 	Build() *Transaction
+	// BuildWithPoW 构造交易并计算工作量证明，用于开启了工作量证明的链，之后再调用 Transaction.SignTX 签名
+	BuildWithPoW(ctx context.Context, chainId uint64, curve types.Curve, difficulty uint64, workers int) (*Transaction, error)
 	SetLatestBlock(block *types.LatestBlock) TransactionBuilder
 	SetOwner(owner string) TransactionBuilder
 	SetLinker(linker string) TransactionBuilder
@@ -37,6 +40,13 @@ func (builder *transactionBuilder) Build() *Transaction {
 	return builder.Transaction
 }
 
+func (builder *transactionBuilder) BuildWithPoW(ctx context.Context, chainId uint64, curve types.Curve, difficulty uint64, workers int) (*Transaction, error) {
+	if err := builder.Transaction.SolvePoW(ctx, chainId, curve, difficulty, workers); err != nil {
+		return nil, err
+	}
+	return builder.Transaction, nil
+}
+
 func (builder *transactionBuilder) SetLatestBlock(block *types.LatestBlock) TransactionBuilder {
 	builder.Transaction.Height = block.Height + 1
 	builder.Transaction.ParentHash = block.Hash
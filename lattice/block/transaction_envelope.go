@@ -0,0 +1,287 @@
+package block
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/crypto"
+	"github.com/LatticeBCLab/go-lattice/lattice/signer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// TransactionEnvelopeVersion 当前的交易信封格式版本
+const TransactionEnvelopeVersion uint8 = 1
+
+var (
+	// ErrEnvelopeHashMismatch 交易内容计算出的待签名哈希与信封中的哈希不一致，交易可能被篡改
+	ErrEnvelopeHashMismatch = errors.New("交易的待签名哈希与信封中的哈希不一致")
+	// ErrEnvelopeNotSigned 信封中的交易未签名
+	ErrEnvelopeNotSigned = errors.New("交易未签名")
+	// ErrEnvelopeSignerMismatch 签名者与交易的owner不一致
+	ErrEnvelopeSignerMismatch = errors.New("交易的签名者与owner不一致")
+)
+
+// TransactionEnvelope 交易信封，用于在联网环境和离线签名环境之间传递交易，支持JSON和RLP两种编码
+//
+//   - Version     信封格式版本
+//   - ChainId     链ID，参与待签名哈希的计算
+//   - Curve       签名使用的椭圆曲线
+//   - Hash        待签名哈希，签名前后都会重新计算并校验
+//   - Transaction 交易，签名后 Transaction.Sign 不为空
+type TransactionEnvelope struct {
+	Version     uint8        `json:"version"`
+	ChainId     uint64       `json:"chainId"`
+	Curve       types.Curve  `json:"curve"`
+	Hash        common.Hash  `json:"hash"`
+	Transaction *Transaction `json:"transaction"`
+}
+
+// NewTransactionEnvelope 将未签名的交易装入信封，并计算待签名哈希
+//
+// Parameters:
+//   - chainId uint64
+//   - curve types.Curve
+//   - unsignedTx *Transaction
+//
+// Returns:
+//   - *TransactionEnvelope
+//   - error
+func NewTransactionEnvelope(chainId uint64, curve types.Curve, unsignedTx *Transaction) (*TransactionEnvelope, error) {
+	hash, err := unsignedTx.RlpEncodeHash(chainId, curve)
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionEnvelope{
+		Version:     TransactionEnvelopeVersion,
+		ChainId:     chainId,
+		Curve:       curve,
+		Hash:        hash,
+		Transaction: unsignedTx,
+	}, nil
+}
+
+// CheckHash 重新计算待签名哈希，并与信封中的哈希比较
+func (e *TransactionEnvelope) CheckHash() error {
+	if err := e.validate(); err != nil {
+		return err
+	}
+	hash, err := e.Transaction.RlpEncodeHash(e.ChainId, e.Curve)
+	if err != nil {
+		return err
+	}
+	if hash != e.Hash {
+		return fmt.Errorf("%w，expect %s, got %s", ErrEnvelopeHashMismatch, e.Hash, hash)
+	}
+	return nil
+}
+
+// Sign 校验待签名哈希后使用私钥签名交易，可以在离线环境中调用
+//
+// Parameters:
+//   - skHex string: 私钥
+//
+// Returns:
+//   - error
+func (e *TransactionEnvelope) Sign(skHex string) error {
+	if err := e.CheckHash(); err != nil {
+		return err
+	}
+	return e.Transaction.SignHash(e.Hash, e.Curve, skHex)
+}
+
+// SignWithSigner 校验待签名哈希后使用签名者签名交易，签名者的曲线必须与信封一致
+//
+// Parameters:
+//   - s signer.Signer
+//
+// Returns:
+//   - error
+func (e *TransactionEnvelope) SignWithSigner(s signer.Signer) error {
+	if s.Curve() != e.Curve {
+		return fmt.Errorf("签名者的曲线%s与交易信封的曲线%s不一致", s.Curve(), e.Curve)
+	}
+	if err := e.CheckHash(); err != nil {
+		return err
+	}
+	return e.Transaction.SignHashWithSigner(e.Hash, s)
+}
+
+// Verify 校验待签名哈希，并从签名恢复公钥，校验签名者是否为交易的owner
+//
+// Returns:
+//   - common.Address: 签名者的地址
+//   - error
+func (e *TransactionEnvelope) Verify() (common.Address, error) {
+	if err := e.CheckHash(); err != nil {
+		return common.Address{}, err
+	}
+	if e.Transaction.Sign == "" {
+		return common.Address{}, ErrEnvelopeNotSigned
+	}
+	signature, err := hexutil.Decode(e.Transaction.Sign)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("无法解析交易的签名：%w", err)
+	}
+	cryptoInstance := crypto.NewCrypto(e.Curve)
+	pk, err := cryptoInstance.SignatureToPK(e.Hash.Bytes(), signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("无法从签名恢复公钥：%w", err)
+	}
+	signer, err := cryptoInstance.PKToAddress(pk)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if signer != e.Transaction.GetOwnerAddress() {
+		return signer, fmt.Errorf("%w，owner: %s, signer: %s", ErrEnvelopeSignerMismatch, e.Transaction.Owner, signer)
+	}
+	return signer, nil
+}
+
+// rlpTransactionEnvelope 交易信封的RLP编码结构，字段顺序不可调整
+type rlpTransactionEnvelope struct {
+	Version     uint8
+	ChainId     uint64
+	Curve       string
+	Hash        common.Hash
+	Height      uint64
+	Type        string
+	ParentHash  common.Hash
+	Hub         []common.Hash
+	DaemonHash  common.Hash
+	CodeHash    common.Hash
+	Owner       string
+	Linker      string
+	Amount      *big.Int
+	Joule       *big.Int
+	Difficulty  uint64
+	Pow         *big.Int `rlp:"nil"`
+	ProofOfWork *big.Int `rlp:"nil"`
+	Payload     string
+	Timestamp   uint64
+	Code        string
+	Sign        string
+}
+
+// MarshalRLP 将信封编码为RLP
+func (e *TransactionEnvelope) MarshalRLP() ([]byte, error) {
+	tx := e.Transaction
+	if tx == nil {
+		return nil, errors.New("信封中的交易为空")
+	}
+	return rlp.EncodeToBytes(&rlpTransactionEnvelope{
+		Version:     e.Version,
+		ChainId:     e.ChainId,
+		Curve:       string(e.Curve),
+		Hash:        e.Hash,
+		Height:      tx.Height,
+		Type:        string(tx.Type),
+		ParentHash:  tx.ParentHash,
+		Hub:         tx.Hub,
+		DaemonHash:  tx.DaemonHash,
+		CodeHash:    tx.CodeHash,
+		Owner:       tx.Owner,
+		Linker:      tx.Linker,
+		Amount:      tx.Amount,
+		Joule:       tx.Joule,
+		Difficulty:  tx.Difficulty,
+		Pow:         tx.Pow,
+		ProofOfWork: tx.ProofOfWork,
+		Payload:     tx.Payload,
+		Timestamp:   tx.Timestamp,
+		Code:        tx.Code,
+		Sign:        tx.Sign,
+	})
+}
+
+// UnmarshalTransactionEnvelopeRLP 解析RLP编码的交易信封
+//
+// Parameters:
+//   - b []byte
+//
+// Returns:
+//   - *TransactionEnvelope
+//   - error
+func UnmarshalTransactionEnvelopeRLP(b []byte) (*TransactionEnvelope, error) {
+	var raw rlpTransactionEnvelope
+	if err := rlp.DecodeBytes(b, &raw); err != nil {
+		return nil, err
+	}
+	if err := checkEnvelopeVersion(raw.Version); err != nil {
+		return nil, err
+	}
+	hub := raw.Hub
+	if hub == nil {
+		hub = make([]common.Hash, 0)
+	}
+	envelope := &TransactionEnvelope{
+		Version: raw.Version,
+		ChainId: raw.ChainId,
+		Curve:   types.Curve(raw.Curve),
+		Hash:    raw.Hash,
+		Transaction: &Transaction{
+			Height:      raw.Height,
+			Type:        TransactionType(raw.Type),
+			ParentHash:  raw.ParentHash,
+			Hub:         hub,
+			DaemonHash:  raw.DaemonHash,
+			CodeHash:    raw.CodeHash,
+			Owner:       raw.Owner,
+			Linker:      raw.Linker,
+			Amount:      raw.Amount,
+			Joule:       raw.Joule,
+			Difficulty:  raw.Difficulty,
+			Pow:         raw.Pow,
+			ProofOfWork: raw.ProofOfWork,
+			Payload:     raw.Payload,
+			Timestamp:   raw.Timestamp,
+			Code:        raw.Code,
+			Sign:        raw.Sign,
+		},
+	}
+	if err := envelope.validate(); err != nil {
+		return nil, err
+	}
+	return envelope, nil
+}
+
+// UnmarshalTransactionEnvelopeJSON 解析JSON编码的交易信封，编码时直接使用 json.Marshal
+//
+// Parameters:
+//   - b []byte
+//
+// Returns:
+//   - *TransactionEnvelope
+//   - error
+func UnmarshalTransactionEnvelopeJSON(b []byte) (*TransactionEnvelope, error) {
+	var envelope TransactionEnvelope
+	if err := json.Unmarshal(b, &envelope); err != nil {
+		return nil, err
+	}
+	if err := checkEnvelopeVersion(envelope.Version); err != nil {
+		return nil, err
+	}
+	if err := envelope.validate(); err != nil {
+		return nil, err
+	}
+	return &envelope, nil
+}
+
+// validate 校验信封中的交易不为空且 Payload 为16进制字符串，信封来自不可信的传输通道，避免计算哈希时panic
+func (e *TransactionEnvelope) validate() error {
+	if e.Transaction == nil {
+		return errors.New("信封中的交易为空")
+	}
+	return validatePayload(e.Transaction.Payload)
+}
+
+func checkEnvelopeVersion(version uint8) error {
+	if version == 0 || version > TransactionEnvelopeVersion {
+		return fmt.Errorf("不支持的交易信封版本：%d", version)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package block
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/crypto"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var (
+	// ErrTransactionNotSigned 交易未签名
+	ErrTransactionNotSigned = errors.New("交易未签名")
+	// ErrTransactionSignatureInvalid 无法从签名恢复公钥，或恢复的地址与交易的owner不一致，交易可能被篡改
+	ErrTransactionSignatureInvalid = errors.New("交易的签名无效")
+	// ErrTransactionHashMismatch 重新计算的交易哈希与交易中的哈希不一致
+	ErrTransactionHashMismatch = errors.New("交易哈希不一致")
+	// ErrProofOfWorkInvalid 交易的工作量证明不满足交易的难度
+	ErrProofOfWorkInvalid = errors.New("交易的工作量证明不满足难度")
+	// ErrTransactionPayloadInvalid 交易的 Payload 不是0x开头的16进制字符串
+	ErrTransactionPayloadInvalid = errors.New("交易的payload无效")
+)
+
+// VerifyTransaction 独立于节点校验已签名的交易
+//
+//  1. 重新计算待签名哈希，从签名恢复公钥，校验签名者的地址是否为交易的owner
+//  2. 交易的难度大于1时，校验工作量证明是否满足难度
+//  3. 交易的 Hash 不为空时，重新计算交易哈希并与 Hash 比较
+//
+// Parameters:
+//   - tx *Transaction: 已签名的交易，链上的交易区块可以通过 TransactionFromBlock 转换
+//   - chainId uint64
+//   - curve types.Curve
+//
+// Returns:
+//   - error
+func VerifyTransaction(tx *Transaction, chainId uint64, curve types.Curve) error {
+	if tx == nil {
+		return errors.New("交易为空")
+	}
+	if err := validatePayload(tx.Payload); err != nil {
+		return err
+	}
+	if tx.Sign == "" {
+		return ErrTransactionNotSigned
+	}
+	signature, err := hexutil.Decode(tx.Sign)
+	if err != nil {
+		return fmt.Errorf("无法解析交易的签名：%w", err)
+	}
+
+	hash, err := tx.RlpEncodeHash(chainId, curve)
+	if err != nil {
+		return err
+	}
+	cryptoInstance := crypto.NewCrypto(curve)
+	pk, err := cryptoInstance.SignatureToPK(hash.Bytes(), signature)
+	if err != nil {
+		return fmt.Errorf("%w，无法从签名恢复公钥：%w", ErrTransactionSignatureInvalid, err)
+	}
+	if pk == nil {
+		return fmt.Errorf("%w，无法从签名恢复公钥", ErrTransactionSignatureInvalid)
+	}
+	signer, err := cryptoInstance.PKToAddress(pk)
+	if err != nil {
+		return err
+	}
+	if signer != tx.GetOwnerAddress() {
+		return fmt.Errorf("%w，签名者与owner不一致，owner: %s, signer: %s", ErrTransactionSignatureInvalid, tx.Owner, signer)
+	}
+
+	if ok, err := tx.VerifyPoW(chainId, curve); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("%w，difficulty: %d", ErrProofOfWorkInvalid, tx.Difficulty)
+	}
+
+	if tx.Hash == "" {
+		return nil
+	}
+	txHash, err := tx.CalculateTransactionHash(curve)
+	if err != nil {
+		return err
+	}
+	if txHash != common.HexToHash(tx.Hash) {
+		return fmt.Errorf("%w，expect %s, got %s", ErrTransactionHashMismatch, tx.Hash, txHash)
+	}
+	return nil
+}
+
+// TransactionFromBlock 将从链上查询到的交易区块转为交易，用于 VerifyTransaction 重新校验
+//
+// Parameters:
+//   - tblock *types.TransactionBlock
+//
+// Returns:
+//   - *Transaction
+//   - error
+func TransactionFromBlock(tblock *types.TransactionBlock) (*Transaction, error) {
+	if tblock == nil {
+		return nil, errors.New("交易区块为空")
+	}
+	tx := &Transaction{
+		Type:       TransactionType(tblock.Type),
+		ParentHash: tblock.ParentHash,
+		Hub:        make([]common.Hash, len(tblock.Hub)),
+		DaemonHash: tblock.DaemonHash,
+		Owner:      tblock.Owner,
+		Linker:     tblock.Linker,
+		Joule:      new(big.Int).SetUint64(tblock.Joule),
+		Difficulty: tblock.Difficulty,
+		Payload:    tblock.Payload,
+		Timestamp:  tblock.Timestamp,
+		Code:       tblock.Code,
+		Sign:       tblock.Sign,
+		Hash:       tblock.Hash.Hex(),
+	}
+	if _, ok := TransactionTypeCode[tx.Type]; !ok {
+		return nil, fmt.Errorf("未知的交易类型：%s", tblock.Type)
+	}
+	if tblock.Height != nil {
+		tx.Height = tblock.Height.Uint64()
+	}
+	for i, hub := range tblock.Hub {
+		tx.Hub[i] = common.HexToHash(hub)
+	}
+	if tblock.CodeHash != "" {
+		tx.CodeHash = common.HexToHash(tblock.CodeHash)
+	}
+	if tx.Payload == "" {
+		tx.Payload = "0x"
+	}
+	if err := validatePayload(tx.Payload); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if tx.Amount, err = parseBigInt("amount", tblock.Amount); err != nil {
+		return nil, err
+	}
+	if tx.ProofOfWork, err = parseBigInt("proofOfWork", tblock.Pow); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// validatePayload 校验 Payload 是否为0x开头的16进制字符串，计算哈希时 DecodePayload 遇到无效的 Payload 会panic
+func validatePayload(payload string) error {
+	if _, err := hexutil.Decode(payload); err != nil {
+		return fmt.Errorf("%w：%s，%w", ErrTransactionPayloadInvalid, payload, err)
+	}
+	return nil
+}
+
+// parseBigInt 解析10进制或0x开头的16进制整数，为空时返回0
+func parseBigInt(name, value string) (*big.Int, error) {
+	if value == "" {
+		return big.NewInt(0), nil
+	}
+	result, ok := new(big.Int).SetString(value, 0)
+	if !ok {
+		return nil, fmt.Errorf("无法解析交易区块的%s：%s", name, value)
+	}
+	return result, nil
+}
@@ -0,0 +1,128 @@
+package block
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/LatticeBCLab/go-lattice/common/convert"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/signer"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrWitnessQuorumNotReached 有效的见证签名数量未达到共识的投票门限
+var ErrWitnessQuorumNotReached = errors.New("有效的见证签名数量未达到投票门限")
+
+// WitnessProofVerdict 见证证明的校验结果
+//
+//   - Hash     被见证的区块哈希
+//   - Valid    有效的见证签名数量是否达到投票门限
+//   - Required 投票门限，即至少需要的有效签名数量
+//   - Total    共识节点的数量
+//   - Signers  签名有效的共识节点地址，同一节点的多个签名只计一次
+//   - Rejected 无效的签名及原因，key为签名中的from
+type WitnessProofVerdict struct {
+	Hash     common.Hash
+	Valid    bool
+	Required int
+	Total    int
+	Signers  []string
+	Rejected map[string]error
+}
+
+// WitnessQuorum 计算共识节点的投票门限，PBFT共识需要 2f+1 个签名，其中 f=(n-1)/3，其它共识需要超过半数的签名
+//
+// Parameters:
+//   - consensus types.Consensus: 共识类型，不区分大小写
+//   - total int: 共识节点的数量
+//
+// Returns:
+//   - int
+func WitnessQuorum(consensus types.Consensus, total int) int {
+	if total <= 0 {
+		return 0
+	}
+	// 节点返回的共识类型大小写不固定，如 pbft 和 PBFT
+	if strings.EqualFold(string(consensus), string(types.ConsensusPBFT)) {
+		return 2*((total-1)/3) + 1
+	}
+	return total/2 + 1
+}
+
+// VerifyWitnessProof 独立于节点校验守护区块或交易区块的见证证明，共识节点集合和共识类型应来自可信的来源，
+// 每个签名需要由共识节点对区块哈希签发，有效签名达到投票门限时返回的 WitnessProofVerdict.Valid 为true
+//
+// Parameters:
+//   - proof *types.WitnessProof: GetDBlockProof 或 GetTBlockProof 的返回值
+//   - curve types.Curve: 链的椭圆曲线
+//   - consensus types.Consensus: 共识类型，不区分大小写
+//   - saints []string: 共识节点的地址，如 NodeConfirmedConfiguration.LatcSaints
+//
+// Returns:
+//   - *WitnessProofVerdict
+//   - error: 未达到投票门限时为 ErrWitnessQuorumNotReached，同时返回校验结果
+func VerifyWitnessProof(proof *types.WitnessProof, curve types.Curve, consensus types.Consensus, saints []string) (*WitnessProofVerdict, error) {
+	if proof == nil {
+		return nil, errors.New("见证证明为空")
+	}
+	saintSet := make(map[common.Address]struct{}, len(saints))
+	for _, saint := range saints {
+		address, err := convert.ZltcToAddress(saint)
+		if err != nil {
+			return nil, fmt.Errorf("无法解析共识节点的地址%s：%w", saint, err)
+		}
+		saintSet[address] = struct{}{}
+	}
+
+	verdict := &WitnessProofVerdict{
+		Hash:     proof.Hash,
+		Required: WitnessQuorum(consensus, len(saintSet)),
+		Total:    len(saintSet),
+		Signers:  make([]string, 0, len(proof.Signers)),
+		Rejected: make(map[string]error),
+	}
+	witnessed := make(map[common.Address]struct{}, len(proof.Signers))
+	for _, signature := range proof.Signers {
+		if signature == nil {
+			continue
+		}
+		address, err := verifyWitnessSignature(proof.Hash, curve, saintSet, signature)
+		if err != nil {
+			verdict.Rejected[signature.From] = err
+			continue
+		}
+		if _, ok := witnessed[address]; ok {
+			continue
+		}
+		witnessed[address] = struct{}{}
+		verdict.Signers = append(verdict.Signers, convert.AddressToZltc(address))
+	}
+
+	verdict.Valid = verdict.Total > 0 && len(verdict.Signers) >= verdict.Required
+	if !verdict.Valid {
+		return verdict, fmt.Errorf("%w，有效签名%d个，需要%d个", ErrWitnessQuorumNotReached, len(verdict.Signers), verdict.Required)
+	}
+	return verdict, nil
+}
+
+// verifyWitnessSignature 校验单个见证签名，返回签名者的地址
+func verifyWitnessSignature(hash common.Hash, curve types.Curve, saints map[common.Address]struct{}, signature *types.Signature) (common.Address, error) {
+	if signature.Hash != (common.Hash{}) && signature.Hash != hash {
+		return common.Address{}, fmt.Errorf("签名的哈希%s与区块哈希%s不一致", signature.Hash, hash)
+	}
+	address, err := convert.ZltcToAddress(signature.From)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("无法解析签名者的地址：%w", err)
+	}
+	if _, ok := saints[address]; !ok {
+		return common.Address{}, fmt.Errorf("签名者%s不是共识节点", signature.From)
+	}
+	if len(signature.Sign) == 0 {
+		return common.Address{}, errors.New("签名为空")
+	}
+	if err := signer.VerifySignature(curve, address, hash, signature.Sign); err != nil {
+		return common.Address{}, err
+	}
+	return address, nil
+}
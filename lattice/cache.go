@@ -66,9 +66,17 @@ type BlockCache interface {
 	//   - *types.LatestBlock: 缓存的区块信息
 	//   - error
 	GetBlock(chainId, address string) (*types.LatestBlock, error)
-}
 
-// type redisBlockCache struct{}
+	// Invalidate 清除账户的区块缓存，下一次获取区块时将重新请求节点
+	//
+	// Parameters:
+	//   - chainId string: 链ID
+	//   - address string: 账户地址
+	//
+	// Returns:
+	//   - error
+	Invalidate(chainId, address string) error
+}
 
 type memoryBlockCache struct {
 	enable                       bool               // 是否启用缓存
@@ -141,3 +149,14 @@ func (c *memoryBlockCache) GetBlock(chainId, address string) (*types.LatestBlock
 
 	return cacheBlock, nil
 }
+
+func (c *memoryBlockCache) Invalidate(chainId, address string) error {
+	if !c.enable {
+		return nil
+	}
+	if err := c.memoryCacheApi.Delete(fmt.Sprintf("%s_%s", chainId, address)); err != nil && !errors.Is(err, bigcache.ErrEntryNotFound) {
+		log.Error().Err(err).Msgf("清除区块缓存信息失败，chainId: %s, accountAddress: %s", chainId, address)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package lattice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrStaleBlockCache 写入的区块比Redis中已缓存的区块更旧（高度更低或栅栏令牌更小）
+var ErrStaleBlockCache = errors.New("拒绝写入过期的区块缓存")
+
+// 原子的比较并写入区块缓存：
+//   - 携带栅栏令牌时，已缓存的令牌更大则拒绝写入
+//   - 未携带栅栏令牌时，已缓存的高度更高则拒绝写入
+//
+// KEYS[1]: 区块缓存的key
+// ARGV[1]: 高度, ARGV[2]: 区块的json, ARGV[3]: 过期时长(ms), ARGV[4]: 栅栏令牌
+var redisSetBlockScript = redis.NewScript(`
+local fence = tonumber(ARGV[4])
+if fence > 0 then
+	local stored = tonumber(redis.call('HGET', KEYS[1], 'fence') or '0')
+	if stored > fence then
+		return 0
+	end
+	redis.call('HSET', KEYS[1], 'height', ARGV[1], 'block', ARGV[2], 'fence', ARGV[4])
+else
+	local height = redis.call('HGET', KEYS[1], 'height')
+	if height and tonumber(height) > tonumber(ARGV[1]) then
+		return 0
+	end
+	redis.call('HSET', KEYS[1], 'height', ARGV[1], 'block', ARGV[2])
+end
+if tonumber(ARGV[3]) > 0 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[3])
+end
+return 1
+`)
+
+// NewRedisBlockCache 初始化基于Redis的分布式区块缓存，多个服务副本共享同一个账户时使用
+//
+// Parameters:
+//   - client redis.UniversalClient: Redis客户端，支持单机、哨兵和集群
+//   - accountLock FencingAccountLock: 与缓存配对使用的账户锁，写入缓存时携带其栅栏令牌，为nil时仅比较区块高度
+//   - daemonHashExpirationDuration time.Duration: 守护区块哈希的过期时长
+//   - lifeDuration time.Duration: 缓存的存活时长，为0时不过期
+//
+// Returns:
+//   - BlockCache
+func NewRedisBlockCache(client redis.UniversalClient, accountLock FencingAccountLock, daemonHashExpirationDuration, lifeDuration time.Duration) BlockCache {
+	return &redisBlockCache{
+		client:                       client,
+		accountLock:                  accountLock,
+		daemonHashExpirationDuration: daemonHashExpirationDuration,
+		lifeDuration:                 lifeDuration,
+	}
+}
+
+type redisBlockCache struct {
+	client                       redis.UniversalClient // Redis客户端
+	accountLock                  FencingAccountLock    // 提供栅栏令牌的账户锁
+	httpApi                      client.HttpApi        // 节点的http客户端
+	daemonHashExpirationDuration time.Duration         // 守护区块哈希的过期时长
+	lifeDuration                 time.Duration         // 缓存的存活时长
+}
+
+func redisBlockKey(chainId, address string) string {
+	return fmt.Sprintf("%s:block:%s_%s", redisKeyPrefix, chainId, address)
+}
+
+func redisDaemonHashKey(chainId string) string {
+	return fmt.Sprintf("%s:daemon:%s", redisKeyPrefix, chainId)
+}
+
+func (c *redisBlockCache) SetHttpApi(httpApi client.HttpApi) {
+	c.httpApi = httpApi
+}
+
+func (c *redisBlockCache) SetBlock(chainId, address string, block *types.LatestBlock) error {
+	bytes, err := json.Marshal(block)
+	if err != nil {
+		log.Error().Err(err).Msgf("json序列化block失败，chainId: %s, accountAddress: %s", chainId, address)
+		return err
+	}
+	var fence uint64
+	if c.accountLock != nil {
+		fence, _ = c.accountLock.FencingToken(chainId, address)
+	}
+
+	ctx, cancelFunc := context.WithTimeout(context.Background(), defaultRedisRequestTimeout)
+	defer cancelFunc()
+	ok, err := redisSetBlockScript.Run(ctx, c.client, []string{redisBlockKey(chainId, address)}, block.Height, bytes, c.lifeDuration.Milliseconds(), fence).Int()
+	if err != nil {
+		log.Error().Err(err).Msgf("设置区块缓存信息失败，chainId: %s, accountAddress: %s", chainId, address)
+		return err
+	}
+	if ok == 0 {
+		log.Warn().Msgf("区块缓存已被更新的写入覆盖，chainId: %s, accountAddress: %s, height: %d, fence: %d", chainId, address, block.Height, fence)
+		return ErrStaleBlockCache
+	}
+
+	if err := c.client.SetNX(ctx, redisDaemonHashKey(chainId), 1, c.daemonHashExpirationDuration).Err(); err != nil {
+		log.Error().Err(err).Msgf("设置守护区块哈希的过期时间失败，chainId: %s", chainId)
+	}
+	return nil
+}
+
+func (c *redisBlockCache) GetBlock(chainId, address string) (*types.LatestBlock, error) {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), defaultRedisRequestTimeout)
+	defer cancelFunc()
+	cacheBlockBytes, err := c.client.HGet(ctx, redisBlockKey(chainId, address), "block").Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return c.httpApi.GetLatestBlock(context.Background(), chainId, address)
+		}
+		log.Error().Err(err).Msgf("获取区块缓存信息失败，chainId: %s, accountAddress: %s", chainId, address)
+		return nil, err
+	}
+	cacheBlock := new(types.LatestBlock)
+	if err := json.Unmarshal(cacheBlockBytes, cacheBlock); err != nil {
+		log.Error().Err(err).Msgf("json序列化block失败，chainId: %s, accountAddress: %s", chainId, address)
+		return nil, err
+	}
+
+	// 守护区块哈希的过期标记不存在时，说明已过期，由首个设置成功的副本负责刷新
+	expired, err := c.client.SetNX(ctx, redisDaemonHashKey(chainId), 1, c.daemonHashExpirationDuration).Result()
+	if err != nil {
+		log.Error().Err(err).Msgf("获取守护区块哈希的过期时间失败，chainId: %s", chainId)
+		return nil, err
+	}
+	if expired {
+		log.Debug().Msgf("守护区块哈希已过期，开始更新守护区块哈希，chainId: %s, accountAddress: %s", chainId, address)
+		block, err := c.httpApi.GetLatestBlock(context.Background(), chainId, address)
+		if err != nil {
+			log.Error().Err(err).Msgf("请求节点获取最新区块信息失败，chainId: %s, accountAddress: %s", chainId, address)
+			return nil, err
+		}
+		cacheBlock.DaemonBlockHash = block.DaemonBlockHash
+	}
+
+	return cacheBlock, nil
+}
+
+// Invalidate 只删除缓存的区块，保留栅栏令牌，避免重新同步后令牌更小的旧持有者写入成功
+func (c *redisBlockCache) Invalidate(chainId, address string) error {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), defaultRedisRequestTimeout)
+	defer cancelFunc()
+	if err := c.client.HDel(ctx, redisBlockKey(chainId, address), "height", "block").Err(); err != nil {
+		log.Error().Err(err).Msgf("清除区块缓存信息失败，chainId: %s, accountAddress: %s", chainId, address)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package lattice
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupRedis(t *testing.T) (*miniredis.Miniredis, redis.UniversalClient) {
+	server := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = redisClient.Close() })
+	return server, redisClient
+}
+
+func TestRedisAccountLock(t *testing.T) {
+	server, redisClient := setupRedis(t)
+
+	t.Run("mutual exclusion across replicas", func(t *testing.T) {
+		replicas := []AccountLock{NewRedisAccountLock(redisClient, time.Second), NewRedisAccountLock(redisClient, time.Second)}
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		holders, maxHolders := 0, 0
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(lock AccountLock) {
+				defer wg.Done()
+				lock.Obtain(chainId, "zltc_a")
+				mu.Lock()
+				holders++
+				maxHolders = max(maxHolders, holders)
+				mu.Unlock()
+				time.Sleep(time.Millisecond)
+				mu.Lock()
+				holders--
+				mu.Unlock()
+				lock.Unlock(chainId, "zltc_a")
+			}(replicas[i%2])
+		}
+		wg.Wait()
+		assert.Equal(t, 1, maxHolders)
+	})
+
+	t.Run("fencing token increases", func(t *testing.T) {
+		lock := NewRedisAccountLock(redisClient, time.Second)
+		lock.Obtain(chainId, "zltc_b")
+		first, ok := lock.FencingToken(chainId, "zltc_b")
+		assert.True(t, ok)
+		lock.Unlock(chainId, "zltc_b")
+		_, ok = lock.FencingToken(chainId, "zltc_b")
+		assert.False(t, ok)
+
+		lock.Obtain(chainId, "zltc_b")
+		second, _ := lock.FencingToken(chainId, "zltc_b")
+		assert.Greater(t, second, first)
+		lock.Unlock(chainId, "zltc_b")
+	})
+
+	t.Run("lease expires", func(t *testing.T) {
+		stale := NewRedisAccountLock(redisClient, time.Second)
+		stale.Obtain(chainId, "zltc_c")
+		server.FastForward(2 * time.Second)
+
+		fresh := NewRedisAccountLock(redisClient, time.Second)
+		fresh.Obtain(chainId, "zltc_c")
+		// 租约过期的旧持有者释放锁时不能删除新持有者的锁
+		stale.Unlock(chainId, "zltc_c")
+		assert.True(t, server.Exists(redisLockKey(chainId, "zltc_c")))
+		fresh.Unlock(chainId, "zltc_c")
+		assert.False(t, server.Exists(redisLockKey(chainId, "zltc_c")))
+	})
+
+	t.Run("lease expired holder", func(t *testing.T) {
+		lock := NewRedisAccountLock(redisClient, 300*time.Millisecond)
+		lock.Obtain(chainId, "zltc_d")
+		server.FastForward(time.Second)
+		other := NewRedisAccountLock(redisClient, time.Minute)
+		other.Obtain(chainId, "zltc_d")
+		// 续约时发现锁已被其它持有者获取
+		assert.Eventually(t, func() bool {
+			_, held := lock.FencingToken(chainId, "zltc_d")
+			return !held
+		}, time.Second, 10*time.Millisecond)
+		lock.Unlock(chainId, "zltc_d")
+		other.Unlock(chainId, "zltc_d")
+	})
+
+	t.Run("lease renewed", func(t *testing.T) {
+		lease := 300 * time.Millisecond
+		lock := NewRedisAccountLock(redisClient, lease)
+		lock.Obtain(chainId, "zltc_e")
+		server.FastForward(200 * time.Millisecond)
+		assert.Eventually(t, func() bool {
+			return server.TTL(redisLockKey(chainId, "zltc_e")) == lease
+		}, time.Second, 10*time.Millisecond)
+		_, held := lock.FencingToken(chainId, "zltc_e")
+		assert.True(t, held)
+		lock.Unlock(chainId, "zltc_e")
+	})
+
+	t.Run("one holder per process", func(t *testing.T) {
+		lock := NewRedisAccountLock(redisClient, time.Minute)
+		lock.Obtain(chainId, "zltc_f")
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, lock.ObtainContext(ctx, chainId, "zltc_f"), context.DeadlineExceeded)
+		assert.True(t, server.Exists(redisLockKey(chainId, "zltc_f")))
+		lock.Unlock(chainId, "zltc_f")
+		assert.Equal(t, 0, lock.Metrics().Entries)
+	})
+
+	t.Run("waiting does not consume tokens", func(t *testing.T) {
+		holder := NewRedisAccountLock(redisClient, time.Minute)
+		waiter := NewRedisAccountLock(redisClient, time.Minute)
+		holder.Obtain(chainId, "zltc_g")
+		first, _ := holder.FencingToken(chainId, "zltc_g")
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		assert.Error(t, waiter.ObtainContext(ctx, chainId, "zltc_g"))
+		holder.Unlock(chainId, "zltc_g")
+
+		waiter.Obtain(chainId, "zltc_g")
+		second, _ := waiter.FencingToken(chainId, "zltc_g")
+		assert.Equal(t, first+1, second)
+		waiter.Unlock(chainId, "zltc_g")
+	})
+}
+
+func TestRedisBlockCache(t *testing.T) {
+	_, redisClient := setupRedis(t)
+	node := newMockNode(t)
+	node.handle("latc_getCurrentTBDB", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.LatestBlock{Height: 1, Hash: common.HexToHash("0x01"), DaemonBlockHash: common.HexToHash("0xd1")}, nil
+	})
+	httpApi := client.NewHttpApi(&client.HttpApiInitParam{HttpUrl: node.server.URL})
+
+	t.Run("shared between replicas", func(t *testing.T) {
+		a := NewRedisBlockCache(redisClient, nil, time.Minute, time.Minute)
+		b := NewRedisBlockCache(redisClient, nil, time.Minute, time.Minute)
+		a.SetHttpApi(httpApi)
+		b.SetHttpApi(httpApi)
+
+		fetched, err := b.GetBlock(chainId, "zltc_a")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), fetched.Height)
+
+		assert.NoError(t, a.SetBlock(chainId, "zltc_a", &types.LatestBlock{Height: 10, Hash: common.HexToHash("0x0a")}))
+		cached, err := b.GetBlock(chainId, "zltc_a")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(10), cached.Height)
+		assert.Equal(t, common.HexToHash("0x0a"), cached.Hash)
+
+		// 更低的高度被拒绝
+		assert.ErrorIs(t, b.SetBlock(chainId, "zltc_a", &types.LatestBlock{Height: 9}), ErrStaleBlockCache)
+
+		assert.NoError(t, b.Invalidate(chainId, "zltc_a"))
+		fetched, err = a.GetBlock(chainId, "zltc_a")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(1), fetched.Height)
+	})
+
+	t.Run("fencing token", func(t *testing.T) {
+		stale := NewRedisAccountLock(redisClient, time.Minute)
+		fresh := NewRedisAccountLock(redisClient, time.Minute)
+		staleCache := NewRedisBlockCache(redisClient, stale, time.Minute, time.Minute)
+		freshCache := NewRedisBlockCache(redisClient, fresh, time.Minute, time.Minute)
+
+		stale.Obtain(chainId, "zltc_b")
+		stale.Unlock(chainId, "zltc_b")
+		fresh.Obtain(chainId, "zltc_b")
+		assert.NoError(t, freshCache.SetBlock(chainId, "zltc_b", &types.LatestBlock{Height: 2}))
+		fresh.Unlock(chainId, "zltc_b")
+
+		// 模拟旧持有者仍持有过期的令牌
+		stale.(*redisAccountLock).holders.Store(chainId+"_zltc_b", &redisLockHolder{token: 1})
+		assert.ErrorIs(t, staleCache.SetBlock(chainId, "zltc_b", &types.LatestBlock{Height: 3}), ErrStaleBlockCache)
+
+		// 清除缓存后保留栅栏令牌，重新同步后旧持有者仍无法写入
+		assert.NoError(t, freshCache.Invalidate(chainId, "zltc_b"))
+		assert.ErrorIs(t, staleCache.SetBlock(chainId, "zltc_b", &types.LatestBlock{Height: 3}), ErrStaleBlockCache)
+	})
+}
@@ -1 +1,35 @@
 package lattice
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryBlockCache_Invalidate(t *testing.T) {
+	node := newMockNode(t)
+	node.handle("latc_getCurrentTBDB", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.LatestBlock{Height: 1, Hash: common.HexToHash("0x01")}, nil
+	})
+	blockCache := NewMemoryBlockCache(10*time.Second, time.Minute, time.Minute)
+	blockCache.SetHttpApi(client.NewHttpApi(&client.HttpApiInitParam{HttpUrl: node.server.URL}))
+
+	assert.NoError(t, blockCache.SetBlock(chainId, "zltc_a", &types.LatestBlock{Height: 10}))
+	cached, err := blockCache.GetBlock(chainId, "zltc_a")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10), cached.Height)
+
+	assert.NoError(t, blockCache.Invalidate(chainId, "zltc_a"))
+	fetched, err := blockCache.GetBlock(chainId, "zltc_a")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), fetched.Height)
+	assert.Equal(t, 1, node.callCount("latc_getCurrentTBDB"))
+
+	// 清除不存在的缓存不报错
+	assert.NoError(t, blockCache.Invalidate(chainId, "zltc_b"))
+}
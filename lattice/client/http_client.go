@@ -46,9 +46,9 @@ type JsonRpcError struct {
 	Message string `json:"message,omitempty"`
 }
 
-// Error returns the error message.
+// Error returns the error as a *RpcError, which can be matched with errors.Is / errors.As.
 func (e *JsonRpcError) Error() error {
-	return fmt.Errorf("%d:%s", e.Code, e.Message)
+	return NewRpcError(e.Code, e.Message)
 }
 
 // NewJsonRpcBody creates a new JSON-RPC body.
@@ -178,6 +178,7 @@ type HttpApiInitParam struct {
 	Transport                  http.RoundTripper // tr
 	JwtSecret                  string            // jwt的secret信息
 	JwtTokenExpirationDuration time.Duration     // jwt token的过期时间
+	Middlewares                []Middleware      // JsonRpc请求的中间件，按顺序由外到内包装请求
 }
 
 // NewHttpApi creates a new HTTP API for the Lattice node.
@@ -191,6 +192,7 @@ func NewHttpApi(args *HttpApiInitParam) HttpApi {
 		GinServerUrl: args.GinServerUrl,
 		transport:    args.Transport,
 		jwtApi:       NewJwt(args.JwtSecret, args.JwtTokenExpirationDuration),
+		handler:      Chain(postHandler, args.Middlewares...),
 	}
 }
 
@@ -210,6 +212,9 @@ type HttpApi interface {
 	SendSignedTransaction(ctx context.Context, chainId string, signedTX *block.Transaction) (*common.Hash, error)
 	// SendSignedTransactions batch send transactions
 	SendSignedTransactions(ctx context.Context, chainId string, signedTXs []*block.Transaction) ([]*common.Hash, error)
+	// BatchCall 将多个JsonRpc请求合并为一次http请求发送，每个请求的结果和错误写入对应的 BatchElem，
+	// 类型安全的用法见 NewBatch
+	BatchCall(ctx context.Context, chainId string, elems []*BatchElem) error
 	// PreCallContract 预执行合约
 	PreCallContract(ctx context.Context, chainId string, unsignedTX *block.Transaction) (*types.Receipt, error)
 	// GetReceipt 获取交易回执
@@ -393,6 +398,7 @@ type httpApi struct {
 	GinServerUrl string            // 节点的Gin服务请求路径
 	transport    http.RoundTripper // http transport
 	jwtApi       Jwt               // jwt api
+	handler      RpcHandler        // 经过中间件包装的JsonRpc请求处理函数
 }
 
 func (api *httpApi) forwardErrorHandler(rw http.ResponseWriter, _ *http.Request, err error) {
@@ -466,7 +472,7 @@ func (api *httpApi) CanDial(timeout time.Duration) error {
 }
 
 func (api *httpApi) SendSignedTransaction(ctx context.Context, chainId string, signedTX *block.Transaction) (*common.Hash, error) {
-	response, err := Post[common.Hash](ctx, api.NodeUrl, NewJsonRpcBody("wallet_sendRawTBlock", signedTX), api.newHeaders(chainId), api.transport)
+	response, err := post[common.Hash](ctx, api, NewJsonRpcBody("wallet_sendRawTBlock", signedTX), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -477,7 +483,7 @@ func (api *httpApi) SendSignedTransaction(ctx context.Context, chainId string, s
 }
 
 func (api *httpApi) SendSignedTransactions(ctx context.Context, chainId string, signedTXs []*block.Transaction) ([]*common.Hash, error) {
-	response, err := Post[[]*common.Hash](ctx, api.NodeUrl, NewJsonRpcBody("wallet_sendRawBatchTBlock", signedTXs), api.newHeaders(chainId), api.transport)
+	response, err := post[[]*common.Hash](ctx, api, NewJsonRpcBody("wallet_sendRawBatchTBlock", signedTXs), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -488,7 +494,7 @@ func (api *httpApi) SendSignedTransactions(ctx context.Context, chainId string,
 }
 
 func (api *httpApi) PreCallContract(ctx context.Context, chainId string, unsignedTX *block.Transaction) (*types.Receipt, error) {
-	response, err := Post[types.Receipt](ctx, api.NodeUrl, NewJsonRpcBody("wallet_preExecuteContract", unsignedTX), api.newHeaders(chainId), api.transport)
+	response, err := post[types.Receipt](ctx, api, NewJsonRpcBody("wallet_preExecuteContract", unsignedTX), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -499,7 +505,7 @@ func (api *httpApi) PreCallContract(ctx context.Context, chainId string, unsigne
 }
 
 func (api *httpApi) GetReceipt(ctx context.Context, chainId, hash string) (*types.Receipt, error) {
-	response, err := Post[types.Receipt](ctx, api.NodeUrl, NewJsonRpcBody("latc_getReceipt", hash), api.newHeaders(chainId), api.transport)
+	response, err := post[types.Receipt](ctx, api, NewJsonRpcBody("latc_getReceipt", hash), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -510,7 +516,7 @@ func (api *httpApi) GetReceipt(ctx context.Context, chainId, hash string) (*type
 }
 
 func (api *httpApi) GetReceipts(ctx context.Context, chainId string, hashes []string) ([]*types.Receipt, error) {
-	response, err := Post[[]*types.Receipt](ctx, api.NodeUrl, NewJsonRpcBody("latc_getTBlockReceipts", hashes), api.newHeaders(chainId), api.transport)
+	response, err := post[[]*types.Receipt](ctx, api, NewJsonRpcBody("latc_getTBlockReceipts", hashes), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -521,9 +527,9 @@ func (api *httpApi) GetReceipts(ctx context.Context, chainId string, hashes []st
 }
 
 func (api *httpApi) ExistsBusinessContractAddress(ctx context.Context, chainId, address string) (bool, error) {
-	response, err := Post[bool](ctx, api.NodeUrl, NewJsonRpcBody("wallet_confirmTaggedContract", address), api.newHeaders(chainId), api.transport)
+	response, err := post[bool](ctx, api, NewJsonRpcBody("wallet_confirmTaggedContract", address), api.newHeaders(chainId))
 	if err != nil {
-		return false, nil
+		return false, err
 	}
 	if response.Error != nil {
 		return false, response.Error.Error()
@@ -532,7 +538,7 @@ func (api *httpApi) ExistsBusinessContractAddress(ctx context.Context, chainId,
 }
 
 func (api *httpApi) GetEvidences(ctx context.Context, chainId, date string, evidenceType types.EvidenceType, page, pageSize int) (*types.Evidences, error) {
-	response, err := Post[types.Evidences](ctx, api.NodeUrl, NewJsonRpcBody("latc_getEvidences", date, evidenceType, page, pageSize), api.newHeaders(chainId), api.transport)
+	response, err := post[types.Evidences](ctx, api, NewJsonRpcBody("latc_getEvidences", date, evidenceType, page, pageSize), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -543,7 +549,7 @@ func (api *httpApi) GetEvidences(ctx context.Context, chainId, date string, evid
 }
 
 func (api *httpApi) GetErrorEvidences(ctx context.Context, chainId, date string, evidenceLevel types.EvidenceLevel, evidenceType types.EvidenceType, page, pageSize int) (*types.Evidences, error) {
-	response, err := Post[types.Evidences](ctx, api.NodeUrl, NewJsonRpcBody("latc_getErrorEvidences", date, evidenceLevel, evidenceType, page, pageSize), api.newHeaders(chainId), api.transport)
+	response, err := post[types.Evidences](ctx, api, NewJsonRpcBody("latc_getErrorEvidences", date, evidenceLevel, evidenceType, page, pageSize), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -554,7 +560,7 @@ func (api *httpApi) GetErrorEvidences(ctx context.Context, chainId, date string,
 }
 
 func (api *httpApi) GetElapsed(ctx context.Context) (map[string]int64, error) {
-	response, err := Post[map[string]int64](ctx, api.NodeUrl, NewJsonRpcBody("latc_getElapsed"), api.newHeaders(emptyChainId), api.transport)
+	response, err := post[map[string]int64](ctx, api, NewJsonRpcBody("latc_getElapsed"), api.newHeaders(emptyChainId))
 	if err != nil {
 		return nil, err
 	}
@@ -566,7 +572,7 @@ func (api *httpApi) GetElapsed(ctx context.Context) (map[string]int64, error) {
 
 // GetConsensus implements HttpApi.
 func (api *httpApi) GetConsensus(ctx context.Context) (*types.LatcConsensus, error) {
-	response, err := Post[types.LatcConsensus](ctx, api.NodeUrl, NewJsonRpcBody("latc_getConsensus"), api.newHeaders(emptyChainId), api.transport)
+	response, err := post[types.LatcConsensus](ctx, api, NewJsonRpcBody("latc_getConsensus"), api.newHeaders(emptyChainId))
 	if err != nil {
 		return nil, err
 	}
@@ -578,7 +584,7 @@ func (api *httpApi) GetConsensus(ctx context.Context) (*types.LatcConsensus, err
 
 // GetSyncStatus implements HttpApi.
 func (api *httpApi) GetSyncStatus(ctx context.Context) (*types.SyncStatus, error) {
-	response, err := Post[types.SyncStatus](ctx, api.NodeUrl, NewJsonRpcBody("sync_status"), api.newHeaders(emptyChainId), api.transport)
+	response, err := post[types.SyncStatus](ctx, api, NewJsonRpcBody("sync_status"), api.newHeaders(emptyChainId))
 	if err != nil {
 		return nil, err
 	}
@@ -605,17 +611,33 @@ func Post[T any](ctx context.Context, url string, jsonRpcBody *JsonRpcBody, head
 	if err != nil {
 		return nil, err
 	}
+	return unmarshalResponse[T](response)
+}
+
+// post 通过httpApi的中间件链发送JsonRpc请求
+func post[T any](ctx context.Context, api *httpApi, jsonRpcBody *JsonRpcBody, headers map[string]string) (*JsonRpcResponse[*T], error) {
+	response, err := api.handler(ctx, &RpcRequest{
+		NodeUrl:   api.NodeUrl,
+		Body:      jsonRpcBody,
+		Headers:   headers,
+		Transport: api.transport,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalResponse[T](response)
+}
 
+func unmarshalResponse[T any](response []byte) (*JsonRpcResponse[*T], error) {
 	var t JsonRpcResponse[*T]
-	if err = json.Unmarshal(response, &t); err != nil {
+	if err := json.Unmarshal(response, &t); err != nil {
 		log.Error().Err(err).Msg("Failed to unmarshal response body")
 		return nil, err
 	}
-
 	return &t, nil
 }
 
-func rawPost(ctx context.Context, baseUrl string, jsonRpcBody *JsonRpcBody, headers map[string]string, tr http.RoundTripper) ([]byte, error) {
+func rawPost(ctx context.Context, baseUrl string, jsonRpcBody any, headers map[string]string, tr http.RoundTripper) ([]byte, error) {
 	log.Debug().Msgf("开始发送JsonRpc请求，url: %s, body: %+v", baseUrl, jsonRpcBody)
 	bodyBytes, err := json.Marshal(jsonRpcBody)
 	if err != nil {
@@ -639,7 +661,7 @@ func rawPost(ctx context.Context, baseUrl string, jsonRpcBody *JsonRpcBody, head
 	response, err := client.Do(request)
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to send http request")
-		return nil, fmt.Errorf("请求节点失败：%s", err)
+		return nil, fmt.Errorf("%w：%w", ErrNodeUnavailable, err)
 	}
 	defer func(Body io.ReadCloser) {
 		if err := Body.Close(); err != nil {
@@ -647,10 +669,14 @@ func rawPost(ctx context.Context, baseUrl string, jsonRpcBody *JsonRpcBody, head
 		}
 	}(response.Body)
 
-	if res, err := io.ReadAll(response.Body); err != nil {
+	res, err := io.ReadAll(response.Body)
+	if err != nil {
 		log.Error().Err(err).Msg("Failed to read response body")
 		return nil, err
-	} else {
-		return res, nil
 	}
+	if response.StatusCode >= http.StatusInternalServerError {
+		log.Error().Msgf("节点返回了异常的http状态码，url: %s, status: %d", baseUrl, response.StatusCode)
+		return nil, &HttpStatusError{StatusCode: response.StatusCode, Body: res}
+	}
+	return res, nil
 }
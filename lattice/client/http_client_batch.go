@@ -0,0 +1,268 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/rs/zerolog/log"
+)
+
+// BatchResponse 批量请求中单个请求的结果
+type BatchResponse[T any] struct {
+	Result *T    // 请求结果
+	Error  error // 节点返回的JsonRpc错误、响应缺失或反序列化失败
+}
+
+// BatchPost send a batch of JSON-RPC requests in one http request
+//
+// 按顺序为每个请求分配唯一的id，按id匹配响应，返回的结果与请求一一对应，不会修改传入的请求体
+//
+// Parameters:
+//   - ctx context.Context: 超时取消
+//   - url string: 请求路径，示例：http://192.168.1.20:13000
+//   - jsonRpcBodies []*JsonRpcBody: 请求体列表
+//   - headers map[string]string: 请求头
+//   - tr http.RoundTripper
+//
+// Returns:
+//   - []*BatchResponse[T]: 每个请求的结果或错误
+//   - error: 请求节点失败或响应无法解析时返回
+func BatchPost[T any](ctx context.Context, url string, jsonRpcBodies []*JsonRpcBody, headers map[string]string, tr http.RoundTripper) ([]*BatchResponse[T], error) {
+	return batchPost[T](ctx, func(ctx context.Context, batch []*JsonRpcBody) ([]byte, error) {
+		return rawPost(ctx, url, batch, headers, tr)
+	}, jsonRpcBodies)
+}
+
+func batchPost[T any](ctx context.Context, send func(ctx context.Context, batch []*JsonRpcBody) ([]byte, error), jsonRpcBodies []*JsonRpcBody) ([]*BatchResponse[T], error) {
+	if len(jsonRpcBodies) == 0 {
+		return nil, nil
+	}
+	batch := make([]*JsonRpcBody, len(jsonRpcBodies))
+	for i, body := range jsonRpcBodies {
+		batch[i] = &JsonRpcBody{Id: i + 1, JsonRpc: "2.0", Method: body.Method, Params: body.Params}
+	}
+
+	response, err := send(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+	var rawResponses []JsonRpcResponse[json.RawMessage]
+	if err := json.Unmarshal(response, &rawResponses); err != nil {
+		// 节点不支持批量请求时，可能返回单个错误响应
+		var single JsonRpcResponse[json.RawMessage]
+		if json.Unmarshal(response, &single) == nil && single.Error != nil {
+			return nil, single.Error.Error()
+		}
+		log.Error().Err(err).Msg("Failed to unmarshal batch response body")
+		return nil, err
+	}
+
+	byId := make(map[int]*JsonRpcResponse[json.RawMessage], len(rawResponses))
+	for i := range rawResponses {
+		byId[rawResponses[i].Id] = &rawResponses[i]
+	}
+	results := make([]*BatchResponse[T], len(batch))
+	for i, body := range batch {
+		result := new(BatchResponse[T])
+		results[i] = result
+		rawResponse, ok := byId[body.Id]
+		switch {
+		case !ok:
+			result.Error = fmt.Errorf("批量请求中缺少方法%s的响应，id: %d", body.Method, body.Id)
+		case rawResponse.Error != nil:
+			result.Error = rawResponse.Error.Error()
+		case len(rawResponse.Result) == 0 || string(rawResponse.Result) == "null":
+		default:
+			result.Result = new(T)
+			if err := json.Unmarshal(rawResponse.Result, result.Result); err != nil {
+				result.Result = nil
+				result.Error = fmt.Errorf("反序列化方法%s的响应失败：%w", body.Method, err)
+			}
+		}
+	}
+	return results, nil
+}
+
+// BatchElem 批量请求中的一个请求
+type BatchElem struct {
+	Method string        // JsonRpc方法名
+	Params []interface{} // 方法参数
+	Result interface{}   // 用于反序列化请求结果的指针，为nil时忽略结果
+	Error  error         // 请求完成后，该请求的错误
+}
+
+func (api *httpApi) BatchCall(ctx context.Context, chainId string, elems []*BatchElem) error {
+	bodies := make([]*JsonRpcBody, len(elems))
+	for i, elem := range elems {
+		bodies[i] = NewJsonRpcBody(elem.Method, elem.Params...)
+	}
+	headers := api.newHeaders(chainId)
+	responses, err := batchPost[json.RawMessage](ctx, func(ctx context.Context, batch []*JsonRpcBody) ([]byte, error) {
+		return api.handler(ctx, &RpcRequest{
+			NodeUrl:   api.NodeUrl,
+			Batch:     batch,
+			Headers:   headers,
+			Transport: api.transport,
+		})
+	}, bodies)
+	if err != nil {
+		return err
+	}
+	for i, elem := range elems {
+		elem.Error = responses[i].Error
+		if elem.Error != nil || elem.Result == nil || responses[i].Result == nil {
+			continue
+		}
+		if err := json.Unmarshal(*responses[i].Result, elem.Result); err != nil {
+			elem.Error = fmt.Errorf("反序列化方法%s的响应失败：%w", elem.Method, err)
+		}
+	}
+	return nil
+}
+
+// Batch 类型安全的批量请求构造器，将多个查询合并为一次http请求
+//
+// Example:
+//
+//	batch := client.NewBatch(api, chainId)
+//	receipt := batch.GetReceipt(hash)
+//	daemonBlock := batch.GetDaemonBlockByHeight(100)
+//	if err := batch.Send(ctx); err != nil {
+//		return err
+//	}
+//	r, err := receipt.Get()
+type Batch struct {
+	api     HttpApi
+	chainId string
+	elems   []*BatchElem
+}
+
+// NewBatch 创建批量请求构造器
+//
+// Parameters:
+//   - api HttpApi: 发送批量请求的客户端
+//   - chainId string: 链ID，同一批请求只能发往同一条链
+//
+// Returns:
+//   - *Batch
+func NewBatch(api HttpApi, chainId string) *Batch {
+	return &Batch{api: api, chainId: chainId}
+}
+
+// BatchResult 已加入批量请求的一个请求，Batch.Send 之后可读取结果
+type BatchResult[T any] struct {
+	elem   *BatchElem
+	result *T
+}
+
+// Get 获取请求的结果
+//
+// Returns:
+//   - *T: 节点返回null时为nil
+//   - error: 该请求的错误，批量请求尚未发送时也返回错误
+func (r *BatchResult[T]) Get() (*T, error) {
+	if r.elem.Error != nil {
+		return nil, r.elem.Error
+	}
+	return r.result, nil
+}
+
+// Queue 将任意方法加入批量请求
+//
+// Parameters:
+//   - batch *Batch
+//   - method string: JsonRpc方法名
+//   - params ...interface{}: 方法参数
+//
+// Returns:
+//   - *BatchResult[T]
+func Queue[T any](batch *Batch, method string, params ...interface{}) *BatchResult[T] {
+	result := new(BatchResult[T])
+	result.elem = &BatchElem{Method: method, Params: params, Result: &result.result, Error: errBatchNotSent}
+	batch.elems = append(batch.elems, result.elem)
+	return result
+}
+
+var errBatchNotSent = errors.New("批量请求尚未发送")
+
+// Len 已加入的请求数量
+func (b *Batch) Len() int {
+	return len(b.elems)
+}
+
+// Send 发送批量请求，每个请求的结果通过对应的 BatchResult 读取
+//
+// Parameters:
+//   - ctx context.Context
+//
+// Returns:
+//   - error: 请求节点失败时返回，单个请求的错误通过 BatchResult.Get 返回
+func (b *Batch) Send(ctx context.Context) error {
+	if len(b.elems) == 0 {
+		return nil
+	}
+	for _, elem := range b.elems {
+		elem.Error = nil
+	}
+	if err := b.api.BatchCall(ctx, b.chainId, b.elems); err != nil {
+		for _, elem := range b.elems {
+			elem.Error = err
+		}
+		return err
+	}
+	return nil
+}
+
+// GetReceipt 加入查询交易回执的请求
+func (b *Batch) GetReceipt(hash string) *BatchResult[types.Receipt] {
+	return Queue[types.Receipt](b, "latc_getReceipt", hash)
+}
+
+// GetTransactionBlockByHash 加入根据哈希查询交易区块的请求
+func (b *Batch) GetTransactionBlockByHash(hash string) *BatchResult[types.TransactionBlock] {
+	return Queue[types.TransactionBlock](b, "latc_getTBlockByHash", hash)
+}
+
+// GetTBlockByHeight 加入查询账户指定高度的交易区块的请求
+func (b *Batch) GetTBlockByHeight(accountAddress string, height uint64) *BatchResult[types.TransactionBlock] {
+	return Queue[types.TransactionBlock](b, "latc_getTBlockByNumber", accountAddress, height)
+}
+
+// GetDaemonBlockByHash 加入根据哈希查询守护区块的请求
+func (b *Batch) GetDaemonBlockByHash(hash string) *BatchResult[types.DaemonBlock] {
+	return Queue[types.DaemonBlock](b, "latc_getDBlockByHash", hash)
+}
+
+// GetDaemonBlockByHeight 加入根据高度查询守护区块的请求
+func (b *Batch) GetDaemonBlockByHeight(height uint64) *BatchResult[types.DaemonBlock] {
+	return Queue[types.DaemonBlock](b, "latc_getDBlockByNumber", height)
+}
+
+// GetLatestBlock 加入查询账户最新区块的请求，不包括pending中的交易
+func (b *Batch) GetLatestBlock(accountAddress string) *BatchResult[types.LatestBlock] {
+	return Queue[types.LatestBlock](b, "latc_getCurrentTBDB", accountAddress)
+}
+
+// GetLatestBlockWithPending 加入查询账户最新区块的请求，包括pending中的交易
+func (b *Batch) GetLatestBlockWithPending(accountAddress string) *BatchResult[types.LatestBlock] {
+	return Queue[types.LatestBlock](b, "latc_getPendingTBDB", accountAddress)
+}
+
+// GetBalanceWithPending 加入查询账户余额的请求
+func (b *Batch) GetBalanceWithPending(accountAddress string) *BatchResult[types.AccountBalance] {
+	return Queue[types.AccountBalance](b, "latc_getBalanceWithPending", accountAddress)
+}
+
+// GetContractInformation 加入查询合约信息的请求
+func (b *Batch) GetContractInformation(contractAddress string) *BatchResult[types.ContractInformation] {
+	return Queue[types.ContractInformation](b, "wallet_getContractState", contractAddress)
+}
+
+// GetTBlockState 加入查询交易区块状态的请求
+func (b *Batch) GetTBlockState(hash string) *BatchResult[types.TBlockState] {
+	return Queue[types.TBlockState](b, "latc_getTBlockState", hash)
+}
@@ -10,7 +10,7 @@ import (
 )
 
 func (api *httpApi) GetLatestBlock(ctx context.Context, chainId, accountAddress string) (*types.LatestBlock, error) {
-	response, err := Post[types.LatestBlock](ctx, api.NodeUrl, NewJsonRpcBody("latc_getCurrentTBDB", accountAddress), api.newHeaders(chainId), api.transport)
+	response, err := post[types.LatestBlock](ctx, api, NewJsonRpcBody("latc_getCurrentTBDB", accountAddress), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -21,7 +21,7 @@ func (api *httpApi) GetLatestBlock(ctx context.Context, chainId, accountAddress
 }
 
 func (api *httpApi) GetLatestBlockWithPending(ctx context.Context, chainId, accountAddress string) (*types.LatestBlock, error) {
-	response, err := Post[types.LatestBlock](ctx, api.NodeUrl, NewJsonRpcBody("latc_getPendingTBDB", accountAddress), api.newHeaders(chainId), api.transport)
+	response, err := post[types.LatestBlock](ctx, api, NewJsonRpcBody("latc_getPendingTBDB", accountAddress), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -32,7 +32,7 @@ func (api *httpApi) GetLatestBlockWithPending(ctx context.Context, chainId, acco
 }
 
 func (api *httpApi) GetLatestDaemonBlock(ctx context.Context, chainID string) (*types.DaemonBlock, error) {
-	response, err := Post[types.DaemonBlock](ctx, api.NodeUrl, NewJsonRpcBody("latc_getCurrentDBlock"), api.newHeaders(chainID), api.transport)
+	response, err := post[types.DaemonBlock](ctx, api, NewJsonRpcBody("latc_getCurrentDBlock"), api.newHeaders(chainID))
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +43,7 @@ func (api *httpApi) GetLatestDaemonBlock(ctx context.Context, chainID string) (*
 }
 
 func (api *httpApi) GetDaemonBlockByHash(ctx context.Context, chainId, hash string) (*types.DaemonBlock, error) {
-	response, err := Post[types.DaemonBlock](ctx, api.NodeUrl, NewJsonRpcBody("latc_getDBlockByHash", hash), api.newHeaders(chainId), api.transport)
+	response, err := post[types.DaemonBlock](ctx, api, NewJsonRpcBody("latc_getDBlockByHash", hash), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +54,7 @@ func (api *httpApi) GetDaemonBlockByHash(ctx context.Context, chainId, hash stri
 }
 
 func (api *httpApi) GetDaemonBlockByHeight(ctx context.Context, chainId string, height uint64) (*types.DaemonBlock, error) {
-	response, err := Post[types.DaemonBlock](ctx, api.NodeUrl, NewJsonRpcBody("latc_getDBlockByNumber", height), api.newHeaders(chainId), api.transport)
+	response, err := post[types.DaemonBlock](ctx, api, NewJsonRpcBody("latc_getDBlockByNumber", height), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -65,7 +65,7 @@ func (api *httpApi) GetDaemonBlockByHeight(ctx context.Context, chainId string,
 }
 
 func (api *httpApi) GetTransactionBlockByHash(ctx context.Context, chainId, hash string) (*types.TransactionBlock, error) {
-	response, err := Post[types.TransactionBlock](ctx, api.NodeUrl, NewJsonRpcBody("latc_getTBlockByHash", hash), api.newHeaders(chainId), api.transport)
+	response, err := post[types.TransactionBlock](ctx, api, NewJsonRpcBody("latc_getTBlockByHash", hash), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +76,7 @@ func (api *httpApi) GetTransactionBlockByHash(ctx context.Context, chainId, hash
 }
 
 func (api *httpApi) GetTransactionsPagination(ctx context.Context, chainId string, startDaemonBlockHeight uint64, pageSize uint16) (*types.TransactionsPagination, error) {
-	response, err := Post[types.TransactionsPagination](ctx, api.NodeUrl, NewJsonRpcBody("latc_getTBlockPagesByDNumber", startDaemonBlockHeight, pageSize), api.newHeaders(chainId), api.transport)
+	response, err := post[types.TransactionsPagination](ctx, api, NewJsonRpcBody("latc_getTBlockPagesByDNumber", startDaemonBlockHeight, pageSize), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +87,7 @@ func (api *httpApi) GetTransactionsPagination(ctx context.Context, chainId strin
 }
 
 func (api *httpApi) GetTBlockState(ctx context.Context, chainId, hash string) (types.TBlockState, error) {
-	response, err := Post[types.TBlockState](ctx, api.NodeUrl, NewJsonRpcBody("latc_getTBlockState", hash), api.newHeaders(chainId), api.transport)
+	response, err := post[types.TBlockState](ctx, api, NewJsonRpcBody("latc_getTBlockState", hash), api.newHeaders(chainId))
 	if err != nil {
 		return types.TBlockStateEMPTY, err
 	}
@@ -99,7 +99,7 @@ func (api *httpApi) GetTBlockState(ctx context.Context, chainId, hash string) (t
 
 // GetLastBatchDBlockNumber implements HttpApi.
 func (api *httpApi) GetLastBatchDBlockNumber(ctx context.Context, chainId string) (*big.Int, error) {
-	response, err := Post[big.Int](ctx, api.NodeUrl, NewJsonRpcBody("latc_getLastedBatchDBNumber"), api.newHeaders(chainId), api.transport)
+	response, err := post[big.Int](ctx, api, NewJsonRpcBody("latc_getLastedBatchDBNumber"), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -111,7 +111,7 @@ func (api *httpApi) GetLastBatchDBlockNumber(ctx context.Context, chainId string
 
 // GetDBlockProof implements HttpApi.
 func (api *httpApi) GetDBlockProof(ctx context.Context, chainId string, dblockNumber *big.Int) (*types.WitnessProof, error) {
-	response, err := Post[types.WitnessProof](ctx, api.NodeUrl, NewJsonRpcBody("latc_getDBlockProof", dblockNumber), api.newHeaders(chainId), api.transport)
+	response, err := post[types.WitnessProof](ctx, api, NewJsonRpcBody("latc_getDBlockProof", dblockNumber), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -123,7 +123,7 @@ func (api *httpApi) GetDBlockProof(ctx context.Context, chainId string, dblockNu
 
 // GetTBlockProof implements HttpApi.
 func (api *httpApi) GetTBlockProof(ctx context.Context, chainId string, accountAddress string, tblockNumber *big.Int) (*types.WitnessProof, error) {
-	response, err := Post[types.WitnessProof](ctx, api.NodeUrl, NewJsonRpcBody("latc_getTBlockProof", accountAddress, tblockNumber), api.newHeaders(chainId), api.transport)
+	response, err := post[types.WitnessProof](ctx, api, NewJsonRpcBody("latc_getTBlockProof", accountAddress, tblockNumber), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -134,7 +134,7 @@ func (api *httpApi) GetTBlockProof(ctx context.Context, chainId string, accountA
 }
 
 func (api *httpApi) GetCurrentTBlock(ctx context.Context, chainId string, accountAddress string) (*types.TransactionBlock, error) {
-	response, err := Post[types.TransactionBlock](ctx, api.NodeUrl, NewJsonRpcBody("latc_getCurrentTBlock", accountAddress), api.newHeaders(chainId), api.transport)
+	response, err := post[types.TransactionBlock](ctx, api, NewJsonRpcBody("latc_getCurrentTBlock", accountAddress), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -145,7 +145,7 @@ func (api *httpApi) GetCurrentTBlock(ctx context.Context, chainId string, accoun
 }
 
 func (api *httpApi) GetTBlockByHeight(ctx context.Context, chainId, accountAddress string, height uint64) (*types.TransactionBlock, error) {
-	response, err := Post[types.TransactionBlock](ctx, api.NodeUrl, NewJsonRpcBody("latc_getTBlockByNumber", accountAddress, height), api.newHeaders(chainId), api.transport)
+	response, err := post[types.TransactionBlock](ctx, api, NewJsonRpcBody("latc_getTBlockByNumber", accountAddress, height), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -156,7 +156,7 @@ func (api *httpApi) GetTBlockByHeight(ctx context.Context, chainId, accountAddre
 }
 
 func (api *httpApi) GetGenesisBlock(ctx context.Context, chainId string) (*types.TransactionBlock, error) {
-	response, err := Post[types.TransactionBlock](ctx, api.NodeUrl, NewJsonRpcBody("latc_getGenesis"), api.newHeaders(chainId), api.transport)
+	response, err := post[types.TransactionBlock](ctx, api, NewJsonRpcBody("latc_getGenesis"), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -167,7 +167,7 @@ func (api *httpApi) GetGenesisBlock(ctx context.Context, chainId string) (*types
 }
 
 func (api *httpApi) GetTBlocksByHeights(ctx context.Context, chainId string, accountAddress string, heights []uint64) ([]*types.TransactionBlock, error) {
-	response, err := Post[[]*types.TransactionBlock](ctx, api.NodeUrl, NewJsonRpcBody("latc_getTBlockByNumberRange", accountAddress, heights), api.newHeaders(chainId), api.transport)
+	response, err := post[[]*types.TransactionBlock](ctx, api, NewJsonRpcBody("latc_getTBlockByNumberRange", accountAddress, heights), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -178,7 +178,7 @@ func (api *httpApi) GetTBlocksByHeights(ctx context.Context, chainId string, acc
 }
 
 func (api *httpApi) GetDBlocksByHeights(ctx context.Context, chainId string, heights []uint64) ([]*types.DaemonBlock, error) {
-	response, err := Post[[]*types.DaemonBlock](ctx, api.NodeUrl, NewJsonRpcBody("latc_getDBlockByNumberRange", heights), api.newHeaders(chainId), api.transport)
+	response, err := post[[]*types.DaemonBlock](ctx, api, NewJsonRpcBody("latc_getDBlockByNumberRange", heights), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -189,7 +189,7 @@ func (api *httpApi) GetDBlocksByHeights(ctx context.Context, chainId string, hei
 }
 
 func (api *httpApi) GetRecentDBlocks(ctx context.Context, chainId string, limit uint32) ([]*types.DaemonBlock, error) {
-	response, err := Post[[]*types.DaemonBlock](ctx, api.NodeUrl, NewJsonRpcBody("latc_getRecentDBlocks", limit), api.newHeaders(chainId), api.transport)
+	response, err := post[[]*types.DaemonBlock](ctx, api, NewJsonRpcBody("latc_getRecentDBlocks", limit), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -200,7 +200,7 @@ func (api *httpApi) GetRecentDBlocks(ctx context.Context, chainId string, limit
 }
 
 func (api *httpApi) GetTBlockCount(ctx context.Context, chainId string) (*types.TBlockCount, error) {
-	response, err := Post[types.TBlockCount](ctx, api.NodeUrl, NewJsonRpcBody("latc_getTBlockCount"), api.newHeaders(chainId), api.transport)
+	response, err := post[types.TBlockCount](ctx, api, NewJsonRpcBody("latc_getTBlockCount"), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -10,12 +10,11 @@ func (api *httpApi) GetCreateContractSolidity(
 	ctx context.Context,
 	params *types.CreateDataContractParams,
 ) (string, error) {
-	response, err := Post[string](
+	response, err := post[string](
 		ctx,
-		api.NodeUrl,
+		api,
 		NewJsonRpcBody("wallet_getCreateContractSolidity", params),
 		api.newHeaders(""),
-		api.transport,
 	)
 	if err != nil {
 		return "", err
@@ -8,7 +8,7 @@ import (
 )
 
 func (api *httpApi) GetContractInformation(ctx context.Context, chainID, contractAddress string) (*types.ContractInformation, error) {
-	response, err := Post[types.ContractInformation](ctx, api.NodeUrl, NewJsonRpcBody("wallet_getContractState", contractAddress), api.newHeaders(chainID), api.transport)
+	response, err := post[types.ContractInformation](ctx, api, NewJsonRpcBody("wallet_getContractState", contractAddress), api.newHeaders(chainID))
 	if err != nil {
 		return nil, err
 	}
@@ -22,9 +22,9 @@ func (api *httpApi) GetContractManagement(ctx context.Context, chainID, contract
 	var err error
 	var response *JsonRpcResponse[*types.ContractManagement]
 	if daemonBlockHeight == nil {
-		response, err = Post[types.ContractManagement](ctx, api.NodeUrl, NewJsonRpcBody("wallet_getPermissionList", contractAddress), api.newHeaders(chainID), api.transport)
+		response, err = post[types.ContractManagement](ctx, api, NewJsonRpcBody("wallet_getPermissionList", contractAddress), api.newHeaders(chainID))
 	} else {
-		response, err = Post[types.ContractManagement](ctx, api.NodeUrl, NewJsonRpcBody("wallet_getPermissionList", contractAddress, daemonBlockHeight), api.newHeaders(chainID), api.transport)
+		response, err = post[types.ContractManagement](ctx, api, NewJsonRpcBody("wallet_getPermissionList", contractAddress, daemonBlockHeight), api.newHeaders(chainID))
 	}
 	if err != nil {
 		return nil, err
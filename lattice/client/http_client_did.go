@@ -7,7 +7,7 @@ import (
 )
 
 func (api *httpApi) GetCurrentIDB(ctx context.Context, chainId, owner string) (*types.CurrentIDB, error) {
-	response, err := Post[types.CurrentIDB](ctx, api.NodeUrl, NewJsonRpcBody("latc_getCurrentIDB", owner), api.newHeaders(chainId), api.transport)
+	response, err := post[types.CurrentIDB](ctx, api, NewJsonRpcBody("latc_getCurrentIDB", owner), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -18,7 +18,7 @@ func (api *httpApi) GetCurrentIDB(ctx context.Context, chainId, owner string) (*
 }
 
 func (api *httpApi) GetDIDBByHash(ctx context.Context, chainId, hash, docHash string) (*types.DIDB, error) {
-	response, err := Post[types.DIDB](ctx, api.NodeUrl, NewJsonRpcBody("latc_getDIDBByHash", hash, docHash), api.newHeaders(chainId), api.transport)
+	response, err := post[types.DIDB](ctx, api, NewJsonRpcBody("latc_getDIDBByHash", hash, docHash), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
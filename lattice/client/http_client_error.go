@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/LatticeBCLab/go-lattice/common/codes"
+	"github.com/LatticeBCLab/go-lattice/common/status"
+)
+
+// 节点常见的失败原因，可通过 errors.Is 判断节点返回的错误
+var (
+	ErrNodeUnavailable     = errors.New("请求节点失败")
+	ErrUnauthorized        = errors.New("节点鉴权失败")
+	ErrMethodNotFound      = errors.New("节点不支持该方法")
+	ErrInvalidSignature    = errors.New("交易签名无效")
+	ErrParentHashMismatch  = errors.New("交易的父哈希或高度与链上不一致")
+	ErrInsufficientBalance = errors.New("账户余额不足")
+	ErrContractNotFound    = errors.New("合约不存在")
+	ErrExecutionReverted   = errors.New("合约执行回滚")
+)
+
+// JsonRpc规范定义的错误码
+const (
+	jsonRpcParseError     = -32700
+	jsonRpcInvalidRequest = -32600
+	jsonRpcMethodNotFound = -32601
+	jsonRpcInvalidParams  = -32602
+	jsonRpcInternalError  = -32603
+)
+
+// rpcErrorKind 根据错误信息中的关键字识别的失败原因
+type rpcErrorKind struct {
+	err      error
+	code     codes.Code
+	keywords []string
+}
+
+var rpcErrorKinds = []rpcErrorKind{
+	{ErrParentHashMismatch, codes.Aborted, []string{"parent hash", "parenthash", "parent block", "invalid number", "invalid height", "height mismatch", "number mismatch", "discontinuous", "父哈希", "高度不"}},
+	{ErrInvalidSignature, codes.Unauthenticated, []string{"invalid signature", "invalid sign", "signature verif", "verify sign", "签名无效", "签名错误", "验签失败"}},
+	{ErrInsufficientBalance, codes.FailedPrecondition, []string{"insufficient balance", "insufficient funds", "not enough balance", "余额不足"}},
+	{ErrContractNotFound, codes.NotFound, []string{"contract not found", "contract does not exist", "contract not exist", "no contract", "合约不存在"}},
+	{ErrExecutionReverted, codes.FailedPrecondition, []string{"execution reverted", "revert"}},
+	{ErrUnauthorized, codes.Unauthenticated, []string{"unauthorized", "token is expired", "invalid token", "jwt"}},
+}
+
+// RpcError 节点返回的JsonRpc错误，保留原始的错误码和错误信息，并映射为 status.Status
+//
+//   - errors.Is(err, client.ErrParentHashMismatch) 判断失败原因
+//   - errors.As(err, &rpcErr) 获取原始的错误码和错误信息
+//   - errors.As(err, &st) 获取映射后的 *status.Status
+type RpcError struct {
+	Code    int16  // JsonRpc错误码
+	Message string // 节点返回的错误信息
+	kind    error  // 识别出的失败原因，未识别时为nil
+	status  *status.Status
+}
+
+// NewRpcError 根据节点返回的错误码和错误信息创建 RpcError
+//
+// Parameters:
+//   - code int16: JsonRpc错误码
+//   - message string: 错误信息
+//
+// Returns:
+//   - *RpcError
+func NewRpcError(code int16, message string) *RpcError {
+	e := &RpcError{Code: code, Message: message}
+	statusCode := codes.Unknown
+	switch code {
+	case jsonRpcParseError, jsonRpcInvalidRequest, jsonRpcInvalidParams:
+		statusCode = codes.InvalidArgument
+	case jsonRpcMethodNotFound:
+		e.kind, statusCode = ErrMethodNotFound, codes.Unimplemented
+	case jsonRpcInternalError:
+		statusCode = codes.Internal
+	}
+	if e.kind == nil {
+		lowerMessage := strings.ToLower(message)
+	match:
+		for _, kind := range rpcErrorKinds {
+			for _, keyword := range kind.keywords {
+				if strings.Contains(lowerMessage, keyword) {
+					e.kind, statusCode = kind.err, kind.code
+					break match
+				}
+			}
+		}
+	}
+	e.status = status.New(statusCode, message)
+	return e
+}
+
+// Error 与之前的错误格式保持一致：错误码:错误信息
+func (e *RpcError) Error() string {
+	return fmt.Sprintf("%d:%s", e.Code, e.Message)
+}
+
+// Unwrap 支持通过 errors.Is 匹配失败原因，通过 errors.As 获取 *status.Status
+func (e *RpcError) Unwrap() []error {
+	if e.kind == nil {
+		return []error{e.status}
+	}
+	return []error{e.kind, e.status}
+}
+
+// Status 映射后的状态
+func (e *RpcError) Status() *status.Status {
+	return e.status
+}
+
+// StatusFromError 将请求节点返回的错误转换为 status.Status，可区分业务错误、网络错误和超时
+//
+// Parameters:
+//   - err error
+//
+// Returns:
+//   - *status.Status: err为nil时返回nil
+func StatusFromError(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+	var st *status.Status
+	switch {
+	case errors.As(err, &st):
+		return st
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.New(codes.DeadlineExceeded, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.New(codes.Canceled, err.Error())
+	case errors.Is(err, ErrNodeUnavailable), errors.Is(err, ErrCircuitOpen), IsRetryableError(err):
+		return status.New(codes.Unavailable, err.Error())
+	default:
+		return status.New(codes.Unknown, err.Error())
+	}
+}
@@ -8,7 +8,7 @@ import (
 )
 
 func (api *httpApi) Freeze(ctx context.Context, chainId string, dblockNumber *big.Int) (uint64, error) {
-	response, err := Post[uint64](ctx, api.NodeUrl, NewJsonRpcBody("latc_freeze", dblockNumber), api.newHeaders(chainId), api.transport)
+	response, err := post[uint64](ctx, api, NewJsonRpcBody("latc_freeze", dblockNumber), api.newHeaders(chainId))
 	if err != nil {
 		return 0, err
 	}
@@ -19,7 +19,7 @@ func (api *httpApi) Freeze(ctx context.Context, chainId string, dblockNumber *bi
 }
 
 func (api *httpApi) GetFreezeDBlockByHash(ctx context.Context, chainId string, hash string) (*types.DaemonBlock, error) {
-	response, err := Post[types.DaemonBlock](ctx, api.NodeUrl, NewJsonRpcBody("latc_getFreezeDBlockByHash", hash), api.newHeaders(chainId), api.transport)
+	response, err := post[types.DaemonBlock](ctx, api, NewJsonRpcBody("latc_getFreezeDBlockByHash", hash), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -30,7 +30,7 @@ func (api *httpApi) GetFreezeDBlockByHash(ctx context.Context, chainId string, h
 }
 
 func (api *httpApi) GetFreezeDBlockByNumber(ctx context.Context, chainId string, dblockNumber *big.Int) (*types.DaemonBlock, error) {
-	response, err := Post[types.DaemonBlock](ctx, api.NodeUrl, NewJsonRpcBody("latc_getFreezeDBlockByNumber", dblockNumber), api.newHeaders(chainId), api.transport)
+	response, err := post[types.DaemonBlock](ctx, api, NewJsonRpcBody("latc_getFreezeDBlockByNumber", dblockNumber), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -41,7 +41,7 @@ func (api *httpApi) GetFreezeDBlockByNumber(ctx context.Context, chainId string,
 }
 
 func (api *httpApi) GetFreezeTBlockByHash(ctx context.Context, chainId string, hash string) (*types.TransactionBlock, error) {
-	response, err := Post[types.TransactionBlock](ctx, api.NodeUrl, NewJsonRpcBody("latc_getFreezeTBlockByHash", hash), api.newHeaders(chainId), api.transport)
+	response, err := post[types.TransactionBlock](ctx, api, NewJsonRpcBody("latc_getFreezeTBlockByHash", hash), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -52,7 +52,7 @@ func (api *httpApi) GetFreezeTBlockByHash(ctx context.Context, chainId string, h
 }
 
 func (api *httpApi) GetFreezeTBlockByNumber(ctx context.Context, chainId string, address string, tblockNumber *big.Int) (*types.TransactionBlock, error) {
-	response, err := Post[types.TransactionBlock](ctx, api.NodeUrl, NewJsonRpcBody("latc_getFreezeTBlockByNumber", address, tblockNumber), api.newHeaders(chainId), api.transport)
+	response, err := post[types.TransactionBlock](ctx, api, NewJsonRpcBody("latc_getFreezeTBlockByNumber", address, tblockNumber), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -63,7 +63,7 @@ func (api *httpApi) GetFreezeTBlockByNumber(ctx context.Context, chainId string,
 }
 
 func (api *httpApi) GetFreezeReceipt(ctx context.Context, chainId string, address string, tblockNumber *big.Int) (*types.Receipt, error) {
-	response, err := Post[types.Receipt](ctx, api.NodeUrl, NewJsonRpcBody("latc_getFreezeReceipt", address, tblockNumber), api.newHeaders(chainId), api.transport)
+	response, err := post[types.Receipt](ctx, api, NewJsonRpcBody("latc_getFreezeReceipt", address, tblockNumber), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -74,7 +74,7 @@ func (api *httpApi) GetFreezeReceipt(ctx context.Context, chainId string, addres
 }
 
 func (api *httpApi) GetFreezeSaveSpace(ctx context.Context, chainId string) (*types.FreezeSaveSpace, error) {
-	response, err := Post[types.FreezeSaveSpace](ctx, api.NodeUrl, NewJsonRpcBody("latc_getFreezeSaveSpace"), api.newHeaders(chainId), api.transport)
+	response, err := post[types.FreezeSaveSpace](ctx, api, NewJsonRpcBody("latc_getFreezeSaveSpace"), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +85,7 @@ func (api *httpApi) GetFreezeSaveSpace(ctx context.Context, chainId string) (*ty
 }
 
 func (api *httpApi) GetFreezeInterval(ctx context.Context, chainId string) (*types.FreezeInterval, error) {
-	response, err := Post[types.FreezeInterval](ctx, api.NodeUrl, NewJsonRpcBody("latc_freezeInterval"), api.newHeaders(chainId), api.transport)
+	response, err := post[types.FreezeInterval](ctx, api, NewJsonRpcBody("latc_freezeInterval"), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -13,7 +13,7 @@ import (
 )
 
 func (api *httpApi) GetLatcInfo(ctx context.Context, chainId string) (*types.NodeProtocolConfig, error) {
-	response, err := Post[types.NodeProtocolConfig](ctx, api.NodeUrl, NewJsonRpcBody("latc_latcInfo"), api.newHeaders(chainId), api.transport)
+	response, err := post[types.NodeProtocolConfig](ctx, api, NewJsonRpcBody("latc_latcInfo"), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -24,7 +24,7 @@ func (api *httpApi) GetLatcInfo(ctx context.Context, chainId string) (*types.Nod
 }
 
 func (api *httpApi) GetNodeCertificate(ctx context.Context) (*types.NodeCertificate, error) {
-	response, err := Post[x509.Certificate](ctx, api.NodeUrl, NewJsonRpcBody("latc_getOwnerCert"), api.newHeaders(emptyChainId), api.transport)
+	response, err := post[x509.Certificate](ctx, api, NewJsonRpcBody("latc_getOwnerCert"), api.newHeaders(emptyChainId))
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +57,7 @@ func x509CertificateToNodeCertificate(certificate *x509.Certificate) (*types.Nod
 }
 
 func (api *httpApi) GetPeerNodeCertificate(ctx context.Context, serialNumber string) (*types.NodeCertificate, error) {
-	response, err := Post[x509.Certificate](ctx, api.NodeUrl, NewJsonRpcBody("latc_getCert", serialNumber), api.newHeaders(emptyChainId), api.transport)
+	response, err := post[x509.Certificate](ctx, api, NewJsonRpcBody("latc_getCert", serialNumber), api.newHeaders(emptyChainId))
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +85,7 @@ func (api *httpApi) GetPeerNodeCertificateByAddress(ctx context.Context, nodeAdd
 }
 
 func (api *httpApi) GetNodeConfiguration(ctx context.Context) (*types.NodeConfiguration, error) {
-	response, err := Post[types.NodeConfiguration](ctx, api.NodeUrl, NewJsonRpcBody("latc_getConfig"), api.newHeaders(emptyChainId), api.transport)
+	response, err := post[types.NodeConfiguration](ctx, api, NewJsonRpcBody("latc_getConfig"), api.newHeaders(emptyChainId))
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +96,7 @@ func (api *httpApi) GetNodeConfiguration(ctx context.Context) (*types.NodeConfig
 }
 
 func (api *httpApi) LoadNodeConfiguration(ctx context.Context, chainId string) (*types.NodeConfiguration, error) {
-	response, err := Post[types.NodeConfiguration](ctx, api.NodeUrl, NewJsonRpcBody("latc_loadConfig"), api.newHeaders(chainId), api.transport)
+	response, err := post[types.NodeConfiguration](ctx, api, NewJsonRpcBody("latc_loadConfig"), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -107,7 +107,7 @@ func (api *httpApi) LoadNodeConfiguration(ctx context.Context, chainId string) (
 }
 
 func (api *httpApi) GetNodeProtocol(ctx context.Context, chainId string) (*types.NodeProtocol, error) {
-	response, err := Post[types.NodeProtocol](ctx, api.NodeUrl, NewJsonRpcBody("latc_getProtocols"), api.newHeaders(chainId), api.transport)
+	response, err := post[types.NodeProtocol](ctx, api, NewJsonRpcBody("latc_getProtocols"), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -118,7 +118,7 @@ func (api *httpApi) GetNodeProtocol(ctx context.Context, chainId string) (*types
 }
 
 func (api *httpApi) GetNodeConfig(ctx context.Context, chainID string) (*types.NodeConfiguration, error) {
-	response, err := Post[types.NodeConfiguration](ctx, api.NodeUrl, NewJsonRpcBody("latc_getConfig"), api.newHeaders(chainID), api.transport)
+	response, err := post[types.NodeConfiguration](ctx, api, NewJsonRpcBody("latc_getConfig"), api.newHeaders(chainID))
 	if err != nil {
 		return nil, err
 	}
@@ -129,7 +129,7 @@ func (api *httpApi) GetNodeConfig(ctx context.Context, chainID string) (*types.N
 }
 
 func (api *httpApi) GetLatcPeers(ctx context.Context, subchainId string) (map[string]*types.SubchainPeer, error) {
-	response, err := Post[map[string]*types.SubchainPeer](ctx, api.NodeUrl, NewJsonRpcBody("latc_peers"), api.newHeaders(subchainId), api.transport)
+	response, err := post[map[string]*types.SubchainPeer](ctx, api, NewJsonRpcBody("latc_peers"), api.newHeaders(subchainId))
 	if err != nil {
 		return nil, err
 	}
@@ -140,7 +140,7 @@ func (api *httpApi) GetLatcPeers(ctx context.Context, subchainId string) (map[st
 }
 
 func (api *httpApi) GetBalanceWithPending(ctx context.Context, chainId, accountAddress string) (*types.AccountBalance, error) {
-	response, err := Post[types.AccountBalance](ctx, api.NodeUrl, NewJsonRpcBody("latc_getBalanceWithPending", accountAddress), api.newHeaders(chainId), api.transport)
+	response, err := post[types.AccountBalance](ctx, api, NewJsonRpcBody("latc_getBalanceWithPending", accountAddress), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -151,7 +151,7 @@ func (api *httpApi) GetBalanceWithPending(ctx context.Context, chainId, accountA
 }
 
 func (api *httpApi) PublishCertificates(ctx context.Context, chainId string, publicKeys []string) ([]string, error) {
-	response, err := Post[[]string](ctx, api.NodeUrl, NewJsonRpcBody("latc_publishCert", publicKeys), api.newHeaders(chainId), api.transport)
+	response, err := post[[]string](ctx, api, NewJsonRpcBody("latc_publishCert", publicKeys), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -162,7 +162,7 @@ func (api *httpApi) PublishCertificates(ctx context.Context, chainId string, pub
 }
 
 func (api *httpApi) GetCertificate(ctx context.Context, chainId string, serialNumber string) (*types.NodeCertificate, error) {
-	response, err := Post[x509.Certificate](ctx, api.NodeUrl, NewJsonRpcBody("latc_getCert", serialNumber), api.newHeaders(chainId), api.transport)
+	response, err := post[x509.Certificate](ctx, api, NewJsonRpcBody("latc_getCert", serialNumber), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
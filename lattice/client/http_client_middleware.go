@@ -0,0 +1,316 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/avast/retry-go"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrCircuitOpen 节点的熔断器处于打开状态，请求未发送到节点
+var ErrCircuitOpen = errors.New("节点连续请求失败，熔断器已打开")
+
+// HttpStatusError 节点返回了5xx的http状态码
+type HttpStatusError struct {
+	StatusCode int    // http状态码
+	Body       []byte // 响应内容
+}
+
+func (e *HttpStatusError) Error() string {
+	return fmt.Sprintf("节点返回了异常的http状态码：%d", e.StatusCode)
+}
+
+// RpcRequest 经过中间件链的JsonRpc请求
+type RpcRequest struct {
+	NodeUrl   string            // 节点的Http请求路径
+	Body      *JsonRpcBody      // 请求体，批量请求时为nil
+	Batch     []*JsonRpcBody    // 批量请求的请求体
+	Headers   map[string]string // 请求头
+	Transport http.RoundTripper // http transport
+}
+
+// Methods 请求的所有JsonRpc方法名
+func (r *RpcRequest) Methods() []string {
+	if r.Body != nil {
+		return []string{r.Body.Method}
+	}
+	methods := make([]string, len(r.Batch))
+	for i, body := range r.Batch {
+		methods[i] = body.Method
+	}
+	return methods
+}
+
+// RpcHandler 发送JsonRpc请求，返回原始的响应内容
+type RpcHandler func(ctx context.Context, request *RpcRequest) ([]byte, error)
+
+// Middleware JsonRpc请求的中间件，包装下一个 RpcHandler
+type Middleware func(next RpcHandler) RpcHandler
+
+// postHandler 中间件链最内层的处理函数，直接向节点发送请求
+func postHandler(ctx context.Context, request *RpcRequest) ([]byte, error) {
+	if request.Body == nil {
+		return rawPost(ctx, request.NodeUrl, request.Batch, request.Headers, request.Transport)
+	}
+	return rawPost(ctx, request.NodeUrl, request.Body, request.Headers, request.Transport)
+}
+
+// Chain 使用中间件包装 RpcHandler，第一个中间件位于最外层
+//
+// Parameters:
+//   - handler RpcHandler: 被包装的处理函数
+//   - middlewares ...Middleware
+//
+// Returns:
+//   - RpcHandler
+func Chain(handler RpcHandler, middlewares ...Middleware) RpcHandler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// idempotentMethods 方法名不以get开头，但可以安全重试的只读方法
+var idempotentMethods = map[string]bool{
+	"sync_status":                  true,
+	"latc_latcInfo":                true,
+	"latc_othersLatcInfo":          true,
+	"latc_peers":                   true,
+	"latc_freezeInterval":          true,
+	"latc_loadConfig":              true,
+	"node_nodeInfo":                true,
+	"node_nodeVersion":             true,
+	"node_peers":                   true,
+	"wallet_accountList":           true,
+	"wallet_confirmTaggedContract": true,
+	"wallet_preExecuteContract":    true,
+	"witness_nodeList":             true,
+}
+
+// IsIdempotentMethod 判断JsonRpc方法是否为可以安全重试的只读方法，发交易、导入私钥、加入子链等方法不可重试
+//
+// Parameters:
+//   - method string: JsonRpc方法名，如 latc_getReceipt
+//
+// Returns:
+//   - bool
+func IsIdempotentMethod(method string) bool {
+	if idempotentMethods[method] {
+		return true
+	}
+	_, name, ok := strings.Cut(method, "_")
+	return ok && strings.HasPrefix(name, "get")
+}
+
+// IsRetryableError 判断错误是否为连接失败、超时或节点返回5xx等临时性错误，节点返回的JsonRpc业务错误不可重试
+//
+// Parameters:
+//   - err error
+//
+// Returns:
+//   - bool
+func IsRetryableError(err error) bool {
+	if err == nil || errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	var urlErr *url.Error
+	var netErr net.Error
+	var statusErr *HttpStatusError
+	return errors.As(err, &urlErr) || errors.As(err, &netErr) || errors.As(err, &statusErr)
+}
+
+// RetryMiddleware 对可以安全重试的方法在临时性错误时重试，ctx取消后停止重试，批量请求中的所有方法都可以重试时才会重试
+//
+// Parameters:
+//   - retryable func(method string) bool: 判断方法是否可以重试，为nil时使用 IsIdempotentMethod
+//   - opts ...retry.Option: 重试策略，可使用 lattice.RetryStrategy.GetRetryStrategyOpts()
+//
+// Returns:
+//   - Middleware
+func RetryMiddleware(retryable func(method string) bool, opts ...retry.Option) Middleware {
+	if retryable == nil {
+		retryable = IsIdempotentMethod
+	}
+	return func(next RpcHandler) RpcHandler {
+		return func(ctx context.Context, request *RpcRequest) ([]byte, error) {
+			methods := request.Methods()
+			for _, method := range methods {
+				if !retryable(method) {
+					return next(ctx, request)
+				}
+			}
+			retryOpts := append([]retry.Option{}, opts...)
+			retryOpts = append(retryOpts,
+				retry.Context(ctx),
+				retry.RetryIf(IsRetryableError),
+				retry.LastErrorOnly(true),
+				retry.OnRetry(func(n uint, err error) {
+					log.Warn().Err(err).Msgf("JsonRpc请求失败，第%d次重试，url: %s, method: %s", n+1, request.NodeUrl, strings.Join(methods, ","))
+				}),
+			)
+			var response []byte
+			err := retry.Do(func() error {
+				var err error
+				response, err = next(ctx, request)
+				return err
+			}, retryOpts...)
+			if err != nil {
+				return nil, err
+			}
+			return response, nil
+		}
+	}
+}
+
+// TimeoutMiddleware 为每次请求设置超时时长，使用重试时应放在 RetryMiddleware 之后，以限制单次请求的耗时，
+// 批量请求使用其中最长的超时时长
+//
+// Parameters:
+//   - defaultTimeout time.Duration: 未单独配置的方法的超时时长，为0时不设置超时
+//   - methodTimeouts map[string]time.Duration: 按方法名配置的超时时长
+//
+// Returns:
+//   - Middleware
+func TimeoutMiddleware(defaultTimeout time.Duration, methodTimeouts map[string]time.Duration) Middleware {
+	return func(next RpcHandler) RpcHandler {
+		return func(ctx context.Context, request *RpcRequest) ([]byte, error) {
+			var timeout time.Duration
+			for _, method := range request.Methods() {
+				methodTimeout, ok := methodTimeouts[method]
+				if !ok {
+					methodTimeout = defaultTimeout
+				}
+				if methodTimeout <= 0 {
+					// 存在不限制超时的方法
+					return next(ctx, request)
+				}
+				timeout = max(timeout, methodTimeout)
+			}
+			if timeout <= 0 {
+				return next(ctx, request)
+			}
+			cancelCtx, cancelFunc := context.WithTimeout(ctx, timeout)
+			defer cancelFunc()
+			return next(cancelCtx, request)
+		}
+	}
+}
+
+// CircuitBreakerConfig 熔断器配置
+type CircuitBreakerConfig struct {
+	FailureThreshold uint          // 连续失败多少次后打开熔断器，默认为5
+	OpenDuration     time.Duration // 熔断器打开后，多久允许一个试探请求，默认为10秒
+}
+
+// CircuitBreakerMiddleware 按节点熔断，节点连续发生临时性错误后快速失败返回 ErrCircuitOpen，
+// 打开一段时间后放行一个试探请求，成功则关闭熔断器
+//
+// Parameters:
+//   - config *CircuitBreakerConfig: 为nil时使用默认配置
+//
+// Returns:
+//   - Middleware
+func CircuitBreakerMiddleware(config *CircuitBreakerConfig) Middleware {
+	if config == nil {
+		config = &CircuitBreakerConfig{}
+	}
+	if config.FailureThreshold == 0 {
+		config.FailureThreshold = 5
+	}
+	if config.OpenDuration <= 0 {
+		config.OpenDuration = 10 * time.Second
+	}
+	breakers := &sync.Map{} // key: nodeUrl, value: *circuitBreaker
+	return func(next RpcHandler) RpcHandler {
+		return func(ctx context.Context, request *RpcRequest) ([]byte, error) {
+			v, _ := breakers.LoadOrStore(request.NodeUrl, &circuitBreaker{config: config})
+			breaker := v.(*circuitBreaker)
+			if !breaker.allow() {
+				return nil, fmt.Errorf("%w，url: %s", ErrCircuitOpen, request.NodeUrl)
+			}
+			response, err := next(ctx, request)
+			if err != nil && ctx.Err() != nil {
+				// 调用方取消的请求不计入节点的失败次数
+				breaker.abort()
+				return response, err
+			}
+			breaker.record(request.NodeUrl, err == nil || !IsRetryableError(err))
+			return response, err
+		}
+	}
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker 单个节点的熔断器
+type circuitBreaker struct {
+	config *CircuitBreakerConfig
+
+	mu       sync.Mutex
+	state    circuitState
+	failures uint
+	openedAt time.Time
+}
+
+// allow 判断是否放行请求，打开时间超过 OpenDuration 后仅放行一个试探请求
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case circuitOpen:
+		if time.Since(b.openedAt) < b.config.OpenDuration {
+			return false
+		}
+		b.state = circuitHalfOpen
+		return true
+	case circuitHalfOpen:
+		return false
+	default:
+		return true
+	}
+}
+
+// abort 试探请求被调用方取消时，恢复为打开状态，由下一个请求重新试探
+func (b *circuitBreaker) abort() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == circuitHalfOpen {
+		b.state = circuitOpen
+	}
+}
+
+// record 记录请求结果
+func (b *circuitBreaker) record(nodeUrl string, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if success {
+		if b.state != circuitClosed {
+			log.Info().Msgf("节点已恢复，关闭熔断器，url: %s", nodeUrl)
+		}
+		b.state = circuitClosed
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == circuitHalfOpen || b.failures >= b.config.FailureThreshold {
+		if b.state != circuitOpen {
+			log.Warn().Msgf("节点连续请求失败%d次，打开熔断器，url: %s", b.failures, nodeUrl)
+		}
+		b.state = circuitOpen
+		b.openedAt = time.Now()
+	}
+}
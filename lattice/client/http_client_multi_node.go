@@ -0,0 +1,444 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultHealthCheckInterval = 5 * time.Second
+	defaultHealthCheckTimeout  = 3 * time.Second
+	defaultMaxDaemonBlockLag   = 10
+	defaultHealthCheckChainId  = "1"
+	latencySmoothingFactor     = 0.3 // 延迟的指数加权移动平均系数
+)
+
+// LoadBalancePolicy 多节点时读请求的负载均衡策略
+type LoadBalancePolicy string
+
+const (
+	RoundRobin   LoadBalancePolicy = "RoundRobin"   // 在健康节点间轮询
+	LeastLatency LoadBalancePolicy = "LeastLatency" // 选择延迟最低的健康节点
+)
+
+// MultiNodeHttpApiInitParam 初始化多节点HTTP API的参数
+type MultiNodeHttpApiInitParam struct {
+	Nodes               []*HttpApiInitParam // 节点列表，第一个节点为首选节点
+	Policy              LoadBalancePolicy   // 读请求的负载均衡策略，默认为 RoundRobin
+	HealthCheckInterval time.Duration       // 健康检查的间隔，默认为5秒
+	HealthCheckTimeout  time.Duration       // 单个节点健康检查的超时时长，默认为3秒
+	HealthCheckChainId  string              // 检查守护区块高度时使用的链ID，默认为1
+	MaxDaemonBlockLag   uint64              // 守护区块高度落后于最高节点的最大允许值，超过后视为不健康，默认为10
+}
+
+// NodeStatus 节点的健康状态
+type NodeStatus struct {
+	NodeUrl           string        // 节点的Http请求路径
+	Healthy           bool          // 是否健康
+	Latency           time.Duration // 请求延迟的移动平均值
+	DaemonBlockHeight uint64        // 最近一次检查时的守护区块高度
+	Syncing           bool          // 是否正在同步区块
+	LastError         error         // 最近一次检查或请求的错误
+	CheckedAt         time.Time     // 最近一次检查的时间
+}
+
+// MultiNodeHttpApi 连接多个节点的HTTP API，对节点定期做健康检查并自动故障转移
+//
+//   - 读请求按负载均衡策略分发到健康节点，节点不可达时转移到其他节点重试
+//   - 同一账户的写请求（发交易、查询最新区块等）固定在同一个节点上，保证父哈希一致，该节点不健康时重新绑定
+//   - 加入子链、导入私钥等节点管理请求发送到首个健康节点，不做重试
+type MultiNodeHttpApi interface {
+	HttpApi
+
+	// NodeStatuses 获取所有节点的健康状态
+	//
+	// Returns:
+	//   - []NodeStatus
+	NodeStatuses() []NodeStatus
+
+	// CheckHealth 立即对所有节点做一次健康检查
+	//
+	// Parameters:
+	//   - ctx context.Context
+	CheckHealth(ctx context.Context)
+
+	// Close 停止健康检查
+	Close()
+}
+
+// NewMultiNodeHttpApi creates a new HTTP API for multiple Lattice nodes.
+func NewMultiNodeHttpApi(args *MultiNodeHttpApiInitParam) MultiNodeHttpApi {
+	if len(args.Nodes) == 0 {
+		panic("至少需要一个节点")
+	}
+	if args.Policy == "" {
+		args.Policy = RoundRobin
+	}
+	if args.HealthCheckInterval <= 0 {
+		args.HealthCheckInterval = defaultHealthCheckInterval
+	}
+	if args.HealthCheckTimeout <= 0 {
+		args.HealthCheckTimeout = defaultHealthCheckTimeout
+	}
+	if args.HealthCheckChainId == "" {
+		args.HealthCheckChainId = defaultHealthCheckChainId
+	}
+	if args.MaxDaemonBlockLag == 0 {
+		args.MaxDaemonBlockLag = defaultMaxDaemonBlockLag
+	}
+
+	api := &multiNodeHttpApi{
+		policy:              args.Policy,
+		healthCheckInterval: args.HealthCheckInterval,
+		healthCheckTimeout:  args.HealthCheckTimeout,
+		healthCheckChainId:  args.HealthCheckChainId,
+		maxDaemonBlockLag:   args.MaxDaemonBlockLag,
+		closeCh:             make(chan struct{}),
+	}
+	for _, param := range args.Nodes {
+		api.nodes = append(api.nodes, &nodeState{
+			api:     NewHttpApi(param),
+			nodeUrl: param.HttpUrl,
+			healthy: true,
+		})
+	}
+	go api.healthCheckLoop()
+	return api
+}
+
+// nodeState 单个节点的客户端和健康状态
+type nodeState struct {
+	api     HttpApi
+	nodeUrl string
+
+	mu                sync.RWMutex
+	healthy           bool
+	latency           time.Duration
+	daemonBlockHeight uint64
+	syncing           bool
+	lastError         error
+	checkedAt         time.Time
+}
+
+func (n *nodeState) isHealthy() bool {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.healthy
+}
+
+func (n *nodeState) getLatency() time.Duration {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.latency
+}
+
+// observeLatency 以指数加权移动平均更新节点的延迟
+func (n *nodeState) observeLatency(elapsed time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.latency == 0 {
+		n.latency = elapsed
+		return
+	}
+	n.latency = time.Duration(latencySmoothingFactor*float64(elapsed) + (1-latencySmoothingFactor)*float64(n.latency))
+}
+
+// markUnhealthy 请求节点失败时立即标记为不健康，等待下一次健康检查恢复
+func (n *nodeState) markUnhealthy(err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.healthy = false
+	n.lastError = err
+}
+
+func (n *nodeState) status() NodeStatus {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return NodeStatus{
+		NodeUrl:           n.nodeUrl,
+		Healthy:           n.healthy,
+		Latency:           n.latency,
+		DaemonBlockHeight: n.daemonBlockHeight,
+		Syncing:           n.syncing,
+		LastError:         n.lastError,
+		CheckedAt:         n.checkedAt,
+	}
+}
+
+type multiNodeHttpApi struct {
+	nodes               []*nodeState
+	policy              LoadBalancePolicy
+	healthCheckInterval time.Duration
+	healthCheckTimeout  time.Duration
+	healthCheckChainId  string
+	maxDaemonBlockLag   uint64
+
+	counter   atomic.Uint64 // 轮询的计数器
+	pins      sync.Map      // 账户绑定的节点, key: chainId_address, value: *nodeState
+	closeOnce sync.Once
+	closeCh   chan struct{}
+}
+
+func (m *multiNodeHttpApi) healthCheckLoop() {
+	ticker := time.NewTicker(m.healthCheckInterval)
+	defer ticker.Stop()
+	m.CheckHealth(context.Background())
+	for {
+		select {
+		case <-m.closeCh:
+			return
+		case <-ticker.C:
+			m.CheckHealth(context.Background())
+		}
+	}
+}
+
+func (m *multiNodeHttpApi) Close() {
+	m.closeOnce.Do(func() {
+		close(m.closeCh)
+	})
+}
+
+// probeResult 单个节点一次健康检查的结果
+type probeResult struct {
+	height  uint64
+	syncing bool
+	latency time.Duration
+	err     error
+}
+
+// probe 依次检查节点的端口连通性、同步状态和最新守护区块高度
+func (m *multiNodeHttpApi) probe(ctx context.Context, node *nodeState) probeResult {
+	if err := node.api.CanDial(m.healthCheckTimeout); err != nil {
+		return probeResult{err: fmt.Errorf("连接节点失败：%w", err)}
+	}
+
+	cancelCtx, cancelFunc := context.WithTimeout(ctx, m.healthCheckTimeout)
+	defer cancelFunc()
+	syncStatus, err := node.api.GetSyncStatus(cancelCtx)
+	if err != nil {
+		return probeResult{err: fmt.Errorf("查询节点同步状态失败：%w", err)}
+	}
+
+	start := time.Now()
+	daemonBlock, err := node.api.GetLatestDaemonBlock(cancelCtx, m.healthCheckChainId)
+	if err != nil {
+		return probeResult{err: fmt.Errorf("查询节点最新守护区块失败：%w", err)}
+	}
+	result := probeResult{syncing: syncStatus.Syncing != 0, latency: time.Since(start)}
+	if daemonBlock.Height != nil {
+		result.height = daemonBlock.Height.Uint64()
+	}
+	return result
+}
+
+func (m *multiNodeHttpApi) CheckHealth(ctx context.Context) {
+	results := make([]probeResult, len(m.nodes))
+	var wg sync.WaitGroup
+	for i, node := range m.nodes {
+		wg.Add(1)
+		go func(i int, node *nodeState) {
+			defer wg.Done()
+			results[i] = m.probe(ctx, node)
+		}(i, node)
+	}
+	wg.Wait()
+
+	var maxHeight uint64
+	for _, result := range results {
+		if result.err == nil && result.height > maxHeight {
+			maxHeight = result.height
+		}
+	}
+
+	now := time.Now()
+	for i, node := range m.nodes {
+		result := results[i]
+		if result.err == nil {
+			node.observeLatency(result.latency)
+		}
+		node.mu.Lock()
+		wasHealthy := node.healthy
+		node.checkedAt = now
+		node.lastError = result.err
+		if result.err == nil {
+			node.daemonBlockHeight = result.height
+			node.syncing = result.syncing
+			if lag := maxHeight - result.height; lag > m.maxDaemonBlockLag {
+				node.lastError = fmt.Errorf("节点的守护区块高度落后%d", lag)
+			}
+		}
+		node.healthy = node.lastError == nil
+		healthy, lastError := node.healthy, node.lastError
+		node.mu.Unlock()
+
+		if wasHealthy && !healthy {
+			log.Warn().Err(lastError).Msgf("节点健康检查未通过，nodeUrl: %s", node.nodeUrl)
+		} else if !wasHealthy && healthy {
+			log.Info().Msgf("节点已恢复健康，nodeUrl: %s", node.nodeUrl)
+		}
+	}
+}
+
+func (m *multiNodeHttpApi) NodeStatuses() []NodeStatus {
+	statuses := make([]NodeStatus, len(m.nodes))
+	for i, node := range m.nodes {
+		statuses[i] = node.status()
+	}
+	return statuses
+}
+
+// candidates 按负载均衡策略返回请求的候选节点，健康节点在前，没有健康节点时返回全部节点
+func (m *multiNodeHttpApi) candidates() []*nodeState {
+	healthy := make([]*nodeState, 0, len(m.nodes))
+	for _, node := range m.nodes {
+		if node.isHealthy() {
+			healthy = append(healthy, node)
+		}
+	}
+	if len(healthy) == 0 {
+		healthy = append(healthy, m.nodes...)
+	}
+
+	ordered := make([]*nodeState, 0, len(healthy))
+	switch m.policy {
+	case LeastLatency:
+		best := 0
+		for i, node := range healthy {
+			if node.getLatency() < healthy[best].getLatency() {
+				best = i
+			}
+		}
+		ordered = append(ordered, healthy[best])
+		ordered = append(ordered, healthy[:best]...)
+		ordered = append(ordered, healthy[best+1:]...)
+	default:
+		start := int(m.counter.Add(1)-1) % len(healthy)
+		ordered = append(ordered, healthy[start:]...)
+		ordered = append(ordered, healthy[:start]...)
+	}
+	return ordered
+}
+
+// primary 首个健康的节点，没有健康节点时返回第一个节点
+func (m *multiNodeHttpApi) primary() *nodeState {
+	for _, node := range m.nodes {
+		if node.isHealthy() {
+			return node
+		}
+	}
+	return m.nodes[0]
+}
+
+// pinned 获取账户绑定的节点，未绑定或绑定的节点不健康时重新选择节点绑定
+func (m *multiNodeHttpApi) pinned(chainId, address string) *nodeState {
+	key := fmt.Sprintf("%s_%s", chainId, address)
+	if v, ok := m.pins.Load(key); ok && v.(*nodeState).isHealthy() {
+		return v.(*nodeState)
+	}
+	node := m.candidates()[0]
+	if actual, loaded := m.pins.Swap(key, node); loaded && actual.(*nodeState) != node {
+		log.Warn().Msgf("账户绑定的节点已切换，chainId: %s, accountAddress: %s, nodeUrl: %s", chainId, address, node.nodeUrl)
+	}
+	return node
+}
+
+// isNodeUnavailable 判断错误是否由节点不可达导致，而不是节点返回的业务错误
+func isNodeUnavailable(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+	return IsRetryableError(err) || errors.Is(err, ErrCircuitOpen)
+}
+
+// call 在单个节点上执行请求，节点不可达时标记为不健康
+func call[T any](ctx context.Context, node *nodeState, fn func(api HttpApi) (T, error)) (T, error) {
+	start := time.Now()
+	result, err := fn(node.api)
+	if err == nil {
+		node.observeLatency(time.Since(start))
+	} else if isNodeUnavailable(ctx, err) {
+		log.Warn().Err(err).Msgf("请求节点失败，标记节点为不健康，nodeUrl: %s", node.nodeUrl)
+		node.markUnhealthy(err)
+	}
+	return result, err
+}
+
+// read 按负载均衡策略执行读请求，节点不可达时依次转移到其他节点重试
+func read[T any](ctx context.Context, m *multiNodeHttpApi, fn func(api HttpApi) (T, error)) (T, error) {
+	var result T
+	var err error
+	for _, node := range m.candidates() {
+		result, err = call(ctx, node, fn)
+		if !isNodeUnavailable(ctx, err) {
+			return result, err
+		}
+	}
+	return result, err
+}
+
+// write 在账户绑定的节点上执行请求，不做重试，避免重复发送交易
+func write[T any](ctx context.Context, m *multiNodeHttpApi, chainId, address string, fn func(api HttpApi) (T, error)) (T, error) {
+	return call(ctx, m.pinned(chainId, address), fn)
+}
+
+// manage 在首选节点上执行节点管理请求，不做重试
+func manage[T any](ctx context.Context, m *multiNodeHttpApi, fn func(api HttpApi) (T, error)) (T, error) {
+	return call(ctx, m.primary(), fn)
+}
+
+// readErr 执行只返回error的读请求
+func readErr(ctx context.Context, m *multiNodeHttpApi, fn func(api HttpApi) error) error {
+	_, err := read(ctx, m, func(api HttpApi) (struct{}, error) { return struct{}{}, fn(api) })
+	return err
+}
+
+// manageErr 执行只返回error的节点管理请求
+func manageErr(ctx context.Context, m *multiNodeHttpApi, fn func(api HttpApi) error) error {
+	_, err := manage(ctx, m, func(api HttpApi) (struct{}, error) { return struct{}{}, fn(api) })
+	return err
+}
+
+func (m *multiNodeHttpApi) NewHeaders(chainId string) map[string]string {
+	return m.primary().api.NewHeaders(chainId)
+}
+
+func (m *multiNodeHttpApi) GetTransport() http.RoundTripper {
+	return m.primary().api.GetTransport()
+}
+
+// CanDial 任意一个节点可以连接即返回nil
+func (m *multiNodeHttpApi) CanDial(timeout time.Duration) error {
+	var err error
+	for _, node := range m.candidates() {
+		if err = node.api.CanDial(timeout); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (m *multiNodeHttpApi) Forward(w http.ResponseWriter, r *http.Request) {
+	m.candidates()[0].api.Forward(w, r)
+}
+
+// BatchCall 批量请求中的方法都可以安全重试时按读请求处理，否则发送到首选节点
+func (m *multiNodeHttpApi) BatchCall(ctx context.Context, chainId string, elems []*BatchElem) error {
+	for _, elem := range elems {
+		if !IsIdempotentMethod(elem.Method) {
+			return manageErr(ctx, m, func(api HttpApi) error {
+				return api.BatchCall(ctx, chainId, elems)
+			})
+		}
+	}
+	return readErr(ctx, m, func(api HttpApi) error {
+		return api.BatchCall(ctx, chainId, elems)
+	})
+}
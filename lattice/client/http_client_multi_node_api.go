@@ -0,0 +1,550 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/LatticeBCLab/go-lattice/wallet"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func (m *multiNodeHttpApi) GetLatestBlock(ctx context.Context, chainId, accountAddress string) (*types.LatestBlock, error) {
+	return write(ctx, m, chainId, accountAddress, func(api HttpApi) (*types.LatestBlock, error) {
+		return api.GetLatestBlock(ctx, chainId, accountAddress)
+	})
+}
+
+func (m *multiNodeHttpApi) GetLatestBlockWithPending(ctx context.Context, chainId, accountAddress string) (*types.LatestBlock, error) {
+	return write(ctx, m, chainId, accountAddress, func(api HttpApi) (*types.LatestBlock, error) {
+		return api.GetLatestBlockWithPending(ctx, chainId, accountAddress)
+	})
+}
+
+func (m *multiNodeHttpApi) SendSignedTransaction(ctx context.Context, chainId string, signedTX *block.Transaction) (*common.Hash, error) {
+	return write(ctx, m, chainId, signedTX.Owner, func(api HttpApi) (*common.Hash, error) {
+		return api.SendSignedTransaction(ctx, chainId, signedTX)
+	})
+}
+
+func (m *multiNodeHttpApi) SendSignedTransactions(ctx context.Context, chainId string, signedTXs []*block.Transaction) ([]*common.Hash, error) {
+	var owner string
+	if len(signedTXs) > 0 {
+		owner = signedTXs[0].Owner
+	}
+	return write(ctx, m, chainId, owner, func(api HttpApi) ([]*common.Hash, error) {
+		return api.SendSignedTransactions(ctx, chainId, signedTXs)
+	})
+}
+
+func (m *multiNodeHttpApi) PreCallContract(ctx context.Context, chainId string, unsignedTX *block.Transaction) (*types.Receipt, error) {
+	return read(ctx, m, func(api HttpApi) (*types.Receipt, error) {
+		return api.PreCallContract(ctx, chainId, unsignedTX)
+	})
+}
+
+func (m *multiNodeHttpApi) GetReceipt(ctx context.Context, chainId, hash string) (*types.Receipt, error) {
+	return read(ctx, m, func(api HttpApi) (*types.Receipt, error) {
+		return api.GetReceipt(ctx, chainId, hash)
+	})
+}
+
+func (m *multiNodeHttpApi) GetReceipts(ctx context.Context, chainId string, hashes []string) ([]*types.Receipt, error) {
+	return read(ctx, m, func(api HttpApi) ([]*types.Receipt, error) {
+		return api.GetReceipts(ctx, chainId, hashes)
+	})
+}
+
+func (m *multiNodeHttpApi) GetContractLifecycleProposal(ctx context.Context, chainId, contractAddress string, state types.ProposalState, startDate, endDate string) ([]types.Proposal[types.ContractLifecycleProposal], error) {
+	return read(ctx, m, func(api HttpApi) ([]types.Proposal[types.ContractLifecycleProposal], error) {
+		return api.GetContractLifecycleProposal(ctx, chainId, contractAddress, state, startDate, endDate)
+	})
+}
+
+func (m *multiNodeHttpApi) UploadFile(ctx context.Context, chainId, filePath string) (*types.UploadFileResponse, error) {
+	return manage(ctx, m, func(api HttpApi) (*types.UploadFileResponse, error) {
+		return api.UploadFile(ctx, chainId, filePath)
+	})
+}
+
+func (m *multiNodeHttpApi) DownloadFile(ctx context.Context, cid, filePath string) error {
+	return manageErr(ctx, m, func(api HttpApi) error {
+		return api.DownloadFile(ctx, cid, filePath)
+	})
+}
+
+func (m *multiNodeHttpApi) GetNodeInfo(ctx context.Context) (*types.NodeInfo, error) {
+	return manage(ctx, m, func(api HttpApi) (*types.NodeInfo, error) {
+		return api.GetNodeInfo(ctx)
+	})
+}
+
+func (m *multiNodeHttpApi) JoinSubchain(ctx context.Context, subchainId, networkId uint64, inode string) error {
+	return manageErr(ctx, m, func(api HttpApi) error {
+		return api.JoinSubchain(ctx, subchainId, networkId, inode)
+	})
+}
+
+func (m *multiNodeHttpApi) StartSubchain(ctx context.Context, subchainId string) error {
+	return manageErr(ctx, m, func(api HttpApi) error {
+		return api.StartSubchain(ctx, subchainId)
+	})
+}
+
+func (m *multiNodeHttpApi) StopSubchain(ctx context.Context, subchainId string) error {
+	return manageErr(ctx, m, func(api HttpApi) error {
+		return api.StopSubchain(ctx, subchainId)
+	})
+}
+
+func (m *multiNodeHttpApi) DeleteSubchain(ctx context.Context, subchainId string) error {
+	return manageErr(ctx, m, func(api HttpApi) error {
+		return api.DeleteSubchain(ctx, subchainId)
+	})
+}
+
+func (m *multiNodeHttpApi) GetSubchain(ctx context.Context, subchainId string) (*types.Subchain, error) {
+	return read(ctx, m, func(api HttpApi) (*types.Subchain, error) {
+		return api.GetSubchain(ctx, subchainId)
+	})
+}
+
+func (m *multiNodeHttpApi) GetCreatedSubchain(ctx context.Context) ([]uint64, error) {
+	return read(ctx, m, func(api HttpApi) ([]uint64, error) {
+		return api.GetCreatedSubchain(ctx)
+	})
+}
+
+func (m *multiNodeHttpApi) GetJoinedSubchain(ctx context.Context) ([]uint64, error) {
+	return read(ctx, m, func(api HttpApi) ([]uint64, error) {
+		return api.GetJoinedSubchain(ctx)
+	})
+}
+
+func (m *multiNodeHttpApi) GetSubchainRunningStatus(ctx context.Context, subchainID string) (*types.SubchainRunningStatus, error) {
+	return read(ctx, m, func(api HttpApi) (*types.SubchainRunningStatus, error) {
+		return api.GetSubchainRunningStatus(ctx, subchainID)
+	})
+}
+
+func (m *multiNodeHttpApi) GetSubchainBriefInfo(ctx context.Context, subchainID string) ([]*types.SubchainBriefInfo, error) {
+	return read(ctx, m, func(api HttpApi) ([]*types.SubchainBriefInfo, error) {
+		return api.GetSubchainBriefInfo(ctx, subchainID)
+	})
+}
+
+func (m *multiNodeHttpApi) GetConsensusNodesStatus(ctx context.Context, chainID string) ([]*types.ConsensusNodeStatus, error) {
+	return read(ctx, m, func(api HttpApi) ([]*types.ConsensusNodeStatus, error) {
+		return api.GetConsensusNodesStatus(ctx, chainID)
+	})
+}
+
+func (m *multiNodeHttpApi) GetGenesisNodeAddress(ctx context.Context, chainID string) (string, error) {
+	return read(ctx, m, func(api HttpApi) (string, error) {
+		return api.GetGenesisNodeAddress(ctx, chainID)
+	})
+}
+
+func (m *multiNodeHttpApi) GetLatestDaemonBlock(ctx context.Context, chainID string) (*types.DaemonBlock, error) {
+	return read(ctx, m, func(api HttpApi) (*types.DaemonBlock, error) {
+		return api.GetLatestDaemonBlock(ctx, chainID)
+	})
+}
+
+func (m *multiNodeHttpApi) GetNodePeers(ctx context.Context) ([]*types.NodePeer, error) {
+	return manage(ctx, m, func(api HttpApi) ([]*types.NodePeer, error) {
+		return api.GetNodePeers(ctx)
+	})
+}
+
+func (m *multiNodeHttpApi) GetSubchainPeers(ctx context.Context, subchainId string) (map[string]*types.SubchainPeer, error) {
+	return manage(ctx, m, func(api HttpApi) (map[string]*types.SubchainPeer, error) {
+		return api.GetSubchainPeers(ctx, subchainId)
+	})
+}
+
+func (m *multiNodeHttpApi) GetLatcPeers(ctx context.Context, chainId string) (map[string]*types.SubchainPeer, error) {
+	return manage(ctx, m, func(api HttpApi) (map[string]*types.SubchainPeer, error) {
+		return api.GetLatcPeers(ctx, chainId)
+	})
+}
+
+func (m *multiNodeHttpApi) GetNodeConfig(ctx context.Context, chainID string) (*types.NodeConfiguration, error) {
+	return manage(ctx, m, func(api HttpApi) (*types.NodeConfiguration, error) {
+		return api.GetNodeConfig(ctx, chainID)
+	})
+}
+
+func (m *multiNodeHttpApi) GetContractInformation(ctx context.Context, chainID, contractAddress string) (*types.ContractInformation, error) {
+	return read(ctx, m, func(api HttpApi) (*types.ContractInformation, error) {
+		return api.GetContractInformation(ctx, chainID, contractAddress)
+	})
+}
+
+func (m *multiNodeHttpApi) GetContractManagement(ctx context.Context, chainID, contractAddress string, daemonBlockHeight *big.Int) (*types.ContractManagement, error) {
+	return read(ctx, m, func(api HttpApi) (*types.ContractManagement, error) {
+		return api.GetContractManagement(ctx, chainID, contractAddress, daemonBlockHeight)
+	})
+}
+
+func (m *multiNodeHttpApi) GetDaemonBlockByHash(ctx context.Context, chainId, hash string) (*types.DaemonBlock, error) {
+	return read(ctx, m, func(api HttpApi) (*types.DaemonBlock, error) {
+		return api.GetDaemonBlockByHash(ctx, chainId, hash)
+	})
+}
+
+func (m *multiNodeHttpApi) GetDaemonBlockByHeight(ctx context.Context, chainId string, height uint64) (*types.DaemonBlock, error) {
+	return read(ctx, m, func(api HttpApi) (*types.DaemonBlock, error) {
+		return api.GetDaemonBlockByHeight(ctx, chainId, height)
+	})
+}
+
+func (m *multiNodeHttpApi) ExistsBusinessContractAddress(ctx context.Context, chainId, address string) (bool, error) {
+	return read(ctx, m, func(api HttpApi) (bool, error) {
+		return api.ExistsBusinessContractAddress(ctx, chainId, address)
+	})
+}
+
+func (m *multiNodeHttpApi) GetTransactionBlockByHash(ctx context.Context, chainId, hash string) (*types.TransactionBlock, error) {
+	return read(ctx, m, func(api HttpApi) (*types.TransactionBlock, error) {
+		return api.GetTransactionBlockByHash(ctx, chainId, hash)
+	})
+}
+
+func (m *multiNodeHttpApi) GetNodeProtocol(ctx context.Context, chainId string) (*types.NodeProtocol, error) {
+	return read(ctx, m, func(api HttpApi) (*types.NodeProtocol, error) {
+		return api.GetNodeProtocol(ctx, chainId)
+	})
+}
+
+func (m *multiNodeHttpApi) GetVoteById(ctx context.Context, chainId, voteId string) (*types.VoteDetails, error) {
+	return read(ctx, m, func(api HttpApi) (*types.VoteDetails, error) {
+		return api.GetVoteById(ctx, chainId, voteId)
+	})
+}
+
+func (m *multiNodeHttpApi) GetProposal(ctx context.Context, chainId, proposalId string, ty types.ProposalType, state types.ProposalState, proposalAddress, contractAddress, startDate, endDate string, result interface{}) error {
+	return readErr(ctx, m, func(api HttpApi) error {
+		return api.GetProposal(ctx, chainId, proposalId, ty, state, proposalAddress, contractAddress, startDate, endDate, result)
+	})
+}
+
+func (m *multiNodeHttpApi) GetRawProposal(ctx context.Context, chainId, proposalId string, ty types.ProposalType, state types.ProposalState, proposalAddress, contractAddress, startDate, endDate string) (json.RawMessage, error) {
+	return read(ctx, m, func(api HttpApi) (json.RawMessage, error) {
+		return api.GetRawProposal(ctx, chainId, proposalId, ty, state, proposalAddress, contractAddress, startDate, endDate)
+	})
+}
+
+func (m *multiNodeHttpApi) GetTransactionsPagination(ctx context.Context, chainId string, startDaemonBlockHeight uint64, pageSize uint16) (*types.TransactionsPagination, error) {
+	return read(ctx, m, func(api HttpApi) (*types.TransactionsPagination, error) {
+		return api.GetTransactionsPagination(ctx, chainId, startDaemonBlockHeight, pageSize)
+	})
+}
+
+func (m *multiNodeHttpApi) GetEvidences(ctx context.Context, chainId, date string, evidenceType types.EvidenceType, page, pageSize int) (*types.Evidences, error) {
+	return read(ctx, m, func(api HttpApi) (*types.Evidences, error) {
+		return api.GetEvidences(ctx, chainId, date, evidenceType, page, pageSize)
+	})
+}
+
+func (m *multiNodeHttpApi) GetErrorEvidences(ctx context.Context, chainId, date string, evidenceLevel types.EvidenceLevel, evidenceType types.EvidenceType, page, pageSize int) (*types.Evidences, error) {
+	return read(ctx, m, func(api HttpApi) (*types.Evidences, error) {
+		return api.GetErrorEvidences(ctx, chainId, date, evidenceLevel, evidenceType, page, pageSize)
+	})
+}
+
+func (m *multiNodeHttpApi) GetNodeConfirmedConfiguration(ctx context.Context, chainId string) (*types.NodeConfirmedConfiguration, error) {
+	return read(ctx, m, func(api HttpApi) (*types.NodeConfirmedConfiguration, error) {
+		return api.GetNodeConfirmedConfiguration(ctx, chainId)
+	})
+}
+
+func (m *multiNodeHttpApi) GetNodeVersion(ctx context.Context) (*types.NodeVersion, error) {
+	return manage(ctx, m, func(api HttpApi) (*types.NodeVersion, error) {
+		return api.GetNodeVersion(ctx)
+	})
+}
+
+func (m *multiNodeHttpApi) GetNodeSaintKey(ctx context.Context) (*wallet.FileKey, error) {
+	return manage(ctx, m, func(api HttpApi) (*wallet.FileKey, error) {
+		return api.GetNodeSaintKey(ctx)
+	})
+}
+
+func (m *multiNodeHttpApi) GetNodeConfiguration(ctx context.Context) (*types.NodeConfiguration, error) {
+	return manage(ctx, m, func(api HttpApi) (*types.NodeConfiguration, error) {
+		return api.GetNodeConfiguration(ctx)
+	})
+}
+
+func (m *multiNodeHttpApi) LoadNodeConfiguration(ctx context.Context, chainId string) (*types.NodeConfiguration, error) {
+	return manage(ctx, m, func(api HttpApi) (*types.NodeConfiguration, error) {
+		return api.LoadNodeConfiguration(ctx, chainId)
+	})
+}
+
+func (m *multiNodeHttpApi) GetNodeWorkingDirectory(ctx context.Context) (string, error) {
+	return manage(ctx, m, func(api HttpApi) (string, error) {
+		return api.GetNodeWorkingDirectory(ctx)
+	})
+}
+
+func (m *multiNodeHttpApi) GetSnapshot(ctx context.Context, chainId string, daemonBlockHeight *big.Int) (*types.NodeProtocolConfig, error) {
+	return read(ctx, m, func(api HttpApi) (*types.NodeProtocolConfig, error) {
+		return api.GetSnapshot(ctx, chainId, daemonBlockHeight)
+	})
+}
+
+func (m *multiNodeHttpApi) GetLatcInfo(ctx context.Context, chainId string) (*types.NodeProtocolConfig, error) {
+	return read(ctx, m, func(api HttpApi) (*types.NodeProtocolConfig, error) {
+		return api.GetLatcInfo(ctx, chainId)
+	})
+}
+
+func (m *multiNodeHttpApi) GetProposalById(ctx context.Context, chainId, proposalId string, result interface{}) error {
+	return readErr(ctx, m, func(api HttpApi) error {
+		return api.GetProposalById(ctx, chainId, proposalId, result)
+	})
+}
+
+func (m *multiNodeHttpApi) GetSubchainIdByProposalId(ctx context.Context, chainId, proposalId string) (uint32, error) {
+	return read(ctx, m, func(api HttpApi) (uint32, error) {
+		return api.GetSubchainIdByProposalId(ctx, chainId, proposalId)
+	})
+}
+
+func (m *multiNodeHttpApi) Freeze(ctx context.Context, chainId string, dblockNumber *big.Int) (uint64, error) {
+	return manage(ctx, m, func(api HttpApi) (uint64, error) {
+		return api.Freeze(ctx, chainId, dblockNumber)
+	})
+}
+
+func (m *multiNodeHttpApi) GetFreezeDBlockByHash(ctx context.Context, chainId string, hash string) (*types.DaemonBlock, error) {
+	return read(ctx, m, func(api HttpApi) (*types.DaemonBlock, error) {
+		return api.GetFreezeDBlockByHash(ctx, chainId, hash)
+	})
+}
+
+func (m *multiNodeHttpApi) GetFreezeDBlockByNumber(ctx context.Context, chainId string, dblockNumber *big.Int) (*types.DaemonBlock, error) {
+	return read(ctx, m, func(api HttpApi) (*types.DaemonBlock, error) {
+		return api.GetFreezeDBlockByNumber(ctx, chainId, dblockNumber)
+	})
+}
+
+func (m *multiNodeHttpApi) GetFreezeTBlockByHash(ctx context.Context, chainId string, hash string) (*types.TransactionBlock, error) {
+	return read(ctx, m, func(api HttpApi) (*types.TransactionBlock, error) {
+		return api.GetFreezeTBlockByHash(ctx, chainId, hash)
+	})
+}
+
+func (m *multiNodeHttpApi) GetFreezeTBlockByNumber(ctx context.Context, chainId string, address string, tblockNumber *big.Int) (*types.TransactionBlock, error) {
+	return read(ctx, m, func(api HttpApi) (*types.TransactionBlock, error) {
+		return api.GetFreezeTBlockByNumber(ctx, chainId, address, tblockNumber)
+	})
+}
+
+func (m *multiNodeHttpApi) GetFreezeReceipt(ctx context.Context, chainId string, address string, tblockNumber *big.Int) (*types.Receipt, error) {
+	return read(ctx, m, func(api HttpApi) (*types.Receipt, error) {
+		return api.GetFreezeReceipt(ctx, chainId, address, tblockNumber)
+	})
+}
+
+func (m *multiNodeHttpApi) GetFreezeSaveSpace(ctx context.Context, chainId string) (*types.FreezeSaveSpace, error) {
+	return read(ctx, m, func(api HttpApi) (*types.FreezeSaveSpace, error) {
+		return api.GetFreezeSaveSpace(ctx, chainId)
+	})
+}
+
+func (m *multiNodeHttpApi) GetFreezeInterval(ctx context.Context, chainId string) (*types.FreezeInterval, error) {
+	return read(ctx, m, func(api HttpApi) (*types.FreezeInterval, error) {
+		return api.GetFreezeInterval(ctx, chainId)
+	})
+}
+
+func (m *multiNodeHttpApi) ImportFileKey(ctx context.Context, fileKey string) (string, error) {
+	return manage(ctx, m, func(api HttpApi) (string, error) {
+		return api.ImportFileKey(ctx, fileKey)
+	})
+}
+
+func (m *multiNodeHttpApi) ImportRawKey(ctx context.Context, privateKey, password string) (bool, error) {
+	return manage(ctx, m, func(api HttpApi) (bool, error) {
+		return api.ImportRawKey(ctx, privateKey, password)
+	})
+}
+
+func (m *multiNodeHttpApi) GetAccounts(ctx context.Context, chainId string) ([]string, error) {
+	return manage(ctx, m, func(api HttpApi) ([]string, error) {
+		return api.GetAccounts(ctx, chainId)
+	})
+}
+
+func (m *multiNodeHttpApi) GetTBlockState(ctx context.Context, chainId, hash string) (types.TBlockState, error) {
+	return read(ctx, m, func(api HttpApi) (types.TBlockState, error) {
+		return api.GetTBlockState(ctx, chainId, hash)
+	})
+}
+
+func (m *multiNodeHttpApi) GetElapsed(ctx context.Context) (map[string]int64, error) {
+	return manage(ctx, m, func(api HttpApi) (map[string]int64, error) {
+		return api.GetElapsed(ctx)
+	})
+}
+
+func (m *multiNodeHttpApi) GetNodeCertificate(ctx context.Context) (*types.NodeCertificate, error) {
+	return manage(ctx, m, func(api HttpApi) (*types.NodeCertificate, error) {
+		return api.GetNodeCertificate(ctx)
+	})
+}
+
+func (m *multiNodeHttpApi) GetPeerNodeCertificate(ctx context.Context, serialNumber string) (*types.NodeCertificate, error) {
+	return read(ctx, m, func(api HttpApi) (*types.NodeCertificate, error) {
+		return api.GetPeerNodeCertificate(ctx, serialNumber)
+	})
+}
+
+func (m *multiNodeHttpApi) GetPeerNodeCertificateByAddress(ctx context.Context, nodeAddress string) (*types.NodeCertificate, error) {
+	return read(ctx, m, func(api HttpApi) (*types.NodeCertificate, error) {
+		return api.GetPeerNodeCertificateByAddress(ctx, nodeAddress)
+	})
+}
+
+func (m *multiNodeHttpApi) GetConsensus(ctx context.Context) (*types.LatcConsensus, error) {
+	return read(ctx, m, func(api HttpApi) (*types.LatcConsensus, error) {
+		return api.GetConsensus(ctx)
+	})
+}
+
+func (m *multiNodeHttpApi) GetSyncStatus(ctx context.Context) (*types.SyncStatus, error) {
+	return manage(ctx, m, func(api HttpApi) (*types.SyncStatus, error) {
+		return api.GetSyncStatus(ctx)
+	})
+}
+
+func (m *multiNodeHttpApi) GetLastBatchDBlockNumber(ctx context.Context, chainId string) (*big.Int, error) {
+	return read(ctx, m, func(api HttpApi) (*big.Int, error) {
+		return api.GetLastBatchDBlockNumber(ctx, chainId)
+	})
+}
+
+func (m *multiNodeHttpApi) ConnectNodeAsync(ctx context.Context, inode string) error {
+	return manageErr(ctx, m, func(api HttpApi) error {
+		return api.ConnectNodeAsync(ctx, inode)
+	})
+}
+
+func (m *multiNodeHttpApi) ConnectPeerAsync(ctx context.Context, nodeHash string) error {
+	return manageErr(ctx, m, func(api HttpApi) error {
+		return api.ConnectPeerAsync(ctx, nodeHash)
+	})
+}
+
+func (m *multiNodeHttpApi) DisconnectPeerAsync(ctx context.Context, nodeHash string) error {
+	return manageErr(ctx, m, func(api HttpApi) error {
+		return api.DisconnectPeerAsync(ctx, nodeHash)
+	})
+}
+
+func (m *multiNodeHttpApi) GetDBlockProof(ctx context.Context, chainId string, dblockNumber *big.Int) (*types.WitnessProof, error) {
+	return read(ctx, m, func(api HttpApi) (*types.WitnessProof, error) {
+		return api.GetDBlockProof(ctx, chainId, dblockNumber)
+	})
+}
+
+func (m *multiNodeHttpApi) GetTBlockProof(ctx context.Context, chainId string, accountAddress string, tblockNumber *big.Int) (*types.WitnessProof, error) {
+	return read(ctx, m, func(api HttpApi) (*types.WitnessProof, error) {
+		return api.GetTBlockProof(ctx, chainId, accountAddress, tblockNumber)
+	})
+}
+
+func (m *multiNodeHttpApi) GetCurrentTBlock(ctx context.Context, chainId string, accountAddress string) (*types.TransactionBlock, error) {
+	return write(ctx, m, chainId, accountAddress, func(api HttpApi) (*types.TransactionBlock, error) {
+		return api.GetCurrentTBlock(ctx, chainId, accountAddress)
+	})
+}
+
+func (m *multiNodeHttpApi) GetTBlockByHeight(ctx context.Context, chainId, accountAddress string, height uint64) (*types.TransactionBlock, error) {
+	return read(ctx, m, func(api HttpApi) (*types.TransactionBlock, error) {
+		return api.GetTBlockByHeight(ctx, chainId, accountAddress, height)
+	})
+}
+
+func (m *multiNodeHttpApi) GetBalanceWithPending(ctx context.Context, chainId, accountAddress string) (*types.AccountBalance, error) {
+	return write(ctx, m, chainId, accountAddress, func(api HttpApi) (*types.AccountBalance, error) {
+		return api.GetBalanceWithPending(ctx, chainId, accountAddress)
+	})
+}
+
+func (m *multiNodeHttpApi) GetGenesisBlock(ctx context.Context, chainId string) (*types.TransactionBlock, error) {
+	return read(ctx, m, func(api HttpApi) (*types.TransactionBlock, error) {
+		return api.GetGenesisBlock(ctx, chainId)
+	})
+}
+
+func (m *multiNodeHttpApi) GetTBlocksByHeights(ctx context.Context, chainId string, accountAddress string, heights []uint64) ([]*types.TransactionBlock, error) {
+	return read(ctx, m, func(api HttpApi) ([]*types.TransactionBlock, error) {
+		return api.GetTBlocksByHeights(ctx, chainId, accountAddress, heights)
+	})
+}
+
+func (m *multiNodeHttpApi) GetDBlocksByHeights(ctx context.Context, chainId string, heights []uint64) ([]*types.DaemonBlock, error) {
+	return read(ctx, m, func(api HttpApi) ([]*types.DaemonBlock, error) {
+		return api.GetDBlocksByHeights(ctx, chainId, heights)
+	})
+}
+
+func (m *multiNodeHttpApi) ProxyReEncryption(ctx context.Context, chainId string, ciphertext, businessAddress, initiator, whitelist string) (string, error) {
+	return manage(ctx, m, func(api HttpApi) (string, error) {
+		return api.ProxyReEncryption(ctx, chainId, ciphertext, businessAddress, initiator, whitelist)
+	})
+}
+
+func (m *multiNodeHttpApi) GetRecentDBlocks(ctx context.Context, chainId string, limit uint32) ([]*types.DaemonBlock, error) {
+	return read(ctx, m, func(api HttpApi) ([]*types.DaemonBlock, error) {
+		return api.GetRecentDBlocks(ctx, chainId, limit)
+	})
+}
+
+func (m *multiNodeHttpApi) GetTBlockCount(ctx context.Context, chainId string) (*types.TBlockCount, error) {
+	return read(ctx, m, func(api HttpApi) (*types.TBlockCount, error) {
+		return api.GetTBlockCount(ctx, chainId)
+	})
+}
+
+func (m *multiNodeHttpApi) ImportCertificate(ctx context.Context, chainId string, pemCertificate string) error {
+	return manageErr(ctx, m, func(api HttpApi) error {
+		return api.ImportCertificate(ctx, chainId, pemCertificate)
+	})
+}
+
+func (m *multiNodeHttpApi) PublishCertificates(ctx context.Context, chainId string, publicKeys []string) ([]string, error) {
+	return manage(ctx, m, func(api HttpApi) ([]string, error) {
+		return api.PublishCertificates(ctx, chainId, publicKeys)
+	})
+}
+
+func (m *multiNodeHttpApi) GetCertificate(ctx context.Context, chainId string, serialNumber string) (*types.NodeCertificate, error) {
+	return read(ctx, m, func(api HttpApi) (*types.NodeCertificate, error) {
+		return api.GetCertificate(ctx, chainId, serialNumber)
+	})
+}
+
+func (m *multiNodeHttpApi) GetCurrentIDB(ctx context.Context, chainId, owner string) (*types.CurrentIDB, error) {
+	return write(ctx, m, chainId, owner, func(api HttpApi) (*types.CurrentIDB, error) {
+		return api.GetCurrentIDB(ctx, chainId, owner)
+	})
+}
+
+func (m *multiNodeHttpApi) GetDIDBByHash(ctx context.Context, chainId, hash, docHash string) (*types.DIDB, error) {
+	return read(ctx, m, func(api HttpApi) (*types.DIDB, error) {
+		return api.GetDIDBByHash(ctx, chainId, hash, docHash)
+	})
+}
+
+func (m *multiNodeHttpApi) GetCreateContractSolidity(ctx context.Context, params *types.CreateDataContractParams) (string, error) {
+	return manage(ctx, m, func(api HttpApi) (string, error) {
+		return api.GetCreateContractSolidity(ctx, params)
+	})
+}
@@ -10,7 +10,7 @@ import (
 )
 
 func (api *httpApi) GetNodeInfo(ctx context.Context) (*types.NodeInfo, error) {
-	response, err := Post[types.NodeInfo](ctx, api.NodeUrl, NewJsonRpcBody("node_nodeInfo"), api.newHeaders(emptyChainId), api.transport)
+	response, err := post[types.NodeInfo](ctx, api, NewJsonRpcBody("node_nodeInfo"), api.newHeaders(emptyChainId))
 	if err != nil {
 		return nil, err
 	}
@@ -21,7 +21,7 @@ func (api *httpApi) GetNodeInfo(ctx context.Context) (*types.NodeInfo, error) {
 }
 
 func (api *httpApi) GetConsensusNodesStatus(ctx context.Context, chainID string) ([]*types.ConsensusNodeStatus, error) {
-	response, err := Post[[]*types.ConsensusNodeStatus](ctx, api.NodeUrl, NewJsonRpcBody("witness_nodeList"), api.newHeaders(chainID), api.transport)
+	response, err := post[[]*types.ConsensusNodeStatus](ctx, api, NewJsonRpcBody("witness_nodeList"), api.newHeaders(chainID))
 	if err != nil {
 		return nil, err
 	}
@@ -32,7 +32,7 @@ func (api *httpApi) GetConsensusNodesStatus(ctx context.Context, chainID string)
 }
 
 func (api *httpApi) GetGenesisNodeAddress(ctx context.Context, chainID string) (string, error) {
-	response, err := Post[string](ctx, api.NodeUrl, NewJsonRpcBody("wallet_getGenesisNode"), api.newHeaders(chainID), api.transport)
+	response, err := post[string](ctx, api, NewJsonRpcBody("wallet_getGenesisNode"), api.newHeaders(chainID))
 	if err != nil {
 		return "", err
 	}
@@ -43,7 +43,7 @@ func (api *httpApi) GetGenesisNodeAddress(ctx context.Context, chainID string) (
 }
 
 func (api *httpApi) GetNodePeers(ctx context.Context) ([]*types.NodePeer, error) {
-	response, err := Post[[]*types.NodePeer](ctx, api.NodeUrl, NewJsonRpcBody("node_peers"), api.newHeaders(emptyChainId), api.transport)
+	response, err := post[[]*types.NodePeer](ctx, api, NewJsonRpcBody("node_peers"), api.newHeaders(emptyChainId))
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +54,7 @@ func (api *httpApi) GetNodePeers(ctx context.Context) ([]*types.NodePeer, error)
 }
 
 func (api *httpApi) GetNodeConfirmedConfiguration(ctx context.Context, chainId string) (*types.NodeConfirmedConfiguration, error) {
-	response, err := Post[types.NodeConfirmedConfiguration](ctx, api.NodeUrl, NewJsonRpcBody("wallet_getConfirmConfig"), api.newHeaders(chainId), api.transport)
+	response, err := post[types.NodeConfirmedConfiguration](ctx, api, NewJsonRpcBody("wallet_getConfirmConfig"), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -65,7 +65,7 @@ func (api *httpApi) GetNodeConfirmedConfiguration(ctx context.Context, chainId s
 }
 
 func (api *httpApi) GetNodeVersion(ctx context.Context) (*types.NodeVersion, error) {
-	response, err := Post[types.NodeVersion](ctx, api.NodeUrl, NewJsonRpcBody("node_nodeVersion"), api.newHeaders(emptyChainId), api.transport)
+	response, err := post[types.NodeVersion](ctx, api, NewJsonRpcBody("node_nodeVersion"), api.newHeaders(emptyChainId))
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +76,7 @@ func (api *httpApi) GetNodeVersion(ctx context.Context) (*types.NodeVersion, err
 }
 
 func (api *httpApi) GetNodeSaintKey(ctx context.Context) (*wallet.FileKey, error) {
-	response, err := Post[wallet.FileKey](ctx, api.NodeUrl, NewJsonRpcBody("node_getSaintKey"), api.newHeaders(emptyChainId), api.transport)
+	response, err := post[wallet.FileKey](ctx, api, NewJsonRpcBody("node_getSaintKey"), api.newHeaders(emptyChainId))
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +87,7 @@ func (api *httpApi) GetNodeSaintKey(ctx context.Context) (*wallet.FileKey, error
 }
 
 func (api *httpApi) GetNodeWorkingDirectory(ctx context.Context) (string, error) {
-	response, err := Post[string](ctx, api.NodeUrl, NewJsonRpcBody("node_getLocationPath"), api.newHeaders(emptyChainId), api.transport)
+	response, err := post[string](ctx, api, NewJsonRpcBody("node_getLocationPath"), api.newHeaders(emptyChainId))
 	if err != nil {
 		return "", err
 	}
@@ -98,7 +98,7 @@ func (api *httpApi) GetNodeWorkingDirectory(ctx context.Context) (string, error)
 }
 
 func (api *httpApi) GetSnapshot(ctx context.Context, chainId string, daemonBlockHeight *big.Int) (*types.NodeProtocolConfig, error) {
-	response, err := Post[types.NodeProtocolConfig](ctx, api.NodeUrl, NewJsonRpcBody("clique_getSnapshot", daemonBlockHeight), api.newHeaders(chainId), api.transport)
+	response, err := post[types.NodeProtocolConfig](ctx, api, NewJsonRpcBody("clique_getSnapshot", daemonBlockHeight), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -109,7 +109,7 @@ func (api *httpApi) GetSnapshot(ctx context.Context, chainId string, daemonBlock
 }
 
 func (api *httpApi) ConnectNodeAsync(ctx context.Context, inode string) error {
-	response, err := Post[json.RawMessage](ctx, api.NodeUrl, NewJsonRpcBody("node_connectNode", inode), api.newHeaders(emptyChainId), api.transport)
+	response, err := post[json.RawMessage](ctx, api, NewJsonRpcBody("node_connectNode", inode), api.newHeaders(emptyChainId))
 	if err != nil {
 		return err
 	}
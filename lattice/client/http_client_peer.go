@@ -6,7 +6,7 @@ import (
 )
 
 func (api *httpApi) ConnectPeerAsync(ctx context.Context, id string) error {
-	response, err := Post[json.RawMessage](ctx, api.NodeUrl, NewJsonRpcBody("latc_connectPeer", id), api.newHeaders(emptyChainId), api.transport)
+	response, err := post[json.RawMessage](ctx, api, NewJsonRpcBody("latc_connectPeer", id), api.newHeaders(emptyChainId))
 	if err != nil {
 		return err
 	}
@@ -17,7 +17,7 @@ func (api *httpApi) ConnectPeerAsync(ctx context.Context, id string) error {
 }
 
 func (api *httpApi) DisconnectPeerAsync(ctx context.Context, id string) error {
-	response, err := Post[json.RawMessage](ctx, api.NodeUrl, NewJsonRpcBody("latc_disconnectPeer", id), api.newHeaders(emptyChainId), api.transport)
+	response, err := post[json.RawMessage](ctx, api, NewJsonRpcBody("latc_disconnectPeer", id), api.newHeaders(emptyChainId))
 	if err != nil {
 		return err
 	}
@@ -21,7 +21,7 @@ func (api *httpApi) GetContractLifecycleProposal(ctx context.Context, chainId, c
 		args["dateEnd"] = endDate
 	}
 
-	response, err := Post[[]types.Proposal[types.ContractLifecycleProposal]](ctx, api.NodeUrl, NewJsonRpcBody("wallet_getProposal", args), api.newHeaders(chainId), api.transport)
+	response, err := post[[]types.Proposal[types.ContractLifecycleProposal]](ctx, api, NewJsonRpcBody("wallet_getProposal", args), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -32,7 +32,7 @@ func (api *httpApi) GetContractLifecycleProposal(ctx context.Context, chainId, c
 }
 
 func (api *httpApi) GetVoteById(ctx context.Context, chainId, voteId string) (*types.VoteDetails, error) {
-	response, err := Post[types.VoteDetails](ctx, api.NodeUrl, NewJsonRpcBody("wallet_getVoteById", voteId), api.newHeaders(chainId), api.transport)
+	response, err := post[types.VoteDetails](ctx, api, NewJsonRpcBody("wallet_getVoteById", voteId), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +86,7 @@ func (api *httpApi) GetRawProposal(ctx context.Context, chainId, proposalId stri
 		args["dateEnd"] = endDate
 	}
 
-	response, err := Post[json.RawMessage](ctx, api.NodeUrl, NewJsonRpcBody("wallet_getProposal", args), api.newHeaders(chainId), api.transport)
+	response, err := post[json.RawMessage](ctx, api, NewJsonRpcBody("wallet_getProposal", args), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -97,7 +97,7 @@ func (api *httpApi) GetRawProposal(ctx context.Context, chainId, proposalId stri
 }
 
 func (api *httpApi) GetProposalById(ctx context.Context, chainId, proposalId string, result interface{}) error {
-	response, err := Post[json.RawMessage](ctx, api.NodeUrl, NewJsonRpcBody("wallet_getProposalById", proposalId), api.newHeaders(chainId), api.transport)
+	response, err := post[json.RawMessage](ctx, api, NewJsonRpcBody("wallet_getProposalById", proposalId), api.newHeaders(chainId))
 	if err != nil {
 		return err
 	}
@@ -8,7 +8,7 @@ import (
 )
 
 func (api *httpApi) GetSubchain(ctx context.Context, subchainId string) (*types.Subchain, error) {
-	response, err := Post[types.Subchain](ctx, api.NodeUrl, NewJsonRpcBody("latc_latcInfo"), api.newHeaders(subchainId), api.transport)
+	response, err := post[types.Subchain](ctx, api, NewJsonRpcBody("latc_latcInfo"), api.newHeaders(subchainId))
 	if err != nil {
 		return nil, err
 	}
@@ -19,7 +19,7 @@ func (api *httpApi) GetSubchain(ctx context.Context, subchainId string) (*types.
 }
 
 func (api *httpApi) GetCreatedSubchain(ctx context.Context) ([]uint64, error) {
-	response, err := Post[[]uint64](ctx, api.NodeUrl, NewJsonRpcBody("cbyc_getCreatedAllChains"), api.newHeaders(emptyChainId), api.transport)
+	response, err := post[[]uint64](ctx, api, NewJsonRpcBody("cbyc_getCreatedAllChains"), api.newHeaders(emptyChainId))
 	if err != nil {
 		return nil, err
 	}
@@ -30,7 +30,7 @@ func (api *httpApi) GetCreatedSubchain(ctx context.Context) ([]uint64, error) {
 }
 
 func (api *httpApi) GetJoinedSubchain(ctx context.Context) ([]uint64, error) {
-	response, err := Post[[]uint64](ctx, api.NodeUrl, NewJsonRpcBody("node_getAllChainId"), api.newHeaders(emptyChainId), api.transport)
+	response, err := post[[]uint64](ctx, api, NewJsonRpcBody("node_getAllChainId"), api.newHeaders(emptyChainId))
 	if err != nil {
 		return nil, err
 	}
@@ -41,7 +41,7 @@ func (api *httpApi) GetJoinedSubchain(ctx context.Context) ([]uint64, error) {
 }
 
 func (api *httpApi) GetSubchainRunningStatus(ctx context.Context, subchainID string) (*types.SubchainRunningStatus, error) {
-	response, err := Post[string](ctx, api.NodeUrl, NewJsonRpcBody("cbyc_getChainStatus"), api.newHeaders(subchainID), api.transport)
+	response, err := post[string](ctx, api, NewJsonRpcBody("cbyc_getChainStatus"), api.newHeaders(subchainID))
 	if err != nil {
 		return nil, err
 	}
@@ -56,7 +56,7 @@ func (api *httpApi) GetSubchainRunningStatus(ctx context.Context, subchainID str
 }
 
 func (api *httpApi) JoinSubchain(ctx context.Context, subchainId, networkId uint64, inode string) error {
-	response, err := Post[any](ctx, api.NodeUrl, NewJsonRpcBody("cbyc_selfJoinChain", subchainId, networkId, inode), api.newHeaders(emptyChainId), api.transport)
+	response, err := post[any](ctx, api, NewJsonRpcBody("cbyc_selfJoinChain", subchainId, networkId, inode), api.newHeaders(emptyChainId))
 	if err != nil {
 		return err
 	}
@@ -67,7 +67,7 @@ func (api *httpApi) JoinSubchain(ctx context.Context, subchainId, networkId uint
 }
 
 func (api *httpApi) StartSubchain(ctx context.Context, subchainId string) error {
-	response, err := Post[any](ctx, api.NodeUrl, NewJsonRpcBody("cbyc_startSelfChain"), api.newHeaders(subchainId), api.transport)
+	response, err := post[any](ctx, api, NewJsonRpcBody("cbyc_startSelfChain"), api.newHeaders(subchainId))
 	if err != nil {
 		return err
 	}
@@ -78,7 +78,7 @@ func (api *httpApi) StartSubchain(ctx context.Context, subchainId string) error
 }
 
 func (api *httpApi) StopSubchain(ctx context.Context, subchainId string) error {
-	response, err := Post[any](ctx, api.NodeUrl, NewJsonRpcBody("cbyc_stopSelfChain"), api.newHeaders(subchainId), api.transport)
+	response, err := post[any](ctx, api, NewJsonRpcBody("cbyc_stopSelfChain"), api.newHeaders(subchainId))
 	if err != nil {
 		return err
 	}
@@ -89,7 +89,7 @@ func (api *httpApi) StopSubchain(ctx context.Context, subchainId string) error {
 }
 
 func (api *httpApi) DeleteSubchain(ctx context.Context, subchainId string) error {
-	response, err := Post[any](ctx, api.NodeUrl, NewJsonRpcBody("cbyc_delSelfChain"), api.newHeaders(subchainId), api.transport)
+	response, err := post[any](ctx, api, NewJsonRpcBody("cbyc_delSelfChain"), api.newHeaders(subchainId))
 	if err != nil {
 		return err
 	}
@@ -100,7 +100,7 @@ func (api *httpApi) DeleteSubchain(ctx context.Context, subchainId string) error
 }
 
 func (api *httpApi) GetSubchainPeers(ctx context.Context, subchainId string) (map[string]*types.SubchainPeer, error) {
-	response, err := Post[map[string]*types.SubchainPeer](ctx, api.NodeUrl, NewJsonRpcBody("latc_peers"), api.newHeaders(subchainId), api.transport)
+	response, err := post[map[string]*types.SubchainPeer](ctx, api, NewJsonRpcBody("latc_peers"), api.newHeaders(subchainId))
 	if err != nil {
 		return nil, err
 	}
@@ -119,7 +119,7 @@ func (api *httpApi) GetSubchainBriefInfo(ctx context.Context, subchainID string)
 			return nil, err
 		}
 	}
-	response, err := Post[[]*types.SubchainBriefInfo](ctx, api.NodeUrl, NewJsonRpcBody("latc_othersLatcInfo", subchainIdAsInt), api.newHeaders(emptyChainId), api.transport)
+	response, err := post[[]*types.SubchainBriefInfo](ctx, api, NewJsonRpcBody("latc_othersLatcInfo", subchainIdAsInt), api.newHeaders(emptyChainId))
 	if err != nil {
 		return nil, err
 	}
@@ -130,7 +130,7 @@ func (api *httpApi) GetSubchainBriefInfo(ctx context.Context, subchainID string)
 }
 
 func (api *httpApi) GetSubchainIdByProposalId(ctx context.Context, chainId, proposalId string) (uint32, error) {
-	response, err := Post[types.Proposal[types.SubchainProposal]](ctx, api.NodeUrl, NewJsonRpcBody("wallet_getProposalById", proposalId), api.newHeaders(chainId), api.transport)
+	response, err := post[types.Proposal[types.SubchainProposal]](ctx, api, NewJsonRpcBody("wallet_getProposalById", proposalId), api.newHeaders(chainId))
 	if err != nil {
 		return 0, err
 	}
@@ -3,7 +3,7 @@ package client
 import "context"
 
 func (api *httpApi) ImportFileKey(ctx context.Context, fileKey string) (string, error) {
-	response, err := Post[string](ctx, api.NodeUrl, NewJsonRpcBody("wallet_importFileKey", fileKey), api.newHeaders(emptyChainId), api.transport)
+	response, err := post[string](ctx, api, NewJsonRpcBody("wallet_importFileKey", fileKey), api.newHeaders(emptyChainId))
 	if err != nil {
 		return "", err
 	}
@@ -14,7 +14,7 @@ func (api *httpApi) ImportFileKey(ctx context.Context, fileKey string) (string,
 }
 
 func (api *httpApi) ImportRawKey(ctx context.Context, privateKey, password string) (bool, error) {
-	response, err := Post[bool](ctx, api.NodeUrl, NewJsonRpcBody("wallet_importRawKey", privateKey, password), api.newHeaders(emptyChainId), api.transport)
+	response, err := post[bool](ctx, api, NewJsonRpcBody("wallet_importRawKey", privateKey, password), api.newHeaders(emptyChainId))
 	if err != nil {
 		return false, err
 	}
@@ -25,7 +25,7 @@ func (api *httpApi) ImportRawKey(ctx context.Context, privateKey, password strin
 }
 
 func (api *httpApi) GetAccounts(ctx context.Context, chainId string) ([]string, error) {
-	response, err := Post[[]string](ctx, api.NodeUrl, NewJsonRpcBody("wallet_accountList"), api.newHeaders(chainId), api.transport)
+	response, err := post[[]string](ctx, api, NewJsonRpcBody("wallet_accountList"), api.newHeaders(chainId))
 	if err != nil {
 		return nil, err
 	}
@@ -46,12 +46,11 @@ func (api *httpApi) GetAccounts(ctx context.Context, chainId string) ([]string,
 //   - string: 重加密后的密文，可能的error(16进制)
 //   - error
 func (api *httpApi) ProxyReEncryption(ctx context.Context, chainId string, ciphertext, businessAddress, initiator, whitelist string) (string, error) {
-	response, err := Post[string](
+	response, err := post[string](
 		ctx,
-		api.NodeUrl,
+		api,
 		NewJsonRpcBody("wallet_proxyRecrypt", map[string]string{"ciphertext": ciphertext, "businessId": businessAddress, "initiator": initiator, "whiteList": whitelist}),
 		api.newHeaders(chainId),
-		api.transport,
 	)
 	if err != nil {
 		return "", err
@@ -63,12 +62,11 @@ func (api *httpApi) ProxyReEncryption(ctx context.Context, chainId string, ciphe
 }
 
 func (api *httpApi) ImportCertificate(ctx context.Context, chainId string, pemCertificate string) error {
-	response, err := Post[any](
+	response, err := post[any](
 		ctx,
-		api.NodeUrl,
+		api,
 		NewJsonRpcBody("wallet_importCert", pemCertificate),
 		api.newHeaders(chainId),
-		api.transport,
 	)
 	if err != nil {
 		return err
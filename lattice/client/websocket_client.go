@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/LatticeBCLab/go-lattice/common/types"
@@ -13,6 +14,15 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+const (
+	defaultPingInterval            = 15 * time.Second
+	defaultPongTimeout             = 10 * time.Second
+	defaultReconnectMinDelay       = 500 * time.Millisecond
+	defaultReconnectMaxDelay       = 30 * time.Second
+	defaultWebSocketRequestTimeout = 10 * time.Second
+	defaultSubscriptionBufferSize  = 128
+)
+
 type SubscriptionResult struct {
 	ID     string          `json:"subapi,omitempty"` // id
 	Result json.RawMessage `json:"result,omitempty"` // 数据
@@ -27,11 +37,15 @@ type SubscribeResponse struct {
 
 // 订阅结果，支持读取订阅数据和主动结束订阅
 type Subscribe[T any] interface {
-	// ID 返回订阅ID
+	// ID 返回订阅ID，断线重连后会变为重新订阅时节点返回的ID
 	ID() string
-	// Read 读取一条订阅数据
+	// Read 读取一条订阅数据，订阅结束后返回结束的原因
 	Read() (T, error)
-	// Close 主动结束订阅
+	// Chan 返回接收订阅数据的channel，订阅结束后channel被关闭，结束原因可通过 Err 获取，无法解析的数据会被丢弃
+	Chan() <-chan T
+	// Err 返回订阅结束的原因，订阅未结束时返回nil
+	Err() error
+	// Close 主动结束订阅，并向节点发送取消订阅的请求
 	Close() error
 }
 
@@ -41,6 +55,41 @@ type WebSocketApi interface {
 
 	// Workflow 订阅工作流
 	Workflow(ctx context.Context, cond *types.WorkflowSubscribeCondition) (Subscribe[types.Workflow], error)
+
+	// DaemonBlocks 订阅新产生的守护区块
+	//
+	// Parameters:
+	//   - ctx context.Context
+	//
+	// Returns:
+	//   - Subscribe[types.DaemonBlock]
+	//   - error
+	DaemonBlocks(ctx context.Context) (Subscribe[types.DaemonBlock], error)
+
+	// TransactionBlocks 订阅账户新产生的交易区块
+	//
+	// Parameters:
+	//   - ctx context.Context
+	//   - accountAddress string: 账户地址，如 zltc_Z1pnS94bP4hQSYLs4aP4UwBP9pH8bEvhi
+	//
+	// Returns:
+	//   - Subscribe[types.TransactionBlock]
+	//   - error
+	TransactionBlocks(ctx context.Context, accountAddress string) (Subscribe[types.TransactionBlock], error)
+
+	// Logs 订阅合约日志
+	//
+	// Parameters:
+	//   - ctx context.Context
+	//   - cond *types.LogsSubscribeCondition: 按合约地址和事件主题过滤，为nil时订阅所有合约的日志
+	//
+	// Returns:
+	//   - Subscribe[types.Event]
+	//   - error
+	Logs(ctx context.Context, cond *types.LogsSubscribeCondition) (Subscribe[types.Event], error)
+
+	// Close 关闭与节点的连接，结束所有订阅
+	Close() error
 }
 
 type WebSocketApiInitParam struct {
@@ -49,32 +98,52 @@ type WebSocketApiInitParam struct {
 	HandshakeTimeout time.Duration
 	ReadBufferSize   int
 	WriteBufferSize  int
+
+	PingInterval           time.Duration // 发送心跳的间隔，默认为15秒
+	PongTimeout            time.Duration // 等待心跳响应的超时时长，超时后断开并重连，默认为10秒
+	ReconnectMinDelay      time.Duration // 断线重连的初始等待时长，之后按指数退避，默认为500毫秒
+	ReconnectMaxDelay      time.Duration // 断线重连的最大等待时长，默认为30秒
+	RequestTimeout         time.Duration // 重新订阅和取消订阅请求的超时时长，默认为10秒
+	SubscriptionBufferSize int           // 每个订阅缓存的未读取数据条数，缓存满时以 ErrSlowConsumer 结束该订阅，默认为128
 }
 
 // NewWebSocketApi creates a new WebSocket API for the Lattice node.
+//
+// 所有订阅共用一个到节点的连接，首次订阅时建立连接，连接断开后自动重连并重新订阅
 func NewWebSocketApi(args *WebSocketApiInitParam) WebSocketApi {
 	if args.HandshakeTimeout == 0 {
 		args.HandshakeTimeout = 30 * time.Second
 	}
+	if args.PingInterval <= 0 {
+		args.PingInterval = defaultPingInterval
+	}
+	if args.PongTimeout <= 0 {
+		args.PongTimeout = defaultPongTimeout
+	}
+	if args.ReconnectMinDelay <= 0 {
+		args.ReconnectMinDelay = defaultReconnectMinDelay
+	}
+	if args.ReconnectMaxDelay < args.ReconnectMinDelay {
+		args.ReconnectMaxDelay = max(defaultReconnectMaxDelay, args.ReconnectMinDelay)
+	}
+	if args.RequestTimeout <= 0 {
+		args.RequestTimeout = defaultWebSocketRequestTimeout
+	}
+	if args.SubscriptionBufferSize <= 0 {
+		args.SubscriptionBufferSize = defaultSubscriptionBufferSize
+	}
 	return &webSocketApi{
-		webSocketUrl: args.WebSocketUrl,
-		dialer: &websocket.Dialer{
+		conn: newWsConn(args, &websocket.Dialer{
 			Proxy:            http.ProxyFromEnvironment,
 			HandshakeTimeout: args.HandshakeTimeout,
 			ReadBufferSize:   args.ReadBufferSize,
 			WriteBufferSize:  args.WriteBufferSize,
-		},
+		}),
 	}
 }
 
 type webSocketApi struct {
-	webSocketUrl string
-	dialer       *websocket.Dialer
-}
-
-// 订阅任意类型的工作流
-type workflowResult struct {
-	subscribeRawResult
+	conn *wsConn // 与节点共用的连接
 }
 
 func unmarshalWorkflow[T any](b []byte) (*T, error) {
@@ -86,12 +155,8 @@ func unmarshalWorkflow[T any](b []byte) (*T, error) {
 	return &result, nil
 }
 
-func (r workflowResult) Read() (types.Workflow, error) {
-	raw, err := r.subscribeRawResult.Read()
-	if err != nil {
-		return nil, err
-	}
-	b := raw.Params.Result
+// decodeWorkflow 按flowType解析任意类型的工作流
+func decodeWorkflow(b []byte) (types.Workflow, error) {
 	t, err := jsonparser.GetInt(b, "flowType")
 	if err != nil {
 		return nil, err
@@ -126,95 +191,101 @@ func (w *webSocketApi) Workflow(ctx context.Context, cond *types.WorkflowSubscri
 	if cond == nil {
 		cond = &types.WorkflowSubscribeCondition{}
 	}
-	r, err := w.subscribe(ctx, "node_subscribe", "workflow", cond)
+	sub, err := w.conn.subscribe(ctx, "node_subscribe", "workflow", cond)
 	if err != nil {
 		return nil, err
 	}
-	return &workflowResult{r}, nil
+	return newSubscribeResult(sub, decodeWorkflow), nil
 }
 
-type subscribeResult[T any] struct {
-	subscribeRawResult
+// DaemonBlocks implements WebSocketApi.
+func (w *webSocketApi) DaemonBlocks(ctx context.Context) (Subscribe[types.DaemonBlock], error) {
+	return subscribe[types.DaemonBlock](w, ctx, "latc_subscribe", "newDBlock")
 }
 
-func (r subscribeResult[T]) Read() (result T, err error) {
-	b, err := r.subscribeRawResult.Read()
-	if err != nil {
-		return
-	}
-	err = json.Unmarshal(b.Params.Result, &result)
-	if err != nil {
-		return
+// TransactionBlocks implements WebSocketApi.
+func (w *webSocketApi) TransactionBlocks(ctx context.Context, accountAddress string) (Subscribe[types.TransactionBlock], error) {
+	return subscribe[types.TransactionBlock](w, ctx, "latc_subscribe", "newTBlock", accountAddress)
+}
+
+// Logs implements WebSocketApi.
+func (w *webSocketApi) Logs(ctx context.Context, cond *types.LogsSubscribeCondition) (Subscribe[types.Event], error) {
+	if cond == nil {
+		cond = &types.LogsSubscribeCondition{}
 	}
-	return
+	return subscribe[types.Event](w, ctx, "latc_subscribe", "logs", cond)
 }
 
-func subscribe[T any](w *webSocketApi, ctx context.Context, method string, params ...any) (Subscribe[T], error) {
-	raw, err := w.subscribe(ctx, method, params...)
+// subscribeResult 将订阅的原始数据解析为T
+type subscribeResult[T any] struct {
+	*subscription
+	decode func(b []byte) (T, error)
+
+	chOnce sync.Once
+	ch     chan T
+}
+
+func newSubscribeResult[T any](sub *subscription, decode func(b []byte) (T, error)) *subscribeResult[T] {
+	return &subscribeResult[T]{subscription: sub, decode: decode}
+}
+
+func (r *subscribeResult[T]) Read() (result T, err error) {
+	b, err := r.subscription.read()
 	if err != nil {
-		return nil, err
+		return
 	}
-	return &subscribeResult[T]{raw}, nil
+	return r.decode(b)
 }
 
-// Subscribe implements WebSocketApi.
-func (w *webSocketApi) Subscribe(ctx context.Context, method string, params ...any) (Subscribe[map[string]any], error) {
-	return subscribe[map[string]any](w, ctx, method, params...)
+func (r *subscribeResult[T]) Chan() <-chan T {
+	r.chOnce.Do(func() {
+		r.ch = make(chan T)
+		go r.pump()
+	})
+	return r.ch
 }
 
-type subscribeRawResult struct {
-	conn *websocket.Conn
-	id   string
+// pump 将订阅数据解析后写入channel，直到订阅结束
+func (r *subscribeResult[T]) pump() {
+	defer close(r.ch)
+	for {
+		b, err := r.subscription.read()
+		if err != nil {
+			return
+		}
+		result, err := r.decode(b)
+		if err != nil {
+			log.Warn().Err(err).Msgf("解析订阅数据失败，丢弃该数据，subscription: %s, data: %s", r.ID(), b)
+			continue
+		}
+		select {
+		case r.ch <- result:
+		case <-r.subscription.done:
+			return
+		}
+	}
 }
 
-func (r subscribeRawResult) ID() string {
-	return r.id
+// unmarshalSubscription 使用json解析订阅数据
+func unmarshalSubscription[T any](b []byte) (result T, err error) {
+	err = json.Unmarshal(b, &result)
+	return
 }
 
-func (r subscribeRawResult) Read() (*SubscribeResponse, error) {
-	var resp SubscribeResponse
-	err := r.conn.ReadJSON(&resp)
+func subscribe[T any](w *webSocketApi, ctx context.Context, method string, params ...any) (Subscribe[T], error) {
+	sub, err := w.conn.subscribe(ctx, method, params...)
 	if err != nil {
 		return nil, err
 	}
-	// 检验订阅ID是否匹配
-	if resp.Params.ID != r.id {
-		return nil, fmt.Errorf("订阅ID不匹配，got %s, expect %s", resp.Params.ID, r.id)
-	}
-	return &resp, nil
+	return newSubscribeResult(sub, unmarshalSubscription[T]), nil
 }
 
-func (r subscribeRawResult) Close() error {
-	return r.conn.Close()
+// Subscribe implements WebSocketApi.
+func (w *webSocketApi) Subscribe(ctx context.Context, method string, params ...any) (Subscribe[map[string]any], error) {
+	return subscribe[map[string]any](w, ctx, method, params...)
 }
 
-func (w *webSocketApi) subscribe(ctx context.Context, method string, params ...any) (result subscribeRawResult, err error) {
-	// 建立连接
-	conn, _, err := w.dialer.DialContext(ctx, w.webSocketUrl, nil)
-	if err != nil {
-		return
-	}
-	// 发送订阅请求
-	req := NewJsonRpcBody(method, params...)
-	debugReq, _ := json.Marshal(req)
-	log.Debug().Msgf("Subscribe:开始订阅, req=%s", debugReq)
-	err = conn.WriteJSON(req)
-	if err != nil {
-		conn.Close()
-		return
-	}
-	// 读取订阅请求响应
-	var resp JsonRpcResponse[string]
-	err = conn.ReadJSON(&resp)
-	if err != nil {
-		log.Error().Err(err).Msgf("Subscribe:订阅失败, req=%v", req)
-		conn.Close()
-		return
-	}
-	// 开始读取订阅数据
-	result = subscribeRawResult{
-		conn: conn,
-		id:   resp.Result,
-	}
-	return
+// Close implements WebSocketApi.
+func (w *webSocketApi) Close() error {
+	return w.conn.close()
 }
@@ -0,0 +1,530 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// ErrSubscriptionClosed 订阅已被主动结束
+	ErrSubscriptionClosed = errors.New("订阅已结束")
+	// ErrWebSocketClosed websocket客户端已关闭
+	ErrWebSocketClosed = errors.New("websocket客户端已关闭")
+	// ErrSlowConsumer 订阅数据的缓存已满，读取过慢的订阅被结束，避免阻塞同一连接上的其它订阅、请求的响应和心跳
+	ErrSlowConsumer = errors.New("订阅数据读取过慢，缓存已满")
+	// errConnectionLost 请求发出后、收到响应前连接断开
+	errConnectionLost = errors.New("websocket连接已断开")
+)
+
+// wsMessage 节点通过websocket发送的消息，为请求的响应或订阅数据
+type wsMessage struct {
+	Id     int                `json:"id,omitempty"`
+	Method string             `json:"method,omitempty"` // 订阅数据的方法名, latc_subscription, node_subscription
+	Params SubscriptionResult `json:"params,omitempty"` // 订阅数据
+	Result json.RawMessage    `json:"result,omitempty"`
+	Error  *JsonRpcError      `json:"error,omitempty"`
+}
+
+// wsReply 请求的响应或连接断开的错误
+type wsReply struct {
+	msg *wsMessage
+	err error
+}
+
+// wsCall 等待响应的请求
+type wsCall struct {
+	sub  *subscription // 订阅请求，收到响应时在读取循环中登记订阅ID，避免丢失紧随响应到达的订阅数据
+	done chan wsReply
+}
+
+// wsConn 与单个节点共用的websocket连接，按订阅ID分发多个订阅的数据，定时发送心跳，断线后按指数退避重连并重新订阅
+type wsConn struct {
+	url    string
+	dialer *websocket.Dialer
+	param  *WebSocketApiInitParam
+
+	startMu sync.Mutex
+	started bool
+
+	writeMu sync.Mutex // gorilla/websocket 不支持并发写
+
+	mu     sync.Mutex
+	conn   *websocket.Conn            // 当前连接，断线期间为nil
+	gen    uint64                     // 当前连接的序号，每次重连后加1
+	ready  chan struct{}              // 连接建立后关闭，断开后重新创建
+	nextId int                        // 请求ID
+	calls  map[int]*wsCall            // 等待响应的请求
+	subs   map[string]*subscription   // 当前连接上的订阅，key为订阅ID
+	active map[*subscription]struct{} // 所有未结束的订阅，重连后重新订阅
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newWsConn(param *WebSocketApiInitParam, dialer *websocket.Dialer) *wsConn {
+	return &wsConn{
+		url:    param.WebSocketUrl,
+		dialer: dialer,
+		param:  param,
+		ready:  make(chan struct{}),
+		calls:  make(map[int]*wsCall),
+		subs:   make(map[string]*subscription),
+		active: make(map[*subscription]struct{}),
+		closed: make(chan struct{}),
+	}
+}
+
+// start 首次使用时建立连接并启动读取循环
+func (c *wsConn) start(ctx context.Context) error {
+	c.startMu.Lock()
+	defer c.startMu.Unlock()
+	if c.isClosed() {
+		return ErrWebSocketClosed
+	}
+	if c.started {
+		return nil
+	}
+	conn, _, err := c.dialer.DialContext(ctx, c.url, nil)
+	if err != nil {
+		return err
+	}
+	c.started = true
+	go c.run(conn)
+	return nil
+}
+
+func (c *wsConn) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// run 读取连接上的消息，连接断开后重连，直到客户端关闭
+func (c *wsConn) run(conn *websocket.Conn) {
+	for {
+		gen := c.attach(conn)
+		go c.resubscribe(conn, gen)
+		err := c.serve(conn)
+		c.detach(conn)
+		if c.isClosed() {
+			return
+		}
+		log.Warn().Err(err).Msgf("websocket连接断开，开始重连，url: %s", c.url)
+		if conn = c.reconnect(); conn == nil {
+			return
+		}
+		log.Info().Msgf("websocket重连成功，url: %s", c.url)
+	}
+}
+
+// attach 将新建立的连接设为当前连接
+func (c *wsConn) attach(conn *websocket.Conn) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn = conn
+	c.gen++
+	close(c.ready)
+	return c.gen
+}
+
+// detach 连接断开后，清空当前连接上的订阅ID，并结束所有等待响应的请求
+func (c *wsConn) detach(conn *websocket.Conn) {
+	c.mu.Lock()
+	calls := c.calls
+	c.conn = nil
+	c.ready = make(chan struct{})
+	c.calls = make(map[int]*wsCall)
+	c.subs = make(map[string]*subscription)
+	c.mu.Unlock()
+
+	_ = conn.Close()
+	for _, call := range calls {
+		call.done <- wsReply{err: errConnectionLost}
+	}
+}
+
+// reconnect 按指数退避重连，客户端关闭时返回nil
+func (c *wsConn) reconnect() *websocket.Conn {
+	delay := c.param.ReconnectMinDelay
+	for {
+		select {
+		case <-c.closed:
+			return nil
+		case <-time.After(delay):
+		}
+		conn, _, err := c.dialer.Dial(c.url, nil)
+		if err == nil {
+			return conn
+		}
+		log.Warn().Err(err).Msgf("websocket重连失败，%s后重试，url: %s", delay, c.url)
+		delay = min(delay*2, c.param.ReconnectMaxDelay)
+	}
+}
+
+// serve 读取并分发连接上的消息，直到连接出错
+func (c *wsConn) serve(conn *websocket.Conn) error {
+	stop := make(chan struct{})
+	defer close(stop)
+	go c.heartbeat(conn, stop)
+
+	readTimeout := c.param.PingInterval + c.param.PongTimeout
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(readTimeout))
+	})
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(readTimeout)); err != nil {
+			return err
+		}
+		_, b, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		var msg wsMessage
+		if err := json.Unmarshal(b, &msg); err != nil {
+			log.Warn().Err(err).Msgf("无法解析websocket消息：%s", b)
+			continue
+		}
+		c.dispatch(&msg)
+	}
+}
+
+// heartbeat 定时发送ping，发送失败时关闭连接以触发重连
+func (c *wsConn) heartbeat(conn *websocket.Conn, stop <-chan struct{}) {
+	ticker := time.NewTicker(c.param.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.param.PongTimeout)); err != nil {
+				log.Warn().Err(err).Msgf("websocket发送心跳失败，url: %s", c.url)
+				_ = conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// dispatch 将订阅数据分发给对应的订阅，将响应交给等待的请求
+func (c *wsConn) dispatch(msg *wsMessage) {
+	if msg.Method != "" {
+		c.mu.Lock()
+		sub, ok := c.subs[msg.Params.ID]
+		c.mu.Unlock()
+		if !ok {
+			log.Debug().Msgf("收到未知订阅的数据，subscription: %s", msg.Params.ID)
+			return
+		}
+		sub.deliver(msg.Params.Result)
+		return
+	}
+
+	c.mu.Lock()
+	call, ok := c.calls[msg.Id]
+	delete(c.calls, msg.Id)
+	if ok && call.sub != nil && msg.Error == nil && !call.sub.isDone() {
+		var id string
+		if err := json.Unmarshal(msg.Result, &id); err == nil {
+			call.sub.id = id
+			c.subs[id] = call.sub
+		}
+	}
+	c.mu.Unlock()
+	if ok {
+		call.done <- wsReply{msg: msg}
+	}
+}
+
+// waitConn 等待连接建立
+func (c *wsConn) waitConn(ctx context.Context) (*websocket.Conn, uint64, error) {
+	for {
+		c.mu.Lock()
+		conn, gen, ready := c.conn, c.gen, c.ready
+		c.mu.Unlock()
+		if conn != nil {
+			return conn, gen, nil
+		}
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			return nil, 0, ctx.Err()
+		case <-c.closed:
+			return nil, 0, ErrWebSocketClosed
+		}
+	}
+}
+
+// call 在指定的连接上发送请求并等待响应，sub不为nil时为订阅请求
+func (c *wsConn) call(ctx context.Context, conn *websocket.Conn, sub *subscription, method string, params ...any) (json.RawMessage, error) {
+	c.mu.Lock()
+	if c.conn != conn {
+		c.mu.Unlock()
+		return nil, errConnectionLost
+	}
+	c.nextId++
+	body := NewJsonRpcBody(method, params...)
+	body.Id = c.nextId
+	call := &wsCall{sub: sub, done: make(chan wsReply, 1)}
+	c.calls[body.Id] = call
+	c.mu.Unlock()
+
+	log.Debug().Msgf("websocket开始发送请求，url: %s, body: %+v", c.url, body)
+	c.writeMu.Lock()
+	_ = conn.SetWriteDeadline(time.Now().Add(c.param.RequestTimeout))
+	err := conn.WriteJSON(body)
+	c.writeMu.Unlock()
+	if err != nil {
+		c.removeCall(body.Id)
+		_ = conn.Close()
+		return nil, fmt.Errorf("%w：%w", errConnectionLost, err)
+	}
+
+	select {
+	case reply := <-call.done:
+		if reply.err != nil {
+			return nil, reply.err
+		}
+		if reply.msg.Error != nil {
+			return nil, reply.msg.Error.Error()
+		}
+		return reply.msg.Result, nil
+	case <-ctx.Done():
+		c.removeCall(body.Id)
+		return nil, ctx.Err()
+	case <-c.closed:
+		return nil, ErrWebSocketClosed
+	}
+}
+
+func (c *wsConn) removeCall(id int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.calls, id)
+}
+
+// subscribe 在共用的连接上订阅，订阅请求发出后连接断开时不返回错误，由重连后重新订阅
+func (c *wsConn) subscribe(ctx context.Context, method string, params ...any) (*subscription, error) {
+	if err := c.start(ctx); err != nil {
+		return nil, err
+	}
+	sub := &subscription{
+		conn:   c,
+		method: method,
+		params: params,
+		ch:     make(chan json.RawMessage, c.param.SubscriptionBufferSize),
+		done:   make(chan struct{}),
+	}
+	for {
+		conn, gen, err := c.waitConn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		if c.gen != gen {
+			c.mu.Unlock()
+			continue
+		}
+		sub.gen = gen
+		c.active[sub] = struct{}{}
+		c.mu.Unlock()
+
+		_, err = c.call(ctx, conn, sub, method, params...)
+		if err == nil || (errors.Is(err, errConnectionLost) && ctx.Err() == nil) {
+			log.Debug().Msgf("订阅成功，method: %s, subscription: %s", method, sub.ID())
+			return sub, nil
+		}
+		log.Error().Err(err).Msgf("订阅失败，method: %s, params: %v", method, params)
+		sub.finish(err)
+		c.mu.Lock()
+		delete(c.active, sub)
+		c.mu.Unlock()
+		return nil, err
+	}
+}
+
+// resubscribe 重连后重新发送所有未结束订阅的订阅请求，节点拒绝订阅时结束该订阅
+func (c *wsConn) resubscribe(conn *websocket.Conn, gen uint64) {
+	c.mu.Lock()
+	subs := make([]*subscription, 0, len(c.active))
+	for sub := range c.active {
+		if sub.gen < gen {
+			sub.gen = gen
+			subs = append(subs, sub)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, sub := range subs {
+		ctx, cancel := context.WithTimeout(context.Background(), c.param.RequestTimeout)
+		_, err := c.call(ctx, conn, sub, sub.method, sub.params...)
+		cancel()
+		switch {
+		case err == nil:
+			log.Info().Msgf("重新订阅成功，method: %s, subscription: %s", sub.method, sub.ID())
+		case errors.Is(err, errConnectionLost) || errors.Is(err, ErrWebSocketClosed):
+			return
+		case errors.Is(err, context.DeadlineExceeded):
+			// 超时的订阅在下次重连时重试
+			log.Warn().Err(err).Msgf("重新订阅超时，method: %s", sub.method)
+		default:
+			log.Error().Err(err).Msgf("重新订阅失败，结束订阅，method: %s", sub.method)
+			sub.finish(err)
+			c.mu.Lock()
+			delete(c.active, sub)
+			c.mu.Unlock()
+		}
+	}
+}
+
+// close 关闭连接，结束所有订阅
+func (c *wsConn) close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		c.mu.Lock()
+		conn := c.conn
+		subs := c.active
+		c.active = make(map[*subscription]struct{})
+		c.mu.Unlock()
+		for sub := range subs {
+			sub.finish(ErrWebSocketClosed)
+		}
+		if conn != nil {
+			c.writeMu.Lock()
+			_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+			c.writeMu.Unlock()
+			err = conn.Close()
+		}
+	})
+	return err
+}
+
+// subscription 共用连接上的一个订阅
+type subscription struct {
+	conn   *wsConn
+	method string // 订阅方法名, latc_subscribe, node_subscribe
+	params []any  // 订阅参数，重新订阅时使用
+	ch     chan json.RawMessage
+
+	done     chan struct{}
+	doneOnce sync.Once
+	err      error // 订阅结束的原因，done关闭后可读
+
+	// 以下字段由 wsConn.mu 保护
+	id  string // 节点返回的订阅ID
+	gen uint64 // 最近一次发送订阅请求的连接序号
+}
+
+func (s *subscription) ID() string {
+	s.conn.mu.Lock()
+	defer s.conn.mu.Unlock()
+	return s.id
+}
+
+func (s *subscription) Err() error {
+	if s.isDone() {
+		return s.err
+	}
+	return nil
+}
+
+func (s *subscription) isDone() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// finish 结束订阅，返回是否为首次结束
+func (s *subscription) finish(err error) bool {
+	finished := false
+	s.doneOnce.Do(func() {
+		s.err = err
+		close(s.done)
+		finished = true
+	})
+	return finished
+}
+
+// deliver 写入一条订阅数据，在读取循环中调用，不能阻塞，缓存满时以 ErrSlowConsumer 结束订阅
+func (s *subscription) deliver(data json.RawMessage) {
+	select {
+	case s.ch <- data:
+	case <-s.done:
+	default:
+		if !s.finish(ErrSlowConsumer) {
+			return
+		}
+		log.Warn().Msgf("订阅数据的缓存已满，结束该订阅，subscription: %s", s.ID())
+		// 取消订阅的请求需要读取循环接收响应，不能在读取循环中等待
+		go func() {
+			if err := s.unsubscribe(); err != nil {
+				log.Warn().Err(err).Msgf("取消读取过慢的订阅失败，subscription: %s", s.ID())
+			}
+		}()
+	}
+}
+
+// read 读取一条订阅数据，订阅结束后返回结束的原因
+func (s *subscription) read() (json.RawMessage, error) {
+	select {
+	case data := <-s.ch:
+		return data, nil
+	case <-s.done:
+		return nil, s.err
+	}
+}
+
+// Close 结束订阅，订阅在当前连接上有效时向节点发送取消订阅的请求
+func (s *subscription) Close() error {
+	if !s.finish(ErrSubscriptionClosed) {
+		return nil
+	}
+	return s.unsubscribe()
+}
+
+// unsubscribe 将已结束的订阅从连接上移除，订阅在当前连接上有效时向节点发送取消订阅的请求
+func (s *subscription) unsubscribe() error {
+	c := s.conn
+	c.mu.Lock()
+	delete(c.active, s)
+	id := s.id
+	if c.subs[id] == s {
+		delete(c.subs, id)
+	}
+	conn := c.conn
+	registered := conn != nil && id != "" && s.gen == c.gen
+	c.mu.Unlock()
+	if !registered {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.param.RequestTimeout)
+	defer cancel()
+	_, err := c.call(ctx, conn, nil, unsubscribeMethod(s.method), id)
+	if errors.Is(err, errConnectionLost) || errors.Is(err, ErrWebSocketClosed) {
+		// 连接已断开，节点上的订阅随连接一起失效
+		return nil
+	}
+	return err
+}
+
+// unsubscribeMethod 订阅方法对应的取消订阅方法，如 node_subscribe 对应 node_unsubscribe
+func unsubscribeMethod(method string) string {
+	namespace, _, _ := strings.Cut(method, "_")
+	return namespace + "_unsubscribe"
+}
@@ -0,0 +1,84 @@
+package lattice
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/LatticeBCLab/go-lattice/common/constant"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/LatticeBCLab/go-lattice/lattice/signer"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCredentialsSigner(t *testing.T) {
+	node := newMockNode(t)
+	node.handle("latc_getCurrentTBDB", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.LatestBlock{Height: 3, Hash: common.HexToHash("0x03")}, nil
+	})
+	var sent []*block.Transaction
+	node.handle("wallet_sendRawTBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		var tx block.Transaction
+		_ = json.Unmarshal(params[0], &tx)
+		sent = append(sent, &tx)
+		return common.HexToHash("0x04"), nil
+	})
+	svc := newMockLattice(t, node, nil)
+	credentials := newTestCredentials(t)
+	s, err := signer.NewPrivateKeySigner(types.Sm2p256v1, credentials.PrivateKey)
+	require.NoError(t, err)
+
+	t.Run("sign with signer", func(t *testing.T) {
+		sent = nil
+		_, err := svc.Transfer(context.Background(), &Credentials{AccountAddress: credentials.AccountAddress, Signer: s}, chainId, constant.ZeroAddress, constant.ZeroPayload, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, sent, 1)
+		hash, err := sent[0].RlpEncodeHash(1, types.Sm2p256v1)
+		require.NoError(t, err)
+		assert.NoError(t, signer.VerifySignature(types.Sm2p256v1, s.Address(), hash, hexutil.MustDecode(sent[0].Sign)))
+	})
+
+	t.Run("signer is not owner", func(t *testing.T) {
+		sent = nil
+		other := newTestCredentials(t)
+		_, err := svc.Transfer(context.Background(), &Credentials{AccountAddress: other.AccountAddress, Signer: s}, chainId, constant.ZeroAddress, constant.ZeroPayload, 0, 0)
+		assert.Error(t, err)
+		assert.Empty(t, sent)
+	})
+
+	t.Run("private key", func(t *testing.T) {
+		c := &Credentials{AccountAddress: credentials.AccountAddress, PrivateKey: credentials.PrivateKey}
+		got, err := c.GetSigner(types.Sm2p256v1)
+		require.NoError(t, err)
+		assert.Equal(t, s.Address(), got.Address())
+		assert.Nil(t, c.Signer)
+
+		// 私钥变更后使用新的私钥
+		other := newTestCredentials(t)
+		c.PrivateKey = other.PrivateKey
+		got, err = c.GetSigner(types.Sm2p256v1)
+		require.NoError(t, err)
+		assert.NotEqual(t, s.Address(), got.Address())
+	})
+
+	t.Run("concurrent get signer", func(t *testing.T) {
+		c := &Credentials{AccountAddress: credentials.AccountAddress, PrivateKey: credentials.PrivateKey}
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				got, err := c.GetSigner(types.Sm2p256v1)
+				assert.NoError(t, err)
+				assert.Equal(t, s.Address(), got.Address())
+			}()
+		}
+		wg.Wait()
+	})
+}
@@ -0,0 +1,225 @@
+package lattice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/LatticeBCLab/go-lattice/abi"
+	"github.com/LatticeBCLab/go-lattice/common/constant"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/avast/retry-go"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// ErrContractProposalNotPassed 开启合约生命周期时，合约的部署提案未通过、已过期或已取消
+	ErrContractProposalNotPassed = errors.New("合约的部署提案未通过")
+	// errContractProposalPending 合约的部署提案正在投票
+	errContractProposalPending = errors.New("合约的部署提案正在投票")
+)
+
+// DeployContractRequest 部署合约的请求
+//
+//   - Abi                   合约的ABI，用于编码构造函数的参数，并绑定到部署后的合约
+//   - Bytecode              合约的字节码
+//   - Args                  构造函数的参数，通过 abi.LatticeAbi.GetConstructor 编码
+//   - Payload               交易备注，为空时使用 constant.ZeroPayload
+//   - Amount                转账金额
+//   - Joule                 交易手续费
+//   - RetryStrategy         等待部署交易回执的策略，为nil时使用 DefaultBackOffRetryStrategy
+//   - ProposalRetryStrategy 开启合约生命周期时等待部署提案通过的策略，为nil时每5秒查询一次，最多查询120次
+type DeployContractRequest struct {
+	Abi                   abi.LatticeAbi
+	Bytecode              string
+	Args                  []interface{}
+	Payload               string
+	Amount                uint64
+	Joule                 uint64
+	RetryStrategy         *RetryStrategy
+	ProposalRetryStrategy *RetryStrategy
+}
+
+// BoundContract 绑定到合约地址和ABI的合约，按方法名和参数调用或预执行合约
+type BoundContract struct {
+	svc        *lattice
+	abi        abi.LatticeAbi
+	address    string
+	receipt    *types.Receipt
+	proposalId string
+}
+
+// Address 合约地址
+func (c *BoundContract) Address() string {
+	return c.address
+}
+
+// Abi 合约的ABI
+func (c *BoundContract) Abi() abi.LatticeAbi {
+	return c.abi
+}
+
+// Receipt 部署合约的回执，通过 BindContract 绑定时为nil
+func (c *BoundContract) Receipt() *types.Receipt {
+	return c.receipt
+}
+
+// ProposalId 合约的部署提案ID，未开启合约生命周期时为空
+func (c *BoundContract) ProposalId() string {
+	return c.proposalId
+}
+
+// Encode 编码合约方法的参数
+//
+// Parameters:
+//   - method string: 方法名
+//   - args ...interface{}: 方法的参数
+//
+// Returns:
+//   - string: 带0x前缀的16进制字符串
+//   - error
+func (c *BoundContract) Encode(method string, args ...interface{}) (string, error) {
+	fn, err := c.abi.GetLatticeFunction(method, args...)
+	if err != nil {
+		return "", err
+	}
+	return fn.Encode()
+}
+
+// Call 发起调用合约方法的交易，等待回执需要传入 WithWaitReceipt 或 WithRetryStrategy，返回值可以通过 abi.DecodeReturn 解码回执的 ContractRet
+//
+// Parameters:
+//   - ctx context.Context
+//   - credentials *Credentials
+//   - chainId string
+//   - method string: 方法名
+//   - args []interface{}: 方法的参数
+//   - opts ...SubmitOption: 同 Lattice.Submit
+//
+// Returns:
+//   - *SubmitResult
+//   - error
+func (c *BoundContract) Call(ctx context.Context, credentials *Credentials, chainId, method string, args []interface{}, opts ...SubmitOption) (*SubmitResult, error) {
+	data, err := c.Encode(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	req := &TxRequest{Type: block.TransactionTypeCallContract, Linker: c.address, Code: data, Payload: constant.ZeroPayload}
+	return c.svc.Submit(ctx, credentials, chainId, req, opts...)
+}
+
+// PreCall 预执行合约方法，并解码返回值
+//
+// Parameters:
+//   - ctx context.Context
+//   - chainId string
+//   - owner string: 预执行的账户地址
+//   - method string: 方法名
+//   - args ...interface{}: 方法的参数
+//
+// Returns:
+//   - []string: abi解码后的返回值，同 abi.DecodeReturn
+//   - *types.Receipt: 预执行的回执
+//   - error
+func (c *BoundContract) PreCall(ctx context.Context, chainId, owner, method string, args ...interface{}) ([]string, *types.Receipt, error) {
+	data, err := c.Encode(method, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	receipt, err := c.svc.PreCallContract(ctx, chainId, owner, c.address, data, constant.ZeroPayload)
+	if err != nil {
+		return nil, receipt, err
+	}
+	result, err := abi.DecodeReturn(c.abi.RawAbi(), method, receipt.ContractRet)
+	return result, receipt, err
+}
+
+func (svc *lattice) BindContract(contractAddress string, contractAbi abi.LatticeAbi) *BoundContract {
+	svc.RegisterContractAbi(contractAddress, contractAbi)
+	return &BoundContract{svc: svc, abi: contractAbi, address: contractAddress}
+}
+
+func (svc *lattice) DeployBoundContract(ctx context.Context, credentials *Credentials, chainId string, req *DeployContractRequest) (*BoundContract, error) {
+	constructorArgs, err := req.Abi.GetConstructor(req.Args...).Encode()
+	if err != nil {
+		return nil, fmt.Errorf("编码构造函数的参数失败：%w", err)
+	}
+	data := "0x" + strings.TrimPrefix(req.Bytecode, "0x") + strings.TrimPrefix(constructorArgs, "0x")
+
+	payload := req.Payload
+	if payload == "" {
+		payload = constant.ZeroPayload
+	}
+	retryStrategy := req.RetryStrategy
+	if retryStrategy == nil {
+		retryStrategy = DefaultBackOffRetryStrategy()
+	}
+	_, receipt, err := svc.DeployContractWaitReceipt(ctx, credentials, chainId, data, payload, req.Amount, req.Joule, retryStrategy)
+	if err != nil {
+		return nil, err
+	}
+	// 未开启 Options.ReturnRevertError 时部署失败不返回错误，失败的合约不绑定，也不会有部署提案
+	if !receipt.Success {
+		return nil, fmt.Errorf("部署合约失败：%w", abi.DecodeRevertError(req.Abi.RawAbi(), receipt.ContractRet))
+	}
+	if receipt.ContractAddress == "" {
+		return nil, errors.New("部署合约的回执中没有合约地址")
+	}
+	contract := svc.BindContract(receipt.ContractAddress, req.Abi)
+	contract.receipt = receipt
+
+	config, err := svc.httpApi.GetNodeConfirmedConfiguration(ctx, chainId)
+	if err != nil {
+		log.Error().Err(err)
+		return contract, err
+	}
+	if !config.EnableContractLifecycle {
+		return contract, nil
+	}
+
+	proposalRetryStrategy := req.ProposalRetryStrategy
+	if proposalRetryStrategy == nil {
+		proposalRetryStrategy = NewFixedRetryStrategy(120, 5*time.Second)
+	}
+	contract.proposalId, err = svc.waitContractProposal(ctx, chainId, contract.address, proposalRetryStrategy)
+	return contract, err
+}
+
+// waitContractProposal 等待合约的部署提案投票通过，返回提案ID
+func (svc *lattice) waitContractProposal(ctx context.Context, chainId, contractAddress string, retryStrategy *RetryStrategy) (string, error) {
+	var proposalId string
+	err := retry.Do(
+		func() error {
+			information, err := svc.httpApi.GetContractInformation(ctx, chainId, contractAddress)
+			if err != nil {
+				return err
+			}
+			if len(information.ProposalIds) == 0 {
+				return errContractProposalPending
+			}
+			proposalId = information.ProposalIds[len(information.ProposalIds)-1]
+
+			var proposal types.Proposal[types.ContractLifecycleProposal]
+			if err := svc.httpApi.GetProposalById(ctx, chainId, proposalId, &proposal); err != nil {
+				return err
+			}
+			if proposal.Content == nil {
+				return errContractProposalPending
+			}
+			switch proposal.Content.State {
+			case types.ProposalStateSUCCESS:
+				return nil
+			case types.ProposalStateNONE, types.ProposalStateINITIAL, types.ProposalStateNOTSTART:
+				log.Debug().Msgf("合约【%s】的部署提案【%s】正在投票", contractAddress, proposalId)
+				return errContractProposalPending
+			default:
+				return retry.Unrecoverable(fmt.Errorf("%w，提案ID：%s，提案状态：%d", ErrContractProposalNotPassed, proposalId, proposal.Content.State))
+			}
+		},
+		append(retryStrategy.GetRetryStrategyOpts(), retry.Context(ctx), retry.LastErrorOnly(true))...,
+	)
+	return proposalId, err
+}
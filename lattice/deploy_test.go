@@ -0,0 +1,127 @@
+package lattice
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/LatticeBCLab/go-lattice/abi"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeployBoundContract(t *testing.T) {
+	contractAbi := abi.NewAbi(`[{"inputs":[{"internalType":"string","name":"name","type":"string"}],"stateMutability":"nonpayable","type":"constructor"},{"inputs":[],"name":"name","outputs":[{"internalType":"string","name":"","type":"string"}],"stateMutability":"view","type":"function"}]`)
+	contractAddress := "zltc_QLbz7JHiBTspS962RLKV8GndWFwjA5K66"
+	nameRet, err := contractAbi.RawAbi().Methods["name"].Outputs.Pack("lattice")
+	require.NoError(t, err)
+
+	node := newMockNode(t)
+	node.handle("latc_getCurrentTBDB", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.LatestBlock{Height: 3, Hash: common.HexToHash("0x03")}, nil
+	})
+	var sent []*block.Transaction
+	node.handle("wallet_sendRawTBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		var tx block.Transaction
+		_ = json.Unmarshal(params[0], &tx)
+		sent = append(sent, &tx)
+		return common.HexToHash("0x04"), nil
+	})
+	deploySucceeded := true
+	node.handle("latc_getReceipt", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		if !deploySucceeded {
+			return &types.Receipt{DBlockNumber: 10, Success: false, ContractAddress: contractAddress, ContractRet: "0x"}, nil
+		}
+		return &types.Receipt{DBlockNumber: 10, Success: true, ContractAddress: contractAddress}, nil
+	})
+	node.handle("wallet_preExecuteContract", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.Receipt{Success: true, ContractRet: hexutil.Encode(nameRet)}, nil
+	})
+	lifecycle := false
+	node.handle("wallet_getConfirmConfig", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.NodeConfirmedConfiguration{EnableContractLifecycle: lifecycle}, nil
+	})
+	node.handle("wallet_getContractState", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.ContractInformation{ContractAddress: contractAddress, ProposalIds: []string{"proposal-1"}}, nil
+	})
+	var proposalStates []types.ProposalState
+	node.handle("wallet_getProposalById", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		state := proposalStates[0]
+		if len(proposalStates) > 1 {
+			proposalStates = proposalStates[1:]
+		}
+		return &types.Proposal[types.ContractLifecycleProposal]{
+			Type:    types.ProposalTypeContractLifecycle,
+			Content: &types.ContractLifecycleProposal{Id: "proposal-1", State: state, ContractManagerBits: []byte{1}},
+		}, nil
+	})
+
+	credentials := newTestCredentials(t)
+	req := &DeployContractRequest{
+		Abi:                   contractAbi,
+		Bytecode:              "0x6080",
+		Args:                  []interface{}{"lattice"},
+		RetryStrategy:         NewBackOffRetryStrategy(3, 10*time.Millisecond),
+		ProposalRetryStrategy: NewFixedRetryStrategy(5, 10*time.Millisecond),
+	}
+
+	t.Run("without contract lifecycle", func(t *testing.T) {
+		sent = nil
+		svc := newMockLattice(t, node, nil)
+		contract, err := svc.DeployBoundContract(context.Background(), credentials, chainId, req)
+		require.NoError(t, err)
+		assert.Equal(t, contractAddress, contract.Address())
+		assert.Empty(t, contract.ProposalId())
+		require.Len(t, sent, 1)
+		assert.Equal(t, block.TransactionTypeDeployContract, sent[0].Type)
+		constructorArgs, err := contractAbi.GetConstructor("lattice").Encode()
+		require.NoError(t, err)
+		assert.Equal(t, "0x6080"+constructorArgs[2:], sent[0].Code)
+
+		result, _, err := contract.PreCall(context.Background(), chainId, credentials.AccountAddress, "name")
+		require.NoError(t, err)
+		assert.Equal(t, []string{`"lattice"`}, result)
+	})
+
+	t.Run("wait for proposal", func(t *testing.T) {
+		lifecycle = true
+		defer func() { lifecycle = false }()
+		proposalStates = []types.ProposalState{types.ProposalStateINITIAL, types.ProposalStateSUCCESS}
+		svc := newMockLattice(t, node, nil)
+		contract, err := svc.DeployBoundContract(context.Background(), credentials, chainId, req)
+		require.NoError(t, err)
+		assert.Equal(t, "proposal-1", contract.ProposalId())
+	})
+
+	t.Run("proposal failed", func(t *testing.T) {
+		lifecycle = true
+		defer func() { lifecycle = false }()
+		proposalStates = []types.ProposalState{types.ProposalStateFAILED}
+		svc := newMockLattice(t, node, nil)
+		contract, err := svc.DeployBoundContract(context.Background(), credentials, chainId, req)
+		assert.ErrorIs(t, err, ErrContractProposalNotPassed)
+		require.NotNil(t, contract)
+		assert.Equal(t, contractAddress, contract.Address())
+	})
+
+	t.Run("deploy failed", func(t *testing.T) {
+		lifecycle = true
+		deploySucceeded = false
+		defer func() { lifecycle, deploySucceeded = false, true }()
+		configCalls, stateCalls := node.callCount("wallet_getConfirmConfig"), node.callCount("wallet_getContractState")
+		svc := newMockLattice(t, node, nil)
+		contract, err := svc.DeployBoundContract(context.Background(), credentials, chainId, req)
+		assert.Nil(t, contract)
+		var revertErr *abi.ContractRevertError
+		require.ErrorAs(t, err, &revertErr)
+		assert.Equal(t, "0x", revertErr.Data)
+		assert.Equal(t, configCalls, node.callCount("wallet_getConfirmConfig"))
+		assert.Equal(t, stateCalls, node.callCount("wallet_getContractState"))
+	})
+}
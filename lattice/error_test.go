@@ -0,0 +1,66 @@
+package lattice
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/LatticeBCLab/go-lattice/common/codes"
+	"github.com/LatticeBCLab/go-lattice/common/status"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRpcError(t *testing.T) {
+	cases := []struct {
+		message  string
+		code     int16
+		sentinel error
+		status   codes.Code
+	}{
+		{"invalid signature", -32000, client.ErrInvalidSignature, codes.Unauthenticated},
+		{"Invalid Parent Hash", -32000, client.ErrParentHashMismatch, codes.Aborted},
+		{"insufficient balance for transfer", -32000, client.ErrInsufficientBalance, codes.FailedPrecondition},
+		{"contract not found", -32000, client.ErrContractNotFound, codes.NotFound},
+		{"the method latc_foo does not exist", -32601, client.ErrMethodNotFound, codes.Unimplemented},
+		{"missing value for required argument 0", -32602, nil, codes.InvalidArgument},
+	}
+	for _, c := range cases {
+		t.Run(c.message, func(t *testing.T) {
+			err := (&client.JsonRpcError{Code: c.code, Message: c.message}).Error()
+			assert.EqualError(t, err, (&client.RpcError{Code: c.code, Message: c.message}).Error())
+			if c.sentinel != nil {
+				assert.ErrorIs(t, err, c.sentinel)
+			}
+			var rpcErr *client.RpcError
+			require.ErrorAs(t, err, &rpcErr)
+			assert.Equal(t, c.code, rpcErr.Code)
+			var st *status.Status
+			require.ErrorAs(t, err, &st)
+			assert.Equal(t, c.status, st.Code)
+			assert.Equal(t, c.status, client.StatusFromError(err).Code)
+		})
+	}
+}
+
+func TestHttpApiErrors(t *testing.T) {
+	ctx := context.Background()
+	node := newMockNode(t)
+	node.handle("wallet_sendRawTBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return nil, &client.JsonRpcError{Code: -32000, Message: "insufficient balance"}
+	})
+	svc := newMockLattice(t, node, &Options{DisableRequestRetry: true})
+
+	_, err := svc.HttpApi().SendSignedTransaction(ctx, chainId, &block.Transaction{Amount: big.NewInt(0), Joule: big.NewInt(0)})
+	assert.ErrorIs(t, err, client.ErrInsufficientBalance)
+	assert.NotErrorIs(t, err, client.ErrNodeUnavailable)
+
+	node.server.Close()
+	exists, err := svc.HttpApi().ExistsBusinessContractAddress(ctx, chainId, "zltc_address")
+	assert.False(t, exists)
+	assert.ErrorIs(t, err, client.ErrNodeUnavailable)
+	assert.Equal(t, codes.Unavailable, client.StatusFromError(err).Code)
+}
@@ -0,0 +1,127 @@
+package lattice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// AutoJoule 交易的 Joule 为该值时，Submit、SubmitBatch 和交易队列在发送前通过 EstimateJoule 估算手续费
+	AutoJoule uint64 = math.MaxUint64
+
+	defaultJouleSafetyMargin = 0.2
+)
+
+// ErrInsufficientBalance 有通证链上账户的可用余额不足以支付转账金额和手续费
+var ErrInsufficientBalance = errors.New("账户余额不足")
+
+func (svc *lattice) EstimateJoule(ctx context.Context, chainId, owner string, req *TxRequest) (uint64, error) {
+	joule, err := svc.estimateJoule(ctx, chainId, owner, req)
+	if err != nil {
+		return 0, err
+	}
+	if err := svc.checkBalance(ctx, chainId, owner, new(big.Int).SetUint64(req.Amount), new(big.Int).SetUint64(joule)); err != nil {
+		return 0, err
+	}
+	return joule, nil
+}
+
+// estimateJoule 预执行交易估算手续费，不检查余额
+func (svc *lattice) estimateJoule(ctx context.Context, chainId, owner string, req *TxRequest) (uint64, error) {
+	estimateReq := *req
+	estimateReq.Joule = 0
+
+	var joule uint64
+	if req.Code != "" {
+		receipt, err := svc.preExecute(ctx, chainId, owner, &estimateReq)
+		if err != nil {
+			return 0, err
+		}
+		joule = uint64(math.Ceil(float64(receipt.JouleUsed) * (1 + svc.jouleSafetyMargin())))
+	}
+	log.Debug().Msgf("估算%s交易的手续费为：%d", req.Type, joule)
+	return joule, nil
+}
+
+func (svc *lattice) jouleSafetyMargin() float64 {
+	if svc.options.JouleSafetyMargin > 0 {
+		return svc.options.JouleSafetyMargin
+	}
+	return defaultJouleSafetyMargin
+}
+
+// checkBalance 有通证链上检查账户的可用余额是否足够支付转账金额和手续费，无通证链不检查
+func (svc *lattice) checkBalance(ctx context.Context, chainId, owner string, amount, joule *big.Int) error {
+	if svc.chainConfig.TokenLess {
+		return nil
+	}
+	balance, err := svc.httpApi.GetBalanceWithPending(ctx, chainId, owner)
+	if err != nil {
+		log.Error().Err(err)
+		return err
+	}
+	value := balance.AvailableBalance
+	if value == "" {
+		value = balance.Total
+	}
+	available, ok := new(big.Int).SetString(value, 0)
+	if !ok {
+		return fmt.Errorf("解析账户【%s】的余额%s失败", owner, value)
+	}
+	required := new(big.Int).Add(amount, joule)
+	if available.Cmp(required) < 0 {
+		return fmt.Errorf("%w，账户：%s，可用余额：%s，需要：%s（转账金额%s，手续费%s）", ErrInsufficientBalance, owner, available, required, amount, joule)
+	}
+	return nil
+}
+
+// resolveJoule 交易的 Joule 为 AutoJoule 时估算手续费，返回估算后的交易请求，不修改传入的请求
+func (svc *lattice) resolveJoule(ctx context.Context, chainId, owner string, req *TxRequest) (*TxRequest, error) {
+	if req.Joule != AutoJoule {
+		return req, nil
+	}
+	joule, err := svc.EstimateJoule(ctx, chainId, owner, req)
+	if err != nil {
+		return nil, err
+	}
+	resolved := *req
+	resolved.Joule = joule
+	return &resolved, nil
+}
+
+// resolveBatchJoule 估算批量交易中 Joule 为 AutoJoule 的交易的手续费，有交易需要估算时，
+// 估算完成后对所有交易的转账金额和手续费之和检查一次余额，返回估算后的交易请求，不修改传入的请求
+//
+// 预执行基于链上的当前状态，不包含批量交易中前序交易的执行结果
+func (svc *lattice) resolveBatchJoule(ctx context.Context, chainId, owner string, reqs []*TxRequest) ([]*TxRequest, error) {
+	resolved := make([]*TxRequest, len(reqs))
+	estimated := false
+	totalAmount, totalJoule := new(big.Int), new(big.Int)
+	for i, req := range reqs {
+		resolved[i] = req
+		if req.Joule == AutoJoule {
+			joule, err := svc.estimateJoule(ctx, chainId, owner, req)
+			if err != nil {
+				return nil, fmt.Errorf("估算第%d笔交易的手续费失败：%w", i+1, err)
+			}
+			resolvedReq := *req
+			resolvedReq.Joule = joule
+			resolved[i] = &resolvedReq
+			estimated = true
+		}
+		totalAmount.Add(totalAmount, new(big.Int).SetUint64(resolved[i].Amount))
+		totalJoule.Add(totalJoule, new(big.Int).SetUint64(resolved[i].Joule))
+	}
+	if !estimated {
+		return resolved, nil
+	}
+	if err := svc.checkBalance(ctx, chainId, owner, totalAmount, totalJoule); err != nil {
+		return nil, err
+	}
+	return resolved, nil
+}
@@ -0,0 +1,106 @@
+package lattice
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/LatticeBCLab/go-lattice/common/constant"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateJoule(t *testing.T) {
+	node := newMockNode(t)
+	node.handle("latc_getCurrentTBDB", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.LatestBlock{Height: 3, Hash: common.HexToHash("0x03")}, nil
+	})
+	var sent []*block.Transaction
+	node.handle("wallet_sendRawTBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		var tx block.Transaction
+		_ = json.Unmarshal(params[0], &tx)
+		sent = append(sent, &tx)
+		return common.HexToHash("0x04"), nil
+	})
+	node.handle("wallet_sendRawBatchTBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		var txs []*block.Transaction
+		_ = json.Unmarshal(params[0], &txs)
+		sent = append(sent, txs...)
+		hashes := make([]*common.Hash, len(txs))
+		for i, tx := range txs {
+			hash, _ := tx.CalculateTransactionHash(types.Sm2p256v1)
+			hashes[i] = &hash
+		}
+		return hashes, nil
+	})
+	node.handle("wallet_preExecuteContract", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.Receipt{Success: true, JouleUsed: 100}, nil
+	})
+	node.handle("latc_getBalanceWithPending", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.AccountBalance{Total: "200", AvailableBalance: "130"}, nil
+	})
+	credentials := newTestCredentials(t)
+	req := &TxRequest{Type: block.TransactionTypeCallContract, Linker: constant.ZeroAddress, Code: "0x01", Payload: constant.ZeroPayload}
+
+	t.Run("safety margin", func(t *testing.T) {
+		svc := newMockLattice(t, node, nil)
+		joule, err := svc.EstimateJoule(context.Background(), chainId, credentials.AccountAddress, req)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(120), joule)
+
+		svc = newMockLattice(t, node, &Options{JouleSafetyMargin: 0.5})
+		joule, err = svc.EstimateJoule(context.Background(), chainId, credentials.AccountAddress, req)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(150), joule)
+	})
+
+	t.Run("insufficient balance on token chain", func(t *testing.T) {
+		svc := newMockLattice(t, node, nil)
+		svc.chainConfig.TokenLess = false
+		_, err := svc.EstimateJoule(context.Background(), chainId, credentials.AccountAddress, req)
+		require.NoError(t, err)
+
+		transfer := &TxRequest{Type: block.TransactionTypeSend, Linker: credentials.AccountAddress, Payload: constant.ZeroPayload, Amount: 131}
+		_, err = svc.EstimateJoule(context.Background(), chainId, credentials.AccountAddress, transfer)
+		assert.ErrorIs(t, err, ErrInsufficientBalance)
+
+		sent = nil
+		_, err = svc.CallContract(context.Background(), credentials, chainId, constant.ZeroAddress, "0x01", constant.ZeroPayload, 20, AutoJoule)
+		assert.ErrorIs(t, err, ErrInsufficientBalance)
+		assert.Empty(t, sent)
+	})
+
+	t.Run("auto joule", func(t *testing.T) {
+		sent = nil
+		svc := newMockLattice(t, node, nil)
+		autoReq := *req
+		autoReq.Joule = AutoJoule
+		_, err := svc.Submit(context.Background(), credentials, chainId, &autoReq)
+		require.NoError(t, err)
+		_, err = svc.SubmitBatch(context.Background(), credentials, chainId, []*TxRequest{&autoReq})
+		require.NoError(t, err)
+		require.Len(t, sent, 2)
+		assert.Equal(t, int64(120), sent[0].Joule.Int64())
+		assert.Equal(t, int64(120), sent[1].Joule.Int64())
+		assert.Equal(t, AutoJoule, autoReq.Joule)
+	})
+
+	t.Run("batch checks cumulative balance", func(t *testing.T) {
+		sent = nil
+		svc := newMockLattice(t, node, nil)
+		svc.chainConfig.TokenLess = false
+		autoReq := *req
+		autoReq.Joule = AutoJoule
+		_, err := svc.SubmitBatch(context.Background(), credentials, chainId, []*TxRequest{&autoReq})
+		require.NoError(t, err)
+
+		sent = nil
+		_, err = svc.SubmitBatch(context.Background(), credentials, chainId, []*TxRequest{&autoReq, &autoReq})
+		assert.ErrorIs(t, err, ErrInsufficientBalance)
+		assert.Empty(t, sent)
+	})
+}
@@ -0,0 +1,209 @@
+package lattice
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+)
+
+// defaultAccountHistoryBatchSize 账户历史每批预取的交易区块数量
+const defaultAccountHistoryBatchSize = 50
+
+// AccountHistoryEntry 账户历史中的一笔交易
+//
+//   - Height           交易区块在账户链上的高度
+//   - TransactionBlock 交易区块
+//   - Receipt          交易回执，节点没有回执时为nil
+//   - Frozen           是否从压缩的区块中查询得到
+type AccountHistoryEntry struct {
+	Height           uint64
+	TransactionBlock *types.TransactionBlock
+	Receipt          *types.Receipt
+	Frozen           bool
+}
+
+// AccountHistoryIterator 按高度递增遍历账户的交易区块和回执，每批通过 GetTBlocksByHeights 预取，
+// 未查询到的区块已被压缩时通过 GetFreezeTBlockByNumber 和 GetFreezeReceipt 查询
+//
+//	it := svc.AccountHistory(ctx, chainId, address, 1)
+//	for it.Next() {
+//		entry := it.Entry() // entry.TransactionBlock, entry.Receipt
+//	}
+//	err := it.Err()
+type AccountHistoryIterator struct {
+	svc       *lattice
+	ctx       context.Context
+	chainId   string
+	address   string
+	next      uint64 // 下一批预取的起始高度
+	end       uint64 // 遍历的最大高度，第一次调用 Next 时查询账户的最新高度
+	started   bool
+	batchSize int
+	buffer    []*AccountHistoryEntry
+	current   *AccountHistoryEntry
+	err       error
+}
+
+func (svc *lattice) AccountHistory(ctx context.Context, chainId, address string, fromHeight uint64) *AccountHistoryIterator {
+	return &AccountHistoryIterator{
+		svc:       svc,
+		ctx:       ctx,
+		chainId:   chainId,
+		address:   address,
+		next:      max(fromHeight, 1),
+		batchSize: defaultAccountHistoryBatchSize,
+	}
+}
+
+// Next 移动到下一笔交易，遍历结束或出错时返回false，出错时通过 Err 获取错误
+func (it *AccountHistoryIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if !it.started {
+		it.started = true
+		latestBlock, err := it.svc.httpApi.GetLatestBlock(it.ctx, it.chainId, it.address)
+		if err != nil {
+			it.err = fmt.Errorf("查询账户的最新高度失败：%w", err)
+			return false
+		}
+		it.end = latestBlock.Height
+	}
+	if len(it.buffer) == 0 {
+		if it.next > it.end {
+			it.current = nil
+			return false
+		}
+		if it.err = it.prefetch(); it.err != nil {
+			it.current = nil
+			return false
+		}
+	}
+	it.current, it.buffer = it.buffer[0], it.buffer[1:]
+	return true
+}
+
+// Entry 当前的交易
+func (it *AccountHistoryIterator) Entry() *AccountHistoryEntry {
+	return it.current
+}
+
+// TransactionBlock 当前的交易区块
+func (it *AccountHistoryIterator) TransactionBlock() *types.TransactionBlock {
+	if it.current == nil {
+		return nil
+	}
+	return it.current.TransactionBlock
+}
+
+// Receipt 当前交易的回执
+func (it *AccountHistoryIterator) Receipt() *types.Receipt {
+	if it.current == nil {
+		return nil
+	}
+	return it.current.Receipt
+}
+
+// Err 遍历过程中的错误
+func (it *AccountHistoryIterator) Err() error {
+	return it.err
+}
+
+// prefetch 预取一批交易区块和回执，未查询到的区块从压缩的区块中查询
+func (it *AccountHistoryIterator) prefetch() error {
+	end := min(it.next+uint64(it.batchSize)-1, it.end)
+	heights := lo.RangeFrom(it.next, int(end-it.next+1))
+
+	live := make(map[uint64]*types.TransactionBlock, len(heights))
+	tblocks, err := it.svc.httpApi.GetTBlocksByHeights(it.ctx, it.chainId, it.address, heights)
+	if err != nil {
+		// 批量查询的区间内有压缩的区块时节点可能返回错误，逐个高度查询
+		log.Debug().Err(err).Msgf("批量查询交易区块失败，逐个高度查询，address: %s, 高度: %d-%d", it.address, it.next, end)
+	}
+	for _, tblock := range tblocks {
+		if tblock != nil && tblock.Height != nil && tblock.Hash != (common.Hash{}) {
+			live[tblock.Height.Uint64()] = tblock
+		}
+	}
+	receipts, err := it.liveReceipts(lo.FilterMap(heights, func(height uint64, _ int) (*types.TransactionBlock, bool) {
+		tblock, ok := live[height]
+		return tblock, ok
+	}))
+	if err != nil {
+		return err
+	}
+
+	entries := make([]*AccountHistoryEntry, 0, len(heights))
+	for _, height := range heights {
+		if err := it.ctx.Err(); err != nil {
+			return err
+		}
+		if tblock, ok := live[height]; ok {
+			entries = append(entries, &AccountHistoryEntry{Height: height, TransactionBlock: tblock, Receipt: receipts[tblock.Hash]})
+			continue
+		}
+		entry, err := it.fetch(height)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+	it.buffer = entries
+	it.next = end + 1
+	return nil
+}
+
+// liveReceipts 批量查询未压缩的交易区块的回执
+func (it *AccountHistoryIterator) liveReceipts(tblocks []*types.TransactionBlock) (map[common.Hash]*types.Receipt, error) {
+	result := make(map[common.Hash]*types.Receipt, len(tblocks))
+	if len(tblocks) == 0 {
+		return result, nil
+	}
+	hashes := lo.Map(tblocks, func(tblock *types.TransactionBlock, _ int) string { return tblock.Hash.String() })
+	receipts, err := it.svc.httpApi.GetReceipts(it.ctx, it.chainId, hashes)
+	if err != nil {
+		return nil, fmt.Errorf("批量查询回执失败：%w", err)
+	}
+	for i, receipt := range receipts {
+		if receipt == nil {
+			continue
+		}
+		hash := receipt.TBlockHash
+		if hash == (common.Hash{}) && len(receipts) == len(tblocks) {
+			hash = tblocks[i].Hash
+		}
+		result[hash] = receipt
+	}
+	return result, nil
+}
+
+// fetch 查询单个高度的交易区块和回执，未压缩的接口查询不到时查询压缩的区块
+func (it *AccountHistoryIterator) fetch(height uint64) (*AccountHistoryEntry, error) {
+	tblock, err := it.svc.httpApi.GetTBlockByHeight(it.ctx, it.chainId, it.address, height)
+	if err == nil && tblock != nil && tblock.Hash != (common.Hash{}) {
+		receipt, err := it.svc.httpApi.GetReceipt(it.ctx, it.chainId, tblock.Hash.String())
+		if err != nil {
+			return nil, fmt.Errorf("查询高度%d的回执失败：%w", height, err)
+		}
+		return &AccountHistoryEntry{Height: height, TransactionBlock: tblock, Receipt: receipt}, nil
+	}
+
+	bigHeight := new(big.Int).SetUint64(height)
+	tblock, err = it.svc.httpApi.GetFreezeTBlockByNumber(it.ctx, it.chainId, it.address, bigHeight)
+	if err != nil {
+		return nil, fmt.Errorf("查询高度%d的压缩交易区块失败：%w", height, err)
+	}
+	if tblock == nil || tblock.Hash == (common.Hash{}) {
+		return nil, fmt.Errorf("账户%s在高度%d的交易区块不存在", it.address, height)
+	}
+	receipt, err := it.svc.httpApi.GetFreezeReceipt(it.ctx, it.chainId, it.address, bigHeight)
+	if err != nil {
+		return nil, fmt.Errorf("查询高度%d的压缩回执失败：%w", height, err)
+	}
+	return &AccountHistoryEntry{Height: height, TransactionBlock: tblock, Receipt: receipt, Frozen: true}, nil
+}
@@ -0,0 +1,98 @@
+package lattice
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountHistory(t *testing.T) {
+	const frozenHeight = 3 // 高度小于等于3的区块已被压缩
+	tblockHash := func(height uint64) common.Hash { return common.BigToHash(new(big.Int).SetUint64(height + 100)) }
+	tblock := func(height uint64) *types.TransactionBlock {
+		return &types.TransactionBlock{Height: new(big.Int).SetUint64(height), Hash: tblockHash(height)}
+	}
+
+	node := newMockNode(t)
+	node.handle("latc_getCurrentTBDB", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.LatestBlock{Height: 7, Hash: tblockHash(7)}, nil
+	})
+	node.handle("latc_getTBlockByNumberRange", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		var heights []uint64
+		_ = json.Unmarshal(params[1], &heights)
+		tblocks := make([]*types.TransactionBlock, 0, len(heights))
+		for _, height := range heights {
+			if height > frozenHeight {
+				tblocks = append(tblocks, tblock(height))
+			}
+		}
+		return tblocks, nil
+	})
+	node.handle("latc_getTBlockByNumber", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return nil, &client.JsonRpcError{Code: -32000, Message: "block not found"}
+	})
+	node.handle("latc_getTBlockReceipts", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		var hashes []common.Hash
+		_ = json.Unmarshal(params[0], &hashes)
+		receipts := make([]*types.Receipt, len(hashes))
+		for i, hash := range hashes {
+			receipts[i] = &types.Receipt{TBlockHash: hash, Success: true}
+		}
+		return receipts, nil
+	})
+	node.handle("latc_getFreezeTBlockByNumber", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		var height uint64
+		_ = json.Unmarshal(params[1], &height)
+		return tblock(height), nil
+	})
+	node.handle("latc_getFreezeReceipt", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		var height uint64
+		_ = json.Unmarshal(params[1], &height)
+		return &types.Receipt{TBlockHash: tblockHash(height), Success: true}, nil
+	})
+	svc := newMockLattice(t, node, nil)
+	address := newTestCredentials(t).AccountAddress
+
+	t.Run("live and frozen blocks", func(t *testing.T) {
+		it := svc.AccountHistory(context.Background(), chainId, address, 0)
+		it.batchSize = 3
+		var heights []uint64
+		for it.Next() {
+			entry := it.Entry()
+			heights = append(heights, entry.Height)
+			assert.Equal(t, tblockHash(entry.Height), it.TransactionBlock().Hash)
+			require.NotNil(t, it.Receipt())
+			assert.Equal(t, tblockHash(entry.Height), it.Receipt().TBlockHash)
+			assert.Equal(t, entry.Height <= frozenHeight, entry.Frozen)
+		}
+		require.NoError(t, it.Err())
+		assert.Equal(t, []uint64{1, 2, 3, 4, 5, 6, 7}, heights)
+		assert.False(t, it.Next())
+		assert.Equal(t, 3, node.callCount("latc_getTBlockByNumberRange"))
+	})
+
+	t.Run("from height", func(t *testing.T) {
+		it := svc.AccountHistory(context.Background(), chainId, address, 6)
+		var heights []uint64
+		for it.Next() {
+			heights = append(heights, it.Entry().Height)
+		}
+		require.NoError(t, it.Err())
+		assert.Equal(t, []uint64{6, 7}, heights)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		it := svc.AccountHistory(ctx, chainId, address, 1)
+		assert.False(t, it.Next())
+		assert.ErrorIs(t, it.Err(), context.Canceled)
+	})
+}
@@ -0,0 +1,301 @@
+package lattice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+)
+
+const (
+	defaultIndexerBatchSize     = 20
+	defaultIndexerPollInterval  = time.Second
+	defaultIndexerMaxReorgDepth = 64
+)
+
+// ErrReorgTooDeep 分叉回滚的区块数量超过 IndexerConfig.MaxReorgDepth 仍未找到共同祖先
+var ErrReorgTooDeep = errors.New("分叉深度超过最大回滚深度")
+
+// IndexedBlock 索引的守护区块及其锚定的交易区块和回执
+//
+//   - ChainId      链ID
+//   - DaemonBlock  守护区块
+//   - Transactions 守护区块锚定的交易区块，顺序同 DaemonBlock.TxHashes
+//   - Receipts     交易区块的回执
+type IndexedBlock struct {
+	ChainId      string                    `json:"chainId"`
+	DaemonBlock  *types.DaemonBlock        `json:"daemonBlock"`
+	Transactions []*types.TransactionBlock `json:"transactions"`
+	Receipts     []*types.Receipt          `json:"receipts"`
+}
+
+// Height 守护区块的高度
+func (b *IndexedBlock) Height() uint64 {
+	if b.DaemonBlock == nil || b.DaemonBlock.Height == nil {
+		return 0
+	}
+	return b.DaemonBlock.Height.Uint64()
+}
+
+// Checkpoint 索引的进度，即已写入的最新守护区块
+type Checkpoint struct {
+	Height uint64      `json:"height"`
+	Hash   common.Hash `json:"hash"`
+}
+
+// Sink 索引数据的存储，一个 Sink 只存储一条链的数据，Indexer 按高度递增依次写入守护区块
+type Sink interface {
+	// Write 写入守护区块，高度为当前检查点的高度加1
+	//
+	// Parameters:
+	//   - ctx context.Context
+	//   - block *IndexedBlock
+	//
+	// Returns:
+	//   - error
+	Write(ctx context.Context, block *IndexedBlock) error
+
+	// Rollback 链发生分叉时删除高度大于height的守护区块
+	//
+	// Parameters:
+	//   - ctx context.Context
+	//   - height uint64: 回滚后的最新高度
+	//
+	// Returns:
+	//   - error
+	Rollback(ctx context.Context, height uint64) error
+
+	// Checkpoint 已写入的最新守护区块，用于重启后继续索引
+	//
+	// Parameters:
+	//   - ctx context.Context
+	//
+	// Returns:
+	//   - *Checkpoint: 没有写入任何区块时为nil
+	//   - error
+	Checkpoint(ctx context.Context) (*Checkpoint, error)
+}
+
+// IndexerConfig 索引器的配置
+type IndexerConfig struct {
+	StartHeight   uint64        // Sink 中没有检查点时开始索引的守护区块高度，默认为0
+	BatchSize     int           // 每次批量查询的守护区块数量，默认为20
+	PollInterval  time.Duration // 追上最新守护区块或查询失败后的等待间隔，默认为1秒
+	Confirmations uint64        // 只索引落后于最新守护区块该数量的区块，默认为0
+	MaxReorgDepth int           // 分叉时最多回滚的区块数量，默认为64
+}
+
+// Indexer 从检查点开始沿守护区块链索引守护区块及其锚定的交易区块和回执，写入 Sink，
+// 通过 ParentHash 检测分叉并回滚到共同祖先，重启后从 Sink 的检查点继续索引
+type Indexer interface {
+	// Run 持续索引直到ctx取消或 Sink 返回错误，查询节点失败时等待 PollInterval 后重试
+	//
+	// Parameters:
+	//   - ctx context.Context
+	//
+	// Returns:
+	//   - error: ctx取消时为 ctx.Err()
+	Run(ctx context.Context) error
+}
+
+// NewIndexer 初始化索引器
+//
+// Parameters:
+//   - httpApi client.HttpApi
+//   - chainId string
+//   - sink Sink
+//   - config *IndexerConfig: 为nil时使用默认配置
+//
+// Returns:
+//   - Indexer
+func NewIndexer(httpApi client.HttpApi, chainId string, sink Sink, config *IndexerConfig) Indexer {
+	if config == nil {
+		config = &IndexerConfig{}
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultIndexerBatchSize
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultIndexerPollInterval
+	}
+	if config.MaxReorgDepth <= 0 {
+		config.MaxReorgDepth = defaultIndexerMaxReorgDepth
+	}
+	return &indexer{httpApi: httpApi, chainId: chainId, sink: sink, config: config}
+}
+
+type indexer struct {
+	httpApi client.HttpApi
+	chainId string
+	sink    Sink
+	config  *IndexerConfig
+}
+
+// errNodeRequest 查询节点失败，等待后重试
+type errNodeRequest struct {
+	err error
+}
+
+func (e *errNodeRequest) Error() string {
+	return e.err.Error()
+}
+
+func (e *errNodeRequest) Unwrap() error {
+	return e.err
+}
+
+func (idx *indexer) Run(ctx context.Context) error {
+	for {
+		caughtUp, err := idx.step(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			var nodeErr *errNodeRequest
+			if !errors.As(err, &nodeErr) {
+				return err
+			}
+			log.Warn().Err(err).Msgf("索引守护区块时查询节点失败，%s后重试，chainId: %s", idx.config.PollInterval, idx.chainId)
+		}
+		if err == nil && !caughtUp {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(idx.config.PollInterval):
+		}
+	}
+}
+
+// step 索引一批守护区块，已追上最新守护区块时返回true
+func (idx *indexer) step(ctx context.Context) (bool, error) {
+	checkpoint, err := idx.sink.Checkpoint(ctx)
+	if err != nil {
+		return false, fmt.Errorf("查询索引的检查点失败：%w", err)
+	}
+	next := idx.config.StartHeight
+	if checkpoint != nil {
+		next = checkpoint.Height + 1
+	}
+
+	latest, err := idx.httpApi.GetLatestDaemonBlock(ctx, idx.chainId)
+	if err != nil {
+		return false, &errNodeRequest{err}
+	}
+	if latest.Height == nil || latest.Height.Uint64() < idx.config.Confirmations {
+		return true, nil
+	}
+	target := latest.Height.Uint64() - idx.config.Confirmations
+	if next > target {
+		return true, nil
+	}
+
+	end := min(next+uint64(idx.config.BatchSize)-1, target)
+	heights := lo.RangeFrom(next, int(end-next+1))
+	blocks, err := idx.httpApi.GetDBlocksByHeights(ctx, idx.chainId, heights)
+	if err != nil {
+		return false, &errNodeRequest{err}
+	}
+	blocks = lo.Filter(blocks, func(block *types.DaemonBlock, _ int) bool { return block != nil && block.Height != nil })
+	if len(blocks) == 0 {
+		return true, nil
+	}
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].Height.Cmp(blocks[j].Height) < 0 })
+
+	for _, daemonBlock := range blocks {
+		height := daemonBlock.Height.Uint64()
+		if height != next {
+			return false, &errNodeRequest{fmt.Errorf("查询到的守护区块高度%d不连续，期望%d", height, next)}
+		}
+		if checkpoint != nil && daemonBlock.ParentHash != checkpoint.Hash {
+			log.Warn().Msgf("守护区块%d的父哈希%s与已索引的区块%s不一致，回滚分叉，chainId: %s", height, daemonBlock.ParentHash, checkpoint.Hash, idx.chainId)
+			rolledBack, err := idx.rollback(ctx, checkpoint)
+			if err != nil {
+				return false, err
+			}
+			if !rolledBack {
+				// 批量查询和单个查询的结果不一致，如分叉过程中或请求落在不同的节点上，等待后重试，避免不停地请求节点
+				return false, &errNodeRequest{fmt.Errorf("守护区块%d的父哈希%s与已索引的区块%s不一致，但已索引的区块仍在链上", height, daemonBlock.ParentHash, checkpoint.Hash)}
+			}
+			return false, nil
+		}
+
+		indexed, err := idx.fetch(ctx, daemonBlock)
+		if err != nil {
+			return false, &errNodeRequest{err}
+		}
+		if err := idx.sink.Write(ctx, indexed); err != nil {
+			return false, fmt.Errorf("写入守护区块%d失败：%w", height, err)
+		}
+		checkpoint = &Checkpoint{Height: height, Hash: daemonBlock.Hash}
+		next++
+	}
+	return next > target, nil
+}
+
+// fetch 查询守护区块锚定的交易区块和回执
+func (idx *indexer) fetch(ctx context.Context, daemonBlock *types.DaemonBlock) (*IndexedBlock, error) {
+	indexed := &IndexedBlock{
+		ChainId:      idx.chainId,
+		DaemonBlock:  daemonBlock,
+		Transactions: make([]*types.TransactionBlock, 0, len(daemonBlock.TxHashes)),
+		Receipts:     daemonBlock.Receipts,
+	}
+	if len(daemonBlock.TxHashes) == 0 {
+		return indexed, nil
+	}
+
+	hashes := lo.Map(daemonBlock.TxHashes, func(hash common.Hash, _ int) string { return hash.String() })
+	for _, hash := range hashes {
+		tblock, err := idx.httpApi.GetTransactionBlockByHash(ctx, idx.chainId, hash)
+		if err != nil {
+			return nil, fmt.Errorf("查询交易区块%s失败：%w", hash, err)
+		}
+		indexed.Transactions = append(indexed.Transactions, tblock)
+	}
+	if len(indexed.Receipts) == 0 {
+		receipts, err := idx.httpApi.GetReceipts(ctx, idx.chainId, hashes)
+		if err != nil {
+			return nil, fmt.Errorf("批量查询回执失败：%w", err)
+		}
+		indexed.Receipts = receipts
+	}
+	return indexed, nil
+}
+
+// rollback 从检查点开始逐个比较已索引的区块和节点上同高度的区块，回滚到共同祖先，检查点仍在链上没有回滚任何区块时返回false
+func (idx *indexer) rollback(ctx context.Context, checkpoint *Checkpoint) (bool, error) {
+	for depth := 0; checkpoint != nil; depth++ {
+		if depth >= idx.config.MaxReorgDepth {
+			return false, fmt.Errorf("%w：%d，chainId: %s", ErrReorgTooDeep, idx.config.MaxReorgDepth, idx.chainId)
+		}
+		daemonBlock, err := idx.httpApi.GetDaemonBlockByHeight(ctx, idx.chainId, checkpoint.Height)
+		if err != nil {
+			return false, &errNodeRequest{err}
+		}
+		if daemonBlock.Hash == checkpoint.Hash {
+			if depth > 0 {
+				log.Info().Msgf("回滚分叉完成，共同祖先的高度为%d，chainId: %s", checkpoint.Height, idx.chainId)
+			}
+			return depth > 0, nil
+		}
+		if checkpoint.Height == 0 {
+			return false, fmt.Errorf("已索引的创世守护区块%s与节点的%s不一致，chainId: %s", checkpoint.Hash, daemonBlock.Hash, idx.chainId)
+		}
+		if err := idx.sink.Rollback(ctx, checkpoint.Height-1); err != nil {
+			return false, fmt.Errorf("回滚到守护区块%d失败：%w", checkpoint.Height-1, err)
+		}
+		if checkpoint, err = idx.sink.Checkpoint(ctx); err != nil {
+			return false, fmt.Errorf("查询索引的检查点失败：%w", err)
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,195 @@
+package lattice
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// MemorySink 将索引数据保存在内存中的 Sink，适用于测试或数据量较小的场景
+type MemorySink struct {
+	mu     sync.RWMutex
+	blocks []*IndexedBlock
+}
+
+// NewMemorySink 初始化内存 Sink
+func NewMemorySink() *MemorySink {
+	return &MemorySink{}
+}
+
+func (s *MemorySink) Write(_ context.Context, block *IndexedBlock) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blocks = append(s.blocks, block)
+	return nil
+}
+
+func (s *MemorySink) Rollback(_ context.Context, height uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(s.blocks) > 0 && s.blocks[len(s.blocks)-1].Height() > height {
+		s.blocks = s.blocks[:len(s.blocks)-1]
+	}
+	return nil
+}
+
+func (s *MemorySink) Checkpoint(_ context.Context) (*Checkpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.blocks) == 0 {
+		return nil, nil
+	}
+	last := s.blocks[len(s.blocks)-1]
+	return &Checkpoint{Height: last.Height(), Hash: last.DaemonBlock.Hash}, nil
+}
+
+// Blocks 已写入的守护区块，按高度递增
+func (s *MemorySink) Blocks() []*IndexedBlock {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]*IndexedBlock(nil), s.blocks...)
+}
+
+// FileSink 将索引数据以JSON Lines格式追加写入文件的 Sink，每行为一个 IndexedBlock，
+// 打开已有的文件时扫描每行的高度和哈希，回滚时截断文件
+type FileSink struct {
+	mu      sync.Mutex
+	file    *os.File
+	entries []fileSinkEntry
+	size    int64
+}
+
+// fileSinkEntry 文件中一行的守护区块高度、哈希和起始偏移量
+type fileSinkEntry struct {
+	checkpoint Checkpoint
+	offset     int64
+}
+
+// NewFileSink 打开或创建JSON Lines文件 Sink
+//
+// Parameters:
+//   - path string: 文件路径
+//
+// Returns:
+//   - *FileSink
+//   - error
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	sink := &FileSink{file: file}
+	if err := sink.load(); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return sink, nil
+}
+
+// load 扫描文件中每行的守护区块，末尾不完整的行会被截断
+func (s *FileSink) load() error {
+	reader := bufio.NewReader(s.file)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				return s.file.Truncate(offset)
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+		var header struct {
+			DaemonBlock struct {
+				Height json.Number `json:"number"`
+				Hash   common.Hash `json:"hash"`
+			} `json:"daemonBlock"`
+		}
+		if err := json.Unmarshal(line, &header); err != nil {
+			return fmt.Errorf("解析索引文件第%d行失败：%w", len(s.entries)+1, err)
+		}
+		height, err := parseJSONHeight(header.DaemonBlock.Height)
+		if err != nil {
+			return fmt.Errorf("解析索引文件第%d行的高度失败：%w", len(s.entries)+1, err)
+		}
+		s.entries = append(s.entries, fileSinkEntry{checkpoint: Checkpoint{Height: height, Hash: header.DaemonBlock.Hash}, offset: offset})
+		offset += int64(len(line))
+	}
+	s.size = offset
+	return nil
+}
+
+func parseJSONHeight(number json.Number) (uint64, error) {
+	if number == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(number.String(), 10, 64)
+}
+
+func (s *FileSink) Write(_ context.Context, block *IndexedBlock) error {
+	line, err := json.Marshal(block)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.WriteAt(line, s.size); err != nil {
+		return err
+	}
+	s.entries = append(s.entries, fileSinkEntry{checkpoint: Checkpoint{Height: block.Height(), Hash: block.DaemonBlock.Hash}, offset: s.size})
+	s.size += int64(len(line))
+	return nil
+}
+
+func (s *FileSink) Rollback(_ context.Context, height uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.entries)
+	for n > 0 && s.entries[n-1].checkpoint.Height > height {
+		n--
+	}
+	if n == len(s.entries) {
+		return nil
+	}
+	size := s.size
+	if n < len(s.entries) {
+		size = s.entries[n].offset
+	}
+	if err := s.file.Truncate(size); err != nil {
+		return err
+	}
+	s.entries = s.entries[:n]
+	s.size = size
+	return nil
+}
+
+func (s *FileSink) Checkpoint(_ context.Context) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.entries) == 0 {
+		return nil, nil
+	}
+	checkpoint := s.entries[len(s.entries)-1].checkpoint
+	return &checkpoint, nil
+}
+
+// Sync 将写入的数据刷新到磁盘
+func (s *FileSink) Sync() error {
+	return s.file.Sync()
+}
+
+// Close 关闭文件
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
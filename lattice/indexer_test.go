@@ -0,0 +1,221 @@
+package lattice
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockDaemonChain 模拟节点的守护区块链，fork用于区分分叉后的区块哈希
+type mockDaemonChain struct {
+	mu     sync.Mutex
+	blocks []*types.DaemonBlock
+}
+
+func (c *mockDaemonChain) extend(n int, fork byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := 0; i < n; i++ {
+		height := len(c.blocks)
+		block := &types.DaemonBlock{
+			Height: big.NewInt(int64(height)),
+			Hash:   common.BytesToHash([]byte{fork, byte(height)}),
+		}
+		if height > 0 {
+			block.ParentHash = c.blocks[height-1].Hash
+			block.TxHashes = []common.Hash{common.BytesToHash([]byte{fork, byte(height), 0x01})}
+		}
+		c.blocks = append(c.blocks, block)
+	}
+}
+
+// reorg 从height开始替换为分叉的区块
+func (c *mockDaemonChain) reorg(height, n int, fork byte) {
+	c.mu.Lock()
+	c.blocks = c.blocks[:height]
+	c.mu.Unlock()
+	c.extend(n, fork)
+}
+
+func (c *mockDaemonChain) get(height uint64) *types.DaemonBlock {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if height >= uint64(len(c.blocks)) {
+		return nil
+	}
+	return c.blocks[height]
+}
+
+func (c *mockDaemonChain) serve(node *mockNode) {
+	node.handle("latc_getCurrentDBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.blocks[len(c.blocks)-1], nil
+	})
+	node.handle("latc_getDBlockByNumberRange", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		var heights []uint64
+		_ = json.Unmarshal(params[0], &heights)
+		blocks := make([]*types.DaemonBlock, 0, len(heights))
+		for _, height := range heights {
+			if block := c.get(height); block != nil {
+				blocks = append(blocks, block)
+			}
+		}
+		return blocks, nil
+	})
+	node.handle("latc_getDBlockByNumber", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		var height uint64
+		_ = json.Unmarshal(params[0], &height)
+		return c.get(height), nil
+	})
+	node.handle("latc_getTBlockByHash", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		var hash common.Hash
+		_ = json.Unmarshal(params[0], &hash)
+		return &types.TransactionBlock{Hash: hash, Height: big.NewInt(1)}, nil
+	})
+	node.handle("latc_getTBlockReceipts", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		var hashes []common.Hash
+		_ = json.Unmarshal(params[0], &hashes)
+		receipts := make([]*types.Receipt, len(hashes))
+		for i, hash := range hashes {
+			receipts[i] = &types.Receipt{TBlockHash: hash, Success: true}
+		}
+		return receipts, nil
+	})
+}
+
+// runIndexerUntil 运行索引器直到 Sink 的检查点与节点的最新守护区块一致
+func runIndexerUntil(t *testing.T, indexer Indexer, sink Sink, chain *mockDaemonChain, height uint64) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- indexer.Run(ctx) }()
+
+	expected := chain.get(height).Hash
+	require.Eventually(t, func() bool {
+		checkpoint, err := sink.Checkpoint(context.Background())
+		return err == nil && checkpoint != nil && checkpoint.Height == height && checkpoint.Hash == expected
+	}, 5*time.Second, 10*time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestIndexer(t *testing.T) {
+	config := &IndexerConfig{BatchSize: 3, PollInterval: 10 * time.Millisecond}
+
+	t.Run("follow chain and rollback reorg", func(t *testing.T) {
+		node := newMockNode(t)
+		chain := &mockDaemonChain{}
+		chain.extend(6, 0x00)
+		chain.serve(node)
+		svc := newMockLattice(t, node, nil)
+
+		sink := NewMemorySink()
+		indexer := NewIndexer(svc.HttpApi(), chainId, sink, config)
+		runIndexerUntil(t, indexer, sink, chain, 5)
+		blocks := sink.Blocks()
+		require.Len(t, blocks, 6)
+		assert.Empty(t, blocks[0].Transactions)
+		require.Len(t, blocks[3].Transactions, 1)
+		assert.Equal(t, blocks[3].DaemonBlock.TxHashes[0], blocks[3].Transactions[0].Hash)
+		require.Len(t, blocks[3].Receipts, 1)
+		assert.Equal(t, blocks[3].DaemonBlock.TxHashes[0], blocks[3].Receipts[0].TBlockHash)
+
+		// 高度4和5分叉，并产生新的区块
+		chain.reorg(4, 4, 0x01)
+		runIndexerUntil(t, NewIndexer(svc.HttpApi(), chainId, sink, config), sink, chain, 7)
+		blocks = sink.Blocks()
+		require.Len(t, blocks, 8)
+		for i, block := range blocks {
+			assert.Equal(t, chain.get(uint64(i)).Hash, block.DaemonBlock.Hash)
+		}
+	})
+
+	t.Run("reorg too deep", func(t *testing.T) {
+		node := newMockNode(t)
+		chain := &mockDaemonChain{}
+		chain.extend(6, 0x00)
+		chain.serve(node)
+		svc := newMockLattice(t, node, nil)
+
+		sink := NewMemorySink()
+		runIndexerUntil(t, NewIndexer(svc.HttpApi(), chainId, sink, config), sink, chain, 5)
+		chain.reorg(1, 7, 0x01)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		err := NewIndexer(svc.HttpApi(), chainId, sink, &IndexerConfig{PollInterval: 10 * time.Millisecond, MaxReorgDepth: 2}).Run(ctx)
+		assert.ErrorIs(t, err, ErrReorgTooDeep)
+	})
+
+	t.Run("parent mismatch with canonical checkpoint waits", func(t *testing.T) {
+		node := newMockNode(t)
+		chain := &mockDaemonChain{}
+		chain.extend(4, 0x00)
+		chain.serve(node)
+		svc := newMockLattice(t, node, nil)
+
+		sink := NewMemorySink()
+		runIndexerUntil(t, NewIndexer(svc.HttpApi(), chainId, sink, config), sink, chain, 3)
+
+		// 节点的最新高度为4，但批量查询返回父哈希与检查点不一致的区块，单个查询的检查点仍在链上
+		chain.extend(1, 0x00)
+		node.handle("latc_getDBlockByNumberRange", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+			return []*types.DaemonBlock{{Height: big.NewInt(4), Hash: common.HexToHash("0x0f04"), ParentHash: common.HexToHash("0x0f03")}}, nil
+		})
+		rangeCalls := node.callCount("latc_getDBlockByNumberRange")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		err := NewIndexer(svc.HttpApi(), chainId, sink, &IndexerConfig{PollInterval: 50 * time.Millisecond}).Run(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.LessOrEqual(t, node.callCount("latc_getDBlockByNumberRange")-rangeCalls, 5)
+
+		checkpoint, err := sink.Checkpoint(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), checkpoint.Height)
+		assert.Equal(t, chain.get(3).Hash, checkpoint.Hash)
+	})
+
+	t.Run("file sink resume", func(t *testing.T) {
+		node := newMockNode(t)
+		chain := &mockDaemonChain{}
+		chain.extend(5, 0x00)
+		chain.serve(node)
+		svc := newMockLattice(t, node, nil)
+
+		path := filepath.Join(t.TempDir(), "index.jsonl")
+		sink, err := NewFileSink(path)
+		require.NoError(t, err)
+		runIndexerUntil(t, NewIndexer(svc.HttpApi(), chainId, sink, config), sink, chain, 4)
+		require.NoError(t, sink.Close())
+
+		// 重启后从文件中的检查点继续索引，并回滚分叉的区块
+		chain.reorg(3, 3, 0x01)
+		sink, err = NewFileSink(path)
+		require.NoError(t, err)
+		checkpoint, err := sink.Checkpoint(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, uint64(4), checkpoint.Height)
+
+		runIndexerUntil(t, NewIndexer(svc.HttpApi(), chainId, sink, config), sink, chain, 5)
+		require.NoError(t, sink.Close())
+
+		sink, err = NewFileSink(path)
+		require.NoError(t, err)
+		defer sink.Close()
+		assert.Len(t, sink.entries, 6)
+		for i, entry := range sink.entries {
+			assert.Equal(t, chain.get(uint64(i)).Hash, entry.checkpoint.Hash)
+		}
+	})
+}
@@ -5,20 +5,24 @@ import (
 	"crypto/tls"
 	"errors"
 	"fmt"
+	"math/big"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/LatticeBCLab/go-lattice/abi"
 	"github.com/LatticeBCLab/go-lattice/common/constant"
+	"github.com/LatticeBCLab/go-lattice/common/convert"
 	"github.com/LatticeBCLab/go-lattice/common/types"
 	"github.com/LatticeBCLab/go-lattice/crypto"
 	"github.com/LatticeBCLab/go-lattice/lattice/block"
 	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/LatticeBCLab/go-lattice/lattice/signer"
 	"github.com/LatticeBCLab/go-lattice/wallet"
 	"github.com/avast/retry-go"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
 )
@@ -35,8 +39,8 @@ const (
 // Parameters:
 //   - chainConfig *ChainConfig: 链配置信息
 //   - connectingNodeConfig *ConnectingNodeConfig: 节点的连接信息
-//   - blockCache BlockCache: 区块缓存接口，通过缓存支持账户高并发发交易，为nil时，禁用缓存，或着使用内置的 lattice.NewMemoryBlockCache(10*time.Second, time.Minute, time.Minute)
-//   - accountLock AccountLock: 账户锁接口，通过账户锁支持账户高并发发交易，为nil时，默认使用 lattice.NewAccountLock()
+//   - blockCache BlockCache: 区块缓存接口，通过缓存支持账户高并发发交易，为nil时，禁用缓存，或着使用内置的 lattice.NewMemoryBlockCache(10*time.Second, time.Minute, time.Minute)，多副本共享账户时使用 lattice.NewRedisBlockCache
+//   - accountLock AccountLock: 账户锁接口，通过账户锁支持账户高并发发交易，为nil时，默认使用 lattice.NewAccountLock()，多副本共享账户时使用 lattice.NewRedisAccountLock
 //   - options *Options:
 //
 // Returns:
@@ -49,16 +53,47 @@ func NewLattice(chainConfig *ChainConfig, connectingNodeConfig *ConnectingNodeCo
 		panic(err)
 	}
 
-	initHttpClientArgs := &client.HttpApiInitParam{
-		NodeAddress:                fmt.Sprintf("%s:%d", connectingNodeConfig.Ip, connectingNodeConfig.HttpPort),
-		HttpUrl:                    connectingNodeConfig.GetHttpUrl(),
-		GinServerUrl:               connectingNodeConfig.GetGinServerUrl(),
-		Transport:                  options.GetTransport(),
-		JwtSecret:                  connectingNodeConfig.JwtSecret,
-		JwtTokenExpirationDuration: connectingNodeConfig.JwtTokenExpirationDuration,
+	httpApi := client.NewHttpApi(connectingNodeConfig.httpApiInitParam(options))
+	return newLattice(chainConfig, connectingNodeConfig, httpApi, blockCache, accountLock, options)
+}
+
+// NewMultiNodeLattice 初始化连接多个节点的LatticeApi，定期对节点做健康检查，节点故障时自动转移到其他节点
+//
+// Parameters:
+//   - chainConfig *ChainConfig: 链配置信息
+//   - connectingNodeConfigs []*ConnectingNodeConfig: 多个节点的连接信息，第一个节点为首选节点，websocket连接到第一个节点
+//   - blockCache BlockCache: 区块缓存接口，同 NewLattice
+//   - accountLock AccountLock: 账户锁接口，同 NewLattice
+//   - options *Options: 通过 Options.LoadBalancePolicy、Options.HealthCheckInterval、Options.HealthCheckChainId、Options.MaxDaemonBlockLag 配置负载均衡和健康检查
+//
+// Returns:
+//   - Lattice: 不再使用时调用 Lattice.Close 停止健康检查
+func NewMultiNodeLattice(chainConfig *ChainConfig, connectingNodeConfigs []*ConnectingNodeConfig, blockCache BlockCache, accountLock AccountLock, options *Options) Lattice {
+	if err := chainConfig.validate(); err != nil {
+		panic(err)
+	}
+	if len(connectingNodeConfigs) == 0 {
+		panic(errors.New("节点的连接信息不能为空"))
+	}
+	nodes := make([]*client.HttpApiInitParam, 0, len(connectingNodeConfigs))
+	for _, connectingNodeConfig := range connectingNodeConfigs {
+		if err := connectingNodeConfig.validate(); err != nil {
+			panic(err)
+		}
+		nodes = append(nodes, connectingNodeConfig.httpApiInitParam(options))
 	}
-	httpApi := client.NewHttpApi(initHttpClientArgs)
 
+	httpApi := client.NewMultiNodeHttpApi(&client.MultiNodeHttpApiInitParam{
+		Nodes:               nodes,
+		Policy:              options.LoadBalancePolicy,
+		HealthCheckInterval: options.HealthCheckInterval,
+		HealthCheckChainId:  options.HealthCheckChainId,
+		MaxDaemonBlockLag:   options.MaxDaemonBlockLag,
+	})
+	return newLattice(chainConfig, connectingNodeConfigs[0], httpApi, blockCache, accountLock, options)
+}
+
+func newLattice(chainConfig *ChainConfig, connectingNodeConfig *ConnectingNodeConfig, httpApi client.HttpApi, blockCache BlockCache, accountLock AccountLock, options *Options) Lattice {
 	initWebsocketClientArgs := &client.WebSocketApiInitParam{
 		WebSocketUrl: connectingNodeConfig.GetWebsocketUrl(),
 	}
@@ -74,6 +109,11 @@ func NewLattice(chainConfig *ChainConfig, connectingNodeConfig *ConnectingNodeCo
 		accountLock = NewAccountLock()
 	}
 
+	var receiptWatcher ReceiptWatcher
+	if options.ReceiptWatcher != nil {
+		receiptWatcher = NewReceiptWatcher(httpApi, webSocketApi, options.ReceiptWatcher)
+	}
+
 	return &lattice{
 		chainConfig:          chainConfig,
 		connectingNodeConfig: connectingNodeConfig,
@@ -82,6 +122,7 @@ func NewLattice(chainConfig *ChainConfig, connectingNodeConfig *ConnectingNodeCo
 		websocketApi:         webSocketApi,
 		blockCache:           blockCache,
 		accountLock:          accountLock,
+		receiptWatcher:       receiptWatcher,
 	}
 }
 
@@ -92,6 +133,8 @@ type lattice struct {
 	connectingNodeConfig *ConnectingNodeConfig // 节点的连接信息配置
 	blockCache           BlockCache            // 区块缓存接口
 	accountLock          AccountLock           // 账户锁接口
+	receiptWatcher       ReceiptWatcher        // 回执监听器，为nil时轮询回执
+	contractAbis         sync.Map              // 合约地址 -> abi.LatticeAbi，用于解码合约执行失败的原因
 	options              *Options              // 可选配置
 }
 
@@ -133,10 +176,11 @@ func (node *ConnectingNodeConfig) validate() error {
 
 // Credentials 凭证配置
 type Credentials struct {
-	AccountAddress string // 账户地址
-	Passphrase     string // 身份密码
-	FileKey        string // FileKey 的json字符串
-	PrivateKey     string // 私钥
+	AccountAddress string        // 账户地址
+	Passphrase     string        // 身份密码
+	FileKey        string        // FileKey 的json字符串
+	PrivateKey     string        // 私钥
+	Signer         signer.Signer // 签名者，不为空时忽略 PrivateKey 和 FileKey，私钥可以保存在硬件加密机或远程签名服务中
 }
 
 type Options struct {
@@ -152,6 +196,53 @@ type Options struct {
 	// (keep-alive) connections to keep per-host.
 	// If zero, DefaultMaxIdleConnsPerHost(2) is used.
 	MaxIdleConnsPerHost int
+
+	// ResendOnBlockMismatch 交易因父哈希或高度与链上不一致被拒绝时，是否在重新同步区块后重新签名并发送一次
+	ResendOnBlockMismatch bool
+
+	// LoadBalancePolicy 连接多个节点时读请求的负载均衡策略，默认为 client.RoundRobin
+	LoadBalancePolicy client.LoadBalancePolicy
+
+	// HealthCheckInterval 连接多个节点时健康检查的间隔，默认为5秒
+	HealthCheckInterval time.Duration
+
+	// HealthCheckChainId 连接多个节点时，健康检查查询节点守护区块高度使用的链ID，默认为1
+	HealthCheckChainId string
+
+	// MaxDaemonBlockLag 连接多个节点时，节点的守护区块高度落后于最高节点的最大允许值，默认为10
+	MaxDaemonBlockLag uint64
+
+	// RequestRetryStrategy 只读请求遇到连接失败、超时或节点返回5xx时的重试策略，为nil时默认重试3次，发交易等非幂等请求不会重试
+	RequestRetryStrategy *RetryStrategy
+
+	// DisableRequestRetry 禁用只读请求的重试
+	DisableRequestRetry bool
+
+	// CircuitBreaker 按节点熔断的配置，为nil时不启用熔断
+	CircuitBreaker *client.CircuitBreakerConfig
+
+	// MethodTimeouts 按JsonRpc方法名配置单次请求的超时时长，如 {"latc_getReceipt": 3 * time.Second}
+	MethodTimeouts map[string]time.Duration
+
+	// Middlewares 自定义的JsonRpc请求中间件，位于内置的重试、熔断和超时中间件的外层
+	Middlewares []client.Middleware
+
+	// ReceiptWatcher 回执监听器的配置，不为nil时 *WaitReceipt 通过订阅守护区块批量查询回执，忽略传入的 RetryStrategy
+	ReceiptWatcher *ReceiptWatcherConfig
+
+	// JouleSafetyMargin 估算交易手续费时在预执行消耗的基础上增加的比例，默认为0.2，即增加20%
+	JouleSafetyMargin float64
+
+	// EnableProofOfWork 链开启了交易的工作量证明时，签名交易和构造离线签名的交易之前以节点最新守护区块的难度计算工作量证明，
+	// 每次提交或批量提交只查询一次难度
+	EnableProofOfWork bool
+
+	// ProofOfWorkWorkers 并行计算工作量证明的goroutine数量，默认为CPU核数
+	ProofOfWorkWorkers int
+
+	// ReturnRevertError 回执为失败时，*WaitReceipt 和 PreCallContract 是否同时返回回执和 *abi.ContractRevertError，
+	// 默认为false，只返回回执，兼容回执为失败时error为nil的行为
+	ReturnRevertError bool
 }
 
 func (options *Options) GetTransport() *http.Transport {
@@ -165,6 +256,51 @@ func (options *Options) GetTransport() *http.Transport {
 	return options.Transport
 }
 
+// GetMiddlewares 获取JsonRpc请求的中间件，由外到内依次为自定义中间件、重试、熔断和超时
+func (options *Options) GetMiddlewares() []client.Middleware {
+	middlewares := append([]client.Middleware{}, options.Middlewares...)
+	if !options.DisableRequestRetry {
+		strategy := options.RequestRetryStrategy
+		if strategy == nil {
+			strategy = NewBackOffRetryStrategy(3, 100*time.Millisecond)
+		}
+		middlewares = append(middlewares, client.RetryMiddleware(client.IsIdempotentMethod, strategy.GetRetryStrategyOpts()...))
+	}
+	if options.CircuitBreaker != nil {
+		middlewares = append(middlewares, client.CircuitBreakerMiddleware(options.CircuitBreaker))
+	}
+	if len(options.MethodTimeouts) != 0 {
+		middlewares = append(middlewares, client.TimeoutMiddleware(0, options.MethodTimeouts))
+	}
+	return middlewares
+}
+
+// GetSigner 获取交易的签名者，未设置 Signer 时每次调用都使用 PrivateKey 或 FileKey 初始化新的签名者，
+// 不写回 Credentials，同一个 Credentials 可以在多个goroutine中并发使用
+//
+// Parameters:
+//   - curve types.Curve: 使用 PrivateKey 初始化签名者时的曲线
+//
+// Returns:
+//   - signer.Signer
+//   - error
+func (credentials *Credentials) GetSigner(curve types.Curve) (signer.Signer, error) {
+	if credentials.Signer != nil {
+		return credentials.Signer, nil
+	}
+	var s signer.Signer
+	var err error
+	if credentials.PrivateKey != "" {
+		s, err = signer.NewPrivateKeySigner(curve, credentials.PrivateKey)
+	} else {
+		s, err = signer.NewFileKeySigner(credentials.FileKey, credentials.Passphrase)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
 // GetSK 获取私钥的Hex字符串
 //
 // Returns:
@@ -189,6 +325,19 @@ func (credentials *Credentials) GetSK() (string, error) {
 	return credentials.PrivateKey, nil
 }
 
+// httpApiInitParam 节点的HTTP API初始化参数
+func (node *ConnectingNodeConfig) httpApiInitParam(options *Options) *client.HttpApiInitParam {
+	return &client.HttpApiInitParam{
+		NodeAddress:                fmt.Sprintf("%s:%d", node.Ip, node.HttpPort),
+		HttpUrl:                    node.GetHttpUrl(),
+		GinServerUrl:               node.GetGinServerUrl(),
+		Transport:                  options.GetTransport(),
+		JwtSecret:                  node.JwtSecret,
+		JwtTokenExpirationDuration: node.JwtTokenExpirationDuration,
+		Middlewares:                options.GetMiddlewares(),
+	}
+}
+
 func (node *ConnectingNodeConfig) GetHttpUrl() string {
 	return fmt.Sprintf("%s://%s:%d", lo.Ternary(node.Insecure, httpsProtocol, httpProtocol), node.Ip, node.HttpPort)
 }
@@ -327,6 +476,46 @@ type Lattice interface {
 	//   - client.WebsocketApi
 	WebsocketApi() client.WebSocketApi
 
+	// Close 释放 Lattice 持有的后台资源，通过 NewMultiNodeLattice 初始化时停止节点的健康检查
+	Close()
+
+	// Submit 提交任意类型的交易，Transfer、DeployContract、CallGoContract 等方法都基于 Submit 实现
+	//
+	// Parameters:
+	//   - ctx context.Context
+	//   - credentials *Credentials: 发交易的身份凭证
+	//   - chainId string
+	//   - req *TxRequest: 交易请求
+	//   - opts ...SubmitOption: WithWaitReceipt、WithRetryStrategy、WithPreExecute、WithDryRun
+	//
+	// Returns:
+	//   - *SubmitResult: 等待回执失败时仍返回包含交易哈希的结果
+	//   - error
+	Submit(ctx context.Context, credentials *Credentials, chainId string, req *TxRequest, opts ...SubmitOption) (*SubmitResult, error)
+
+	// SubmitBatch 为同一账户构造高度连续的一组交易，签名后通过一次 wallet_sendRawBatchTBlock 请求发送，并一次性更新区块缓存
+	//
+	// Parameters:
+	//   - ctx context.Context
+	//   - credentials *Credentials: 发交易的身份凭证
+	//   - chainId string
+	//   - reqs []*TxRequest: 交易请求，按顺序衔接，Joule 为 AutoJoule 的交易在加锁前估算手续费，
+	//     有通证链对所有交易的转账金额和手续费之和检查余额，预执行不包含前序交易的执行结果
+	//
+	// Returns:
+	//   - []*BatchTxResult: 每笔交易的结果，与 reqs 一一对应
+	//   - error: 部分交易失败时为 ErrBatchPartiallyFailed，此时仍返回每笔交易的结果
+	SubmitBatch(ctx context.Context, credentials *Credentials, chainId string, reqs []*TxRequest) ([]*BatchTxResult, error)
+
+	// NewTxQueue 初始化异步交易队列，交易按账户顺序发送，不同账户之间并发发送
+	//
+	// Parameters:
+	//   - config *TxQueueConfig: 为nil时使用默认配置
+	//
+	// Returns:
+	//   - TxQueue
+	NewTxQueue(config *TxQueueConfig) TxQueue
+
 	// Transfer 发起转账交易
 	//
 	// Parameters:
@@ -370,7 +559,119 @@ type Lattice interface {
 	//   - error
 	CallContract(ctx context.Context, credentials *Credentials, chainId, contractAddress, data, payload string, amount, joule uint64) (*common.Hash, error)
 	UnsafeCallContract(ctx context.Context, credentials *Credentials, chainId, contractAddress, data, payload string, amount, joule uint64) (*common.Hash, error)
+	// WaitReceipt 等待交易回执，所有 *WaitReceipt 方法相同：回执为失败时默认只返回回执，error为nil，
+	// 开启 Options.ReturnRevertError 时同时返回回执和 *abi.ContractRevertError，也可以通过 RevertError 解码失败原因
+	//
+	// Parameters:
+	//   - ctx context.Context
+	//   - chainId string
+	//   - hash *common.Hash: 交易哈希
+	//   - retryStrategy *RetryStrategy: 等待回执策略
+	//
+	// Returns:
+	//   - *common.Hash: 交易哈希
+	//   - *types.Receipt: 回执
+	//   - error
 	WaitReceipt(ctx context.Context, chainId string, hash *common.Hash, retryStrategy *RetryStrategy) (*common.Hash, *types.Receipt, error)
+
+	// DeployBoundContract 使用ABI编码构造函数的参数并部署合约，开启合约生命周期时等待合约的部署提案投票通过
+	//
+	// Parameters:
+	//   - ctx context.Context
+	//   - credentials *Credentials
+	//   - chainId string
+	//   - req *DeployContractRequest
+	//
+	// Returns:
+	//   - *BoundContract: 绑定到新合约地址的合约，等待提案失败时同时返回合约和错误
+	//   - error: 部署失败时为 *abi.ContractRevertError，部署提案未通过时为 ErrContractProposalNotPassed
+	DeployBoundContract(ctx context.Context, credentials *Credentials, chainId string, req *DeployContractRequest) (*BoundContract, error)
+
+	// BindContract 绑定已部署的合约，并注册合约的ABI
+	//
+	// Parameters:
+	//   - contractAddress string: 合约地址
+	//   - contractAbi abi.LatticeAbi
+	//
+	// Returns:
+	//   - *BoundContract
+	BindContract(contractAddress string, contractAbi abi.LatticeAbi) *BoundContract
+
+	// EstimateJoule 预执行交易估算手续费，在回执的 JouleUsed 基础上增加 Options.JouleSafetyMargin 的余量，
+	// 有通证链会同时检查账户的余额是否足够支付转账金额和手续费，交易的 Joule 为 AutoJoule 时 Submit 等方法自动估算手续费
+	//
+	// Parameters:
+	//   - ctx context.Context
+	//   - chainId string
+	//   - owner string: 发交易的账户地址
+	//   - req *TxRequest: 没有合约数据的交易不预执行，只检查余额
+	//
+	// Returns:
+	//   - uint64: 估算的手续费
+	//   - error: 预执行失败时为 ErrPreExecuteFailed，余额不足时为 ErrInsufficientBalance
+	EstimateJoule(ctx context.Context, chainId, owner string, req *TxRequest) (uint64, error)
+
+	// VerifyDBlockProof 查询守护区块的见证证明，校验证明的区块哈希与期望的哈希一致，
+	// 并以调用方提供的可信共识节点集合和共识类型校验见证签名，不使用提供证明的节点返回的共识节点集合
+	//
+	// Parameters:
+	//   - ctx context.Context
+	//   - chainId string
+	//   - height *big.Int: 守护区块高度
+	//   - expectedHash common.Hash: 期望被见证的区块哈希，为空时查询该高度的守护区块的哈希
+	//   - trusted *TrustedWitnesses: 可信的共识节点集合和共识类型
+	//
+	// Returns:
+	//   - *block.WitnessProofVerdict
+	//   - error: 有效签名未达到投票门限时为 block.ErrWitnessQuorumNotReached，同时返回校验结果，
+	//     证明的区块哈希与期望的哈希不一致时为 ErrWitnessProofHashMismatch
+	VerifyDBlockProof(ctx context.Context, chainId string, height *big.Int, expectedHash common.Hash, trusted *TrustedWitnesses) (*block.WitnessProofVerdict, error)
+
+	// VerifyTBlockProof 查询交易区块的见证证明并校验见证签名，同 VerifyDBlockProof
+	//
+	// Parameters:
+	//   - ctx context.Context
+	//   - chainId string
+	//   - accountAddress string: 账户地址
+	//   - height *big.Int: 交易区块高度
+	//   - expectedHash common.Hash: 期望被见证的区块哈希，为空时查询账户在该高度的交易区块的哈希
+	//   - trusted *TrustedWitnesses: 可信的共识节点集合和共识类型
+	//
+	// Returns:
+	//   - *block.WitnessProofVerdict
+	//   - error
+	VerifyTBlockProof(ctx context.Context, chainId, accountAddress string, height *big.Int, expectedHash common.Hash, trusted *TrustedWitnesses) (*block.WitnessProofVerdict, error)
+
+	// AccountHistory 从指定高度开始按高度递增遍历账户的交易区块和回执，遍历到第一次调用 Next 时账户的最新高度为止，
+	// 每批通过 GetTBlocksByHeights 预取，区块已被压缩时自动改为查询压缩的区块和回执
+	//
+	// Parameters:
+	//   - ctx context.Context
+	//   - chainId string
+	//   - address string: 账户地址
+	//   - fromHeight uint64: 起始高度，为0时从1开始
+	//
+	// Returns:
+	//   - *AccountHistoryIterator
+	AccountHistory(ctx context.Context, chainId, address string, fromHeight uint64) *AccountHistoryIterator
+
+	// RegisterContractAbi 注册合约的ABI，回执为失败时使用ABI解码合约的自定义错误
+	//
+	// Parameters:
+	//   - contractAddress string: 合约地址
+	//   - contractAbi abi.LatticeAbi
+	RegisterContractAbi(contractAddress string, contractAbi abi.LatticeAbi)
+
+	// RevertError 回执为失败时解码 ContractRet，使用合约地址注册的ABI解码合约的自定义错误
+	//
+	// Parameters:
+	//   - contractAddress string: 被调用的合约地址，为空时使用回执中的合约地址查找已注册的ABI
+	//   - receipt *types.Receipt
+	//
+	// Returns:
+	//   - error: 回执为失败时为 *abi.ContractRevertError，回执为成功或nil时为nil
+	RevertError(contractAddress string, receipt *types.Receipt) error
+
 	// TransferWaitReceipt 发起转账交易并等待回执
 	//
 	// Parameters:
@@ -432,7 +733,7 @@ type Lattice interface {
 	//
 	// Returns:
 	//   - *types.Receipt: 交易回执
-	//   - error: 预执行的错误
+	//   - error: 预执行的错误，开启 Options.ReturnRevertError 且回执为失败时为 *abi.ContractRevertError，同时返回回执
 	PreCallContract(ctx context.Context, chainId, owner, contractAddress, data, payload string) (*types.Receipt, error)
 
 	// UpgradeContract 发起升级合约交易
@@ -514,6 +815,38 @@ type Lattice interface {
 	NewCallContractTx(ctx context.Context, credentials *Credentials, chainId, contractAddress, data, payload string, amount, joule uint64) (unsignedTx *block.Transaction, unsignedHash common.Hash, err error)
 	// NewDeployContractTx construct a deployment contract tx
 	NewDeployContractTx(ctx context.Context, credentials *Credentials, chainId, data, payload string, amount, joule uint64) (unsignedTx *block.Transaction, unsignedHash common.Hash, err error)
+	// NewTransferTx construct a transfer tx
+	NewTransferTx(ctx context.Context, credentials *Credentials, chainId, linker, payload string, amount, joule uint64) (unsignedTx *block.Transaction, unsignedHash common.Hash, err error)
+	// NewUpgradeContractTx construct an upgrade contract tx
+	NewUpgradeContractTx(ctx context.Context, credentials *Credentials, chainId, contractAddress, data, payload string, amount, joule uint64) (unsignedTx *block.Transaction, unsignedHash common.Hash, err error)
+	// NewDeployMultilingualContractTx construct a deployment go or java contract tx
+	NewDeployMultilingualContractTx(ctx context.Context, credentials *Credentials, chainId string, lang types.ContractLang, data types.DeployMultilingualContractCode, payload string, amount, joule uint64) (unsignedTx *block.Transaction, unsignedHash common.Hash, err error)
+	// NewUpgradeMultilingualContractTx construct an upgrade go or java contract tx
+	NewUpgradeMultilingualContractTx(ctx context.Context, credentials *Credentials, chainId, contractAddress string, lang types.ContractLang, data types.UpgradeMultilingualContractCode, payload string, amount, joule uint64) (unsignedTx *block.Transaction, unsignedHash common.Hash, err error)
+	// NewCallMultilingualContractTx construct a call go or java contract tx
+	NewCallMultilingualContractTx(ctx context.Context, credentials *Credentials, chainId, contractAddress string, lang types.ContractLang, data types.CallMultilingualContractCode, payload string, amount, joule uint64) (unsignedTx *block.Transaction, unsignedHash common.Hash, err error)
+
+	// NewTransactionEnvelope 将 New*Tx 构造的未签名交易装入交易信封，信封可编码为JSON或RLP后交给离线环境签名
+	//
+	// Parameters:
+	//   - chainId string
+	//   - unsignedTx *block.Transaction: 未签名的交易
+	//
+	// Returns:
+	//   - *block.TransactionEnvelope
+	//   - error
+	NewTransactionEnvelope(chainId string, unsignedTx *block.Transaction) (*block.TransactionEnvelope, error)
+
+	// SendOfflineSignedTransaction 校验离线签名的交易信封后发送交易，签名者必须为交易的owner
+	//
+	// Parameters:
+	//   - ctx context.Context
+	//   - envelope *block.TransactionEnvelope: 已通过 block.TransactionEnvelope.Sign 签名的交易信封
+	//
+	// Returns:
+	//   - *common.Hash: 交易哈希
+	//   - error
+	SendOfflineSignedTransaction(ctx context.Context, envelope *block.TransactionEnvelope) (*common.Hash, error)
 }
 
 func (svc *lattice) HttpApi() client.HttpApi {
@@ -524,10 +857,90 @@ func (svc *lattice) WebsocketApi() client.WebSocketApi {
 	return svc.websocketApi
 }
 
+func (svc *lattice) Close() {
+	if multiNodeApi, ok := svc.httpApi.(client.MultiNodeHttpApi); ok {
+		multiNodeApi.Close()
+	}
+}
+
+// obtainAccountLock 获取账户锁，ctx取消或超时时放弃等待
+func (svc *lattice) obtainAccountLock(ctx context.Context, chainId, address string) error {
+	if err := svc.accountLock.ObtainContext(ctx, chainId, address); err != nil {
+		log.Error().Err(err).Msgf("获取账户锁失败，chainId: %s, accountAddress: %s", chainId, address)
+		return fmt.Errorf("获取账户锁失败：%w", err)
+	}
+	return nil
+}
+
+// checkAccountLockLease 账户锁支持栅栏令牌时，发送交易前确认当前仍持有账户锁且租约未过期，
+// 避免租约过期后其它副本已获取锁时发送高度重复的交易
+func (svc *lattice) checkAccountLockLease(chainId, address string) error {
+	fencingLock, ok := svc.accountLock.(FencingAccountLock)
+	if !ok {
+		return nil
+	}
+	if _, held := fencingLock.FencingToken(chainId, address); !held {
+		log.Error().Msgf("账户锁的租约已过期，chainId: %s, accountAddress: %s", chainId, address)
+		return fmt.Errorf("%w，chainId: %s, accountAddress: %s", ErrAccountLockLeaseExpired, chainId, address)
+	}
+	return nil
+}
+
+// signTransaction 使用凭证的签名者签名交易，签名者的曲线必须与链一致，地址必须为交易的owner，
+// 开启工作量证明时在签名之前以 powDifficulty 查询的难度计算工作量证明
+func (svc *lattice) signTransaction(ctx context.Context, credentials *Credentials, chainId uint64, transaction *block.Transaction, difficulty uint64) error {
+	s, err := credentials.GetSigner(svc.chainConfig.Curve)
+	if err != nil {
+		return err
+	}
+	if s.Curve() != svc.chainConfig.Curve {
+		return fmt.Errorf("签名者的曲线%s与链配置的曲线%s不一致", s.Curve(), svc.chainConfig.Curve)
+	}
+	if s.Address() != transaction.GetOwnerAddress() {
+		return fmt.Errorf("签名者的地址%s与交易的owner%s不一致", convert.AddressToZltc(s.Address()), transaction.Owner)
+	}
+	if err := svc.solveProofOfWork(ctx, chainId, transaction, difficulty); err != nil {
+		return err
+	}
+	return transaction.SignTXWithSigner(chainId, s)
+}
+
+// powDifficulty 未开启工作量证明时返回0，否则查询节点最新守护区块的难度，
+// 在获取账户锁之前查询，同一次提交的签名和重新签名使用同一个难度
+func (svc *lattice) powDifficulty(ctx context.Context, chainId string) (uint64, error) {
+	if !svc.options.EnableProofOfWork {
+		return 0, nil
+	}
+	daemonBlock, err := svc.httpApi.GetLatestDaemonBlock(ctx, chainId)
+	if err != nil {
+		return 0, fmt.Errorf("查询工作量证明的难度失败：%w", err)
+	}
+	if daemonBlock.Difficulty == nil {
+		return 0, nil
+	}
+	if !daemonBlock.Difficulty.IsUint64() {
+		return 0, fmt.Errorf("工作量证明的难度%s超出范围", daemonBlock.Difficulty)
+	}
+	return daemonBlock.Difficulty.Uint64(), nil
+}
+
+// solveProofOfWork 未开启工作量证明时不计算，否则以 powDifficulty 查询的难度计算交易的工作量证明
+func (svc *lattice) solveProofOfWork(ctx context.Context, chainId uint64, transaction *block.Transaction, difficulty uint64) error {
+	if !svc.options.EnableProofOfWork {
+		return nil
+	}
+	start := time.Now()
+	if err := transaction.SolvePoW(ctx, chainId, svc.chainConfig.Curve, difficulty, svc.options.ProofOfWorkWorkers); err != nil {
+		return fmt.Errorf("计算工作量证明失败：%w", err)
+	}
+	log.Debug().Msgf("计算工作量证明耗时：%d ms，难度：%d", time.Since(start).Milliseconds(), difficulty)
+	return nil
+}
+
 // Start handle transaction, contains
 // 1.Sign transaction,
 // 2.Send transaction to the chain.
-func (svc *lattice) handleTransaction(ctx context.Context, credentials *Credentials, chainId string, transaction *block.Transaction, latestBlock *types.LatestBlock) (*common.Hash, error) {
+func (svc *lattice) handleTransaction(ctx context.Context, credentials *Credentials, chainId string, transaction *block.Transaction, latestBlock *types.LatestBlock, difficulty uint64) (*common.Hash, error) {
 	chainIdAsInt, err := strconv.Atoi(chainId)
 	if err != nil {
 		log.Error().Err(err)
@@ -535,19 +948,15 @@ func (svc *lattice) handleTransaction(ctx context.Context, credentials *Credenti
 	}
 
 	start := time.Now()
-	sk, err := credentials.GetSK()
-	if err != nil {
+	if err = svc.signTransaction(ctx, credentials, uint64(chainIdAsInt), transaction, difficulty); err != nil {
 		log.Error().Err(err)
 		return nil, err
 	}
-	log.Debug().Msgf("获取私钥耗时：%d ms", time.Since(start).Milliseconds())
+	log.Debug().Msgf("签名交易耗时：%d ms", time.Since(start).Milliseconds())
 
-	start = time.Now()
-	if err = transaction.SignTX(uint64(chainIdAsInt), svc.chainConfig.Curve, sk); err != nil {
-		log.Error().Err(err)
+	if err := svc.checkAccountLockLease(chainId, credentials.AccountAddress); err != nil {
 		return nil, err
 	}
-	log.Debug().Msgf("签名交易耗时：%d ms", time.Since(start).Milliseconds())
 
 	start = time.Now()
 	cancelCtx, cancelFunc := context.WithTimeout(ctx, defaultHttpRequestTimeout)
@@ -555,7 +964,7 @@ func (svc *lattice) handleTransaction(ctx context.Context, credentials *Credenti
 	hash, err := svc.httpApi.SendSignedTransaction(cancelCtx, chainId, transaction)
 	if err != nil {
 		log.Error().Err(err)
-		return nil, err
+		return svc.recoverTransaction(ctx, credentials, chainId, transaction, difficulty, err)
 	} else {
 		latestBlock.Hash = *hash
 		latestBlock.IncrHeight()
@@ -576,107 +985,57 @@ func (svc *lattice) IsTokenLess() bool {
 }
 
 func (svc *lattice) Transfer(ctx context.Context, credentials *Credentials, chainId, linker, payload string, amount, joule uint64) (*common.Hash, error) {
-	log.Debug().Msgf("开始发起转账交易，chainId: %s, linker: %s, payload: %s, amount: %d, joule: %d", chainId, linker, payload, amount, joule)
-
-	svc.accountLock.Obtain(chainId, credentials.AccountAddress)
-	defer svc.accountLock.Unlock(chainId, credentials.AccountAddress)
-
-	latestBlock, err := svc.blockCache.GetBlock(chainId, credentials.AccountAddress)
+	result, err := svc.Submit(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeSend, Linker: linker, Payload: payload, Amount: amount, Joule: joule})
 	if err != nil {
-		log.Error().Err(err)
 		return nil, err
 	}
+	return result.Hash, nil
+}
 
-	transaction := block.NewTransactionBuilder(block.TransactionTypeSend).
-		SetLatestBlock(latestBlock).
-		SetOwner(credentials.AccountAddress).
-		SetLinker(linker).
-		SetPayload(payload).
-		SetAmount(amount).
-		SetJoule(joule).
-		Build()
-
-	hash, err := svc.handleTransaction(ctx, credentials, chainId, transaction, latestBlock)
+func (svc *lattice) DeployContract(ctx context.Context, credentials *Credentials, chainId, data, payload string, amount, joule uint64) (*common.Hash, error) {
+	result, err := svc.Submit(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeDeployContract, Code: data, Payload: payload, Amount: amount, Joule: joule})
 	if err != nil {
 		return nil, err
 	}
-	log.Debug().Msgf("结束转账交易，哈希为：%s", hash.String())
-	return hash, nil
+	return result.Hash, nil
 }
 
-func (svc *lattice) DeployContract(ctx context.Context, credentials *Credentials, chainId, data, payload string, amount, joule uint64) (*common.Hash, error) {
-	log.Debug().Msgf("开始发起部署合约交易，chainId: %s, data: %s, payload: %s, amount: %d, joule: %d", chainId, data, payload, amount, joule)
-
-	svc.accountLock.Obtain(chainId, credentials.AccountAddress)
-	defer svc.accountLock.Unlock(chainId, credentials.AccountAddress)
-
-	latestBlock, err := svc.blockCache.GetBlock(chainId, credentials.AccountAddress)
+func (svc *lattice) CallContract(ctx context.Context, credentials *Credentials, chainId, contractAddress, data, payload string, amount, joule uint64) (*common.Hash, error) {
+	result, err := svc.Submit(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeCallContract, Linker: contractAddress, Code: data, Payload: payload, Amount: amount, Joule: joule})
 	if err != nil {
-		log.Error().Err(err)
 		return nil, err
 	}
+	return result.Hash, nil
+}
 
-	transaction := block.NewTransactionBuilder(block.TransactionTypeDeployContract).
-		SetLatestBlock(latestBlock).
-		SetOwner(credentials.AccountAddress).
-		SetLinker(constant.ZeroAddress).
-		SetCode(data).
-		SetPayload(payload).
-		SetAmount(amount).
-		SetJoule(joule).
-		Build()
-
-	cryptoInstance := crypto.NewCrypto(svc.chainConfig.Curve)
-	dataHash := cryptoInstance.Hash(hexutil.MustDecode(data))
-	transaction.CodeHash = dataHash
+func (svc *lattice) UnsafeCallContract(ctx context.Context, credentials *Credentials, chainId, contractAddress, data, payload string, amount, joule uint64) (*common.Hash, error) {
+	log.Debug().Msgf("开始发起调用合约交易，chainId: %s, contractAddress: %s, data: %s, payload: %s, amount: %d, joule: %d", chainId, contractAddress, data, payload, amount, joule)
 
-	hash, err := svc.handleTransaction(ctx, credentials, chainId, transaction, latestBlock)
+	hash, err := svc.unsafeSubmit(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeCallContract, Linker: contractAddress, Code: data, Payload: payload, Amount: amount, Joule: joule})
 	if err != nil {
 		return nil, err
 	}
-	log.Debug().Msgf("结束部署合约，哈希为：%s", hash.String())
+	log.Debug().Msgf("结束调用合约，哈希为：%s", hash.String())
 	return hash, nil
 }
 
-func (svc *lattice) CallContract(ctx context.Context, credentials *Credentials, chainId, contractAddress, data, payload string, amount, joule uint64) (*common.Hash, error) {
-	log.Debug().Msgf("开始发起调用合约交易，chainId: %s, contractAddress: %s, data: %s, payload: %s, amount: %d, joule: %d", chainId, contractAddress, data, payload, amount, joule)
-
-	svc.accountLock.Obtain(chainId, credentials.AccountAddress)
-	defer svc.accountLock.Unlock(chainId, credentials.AccountAddress)
-
-	latestBlock, err := svc.blockCache.GetBlock(chainId, credentials.AccountAddress)
+// unsafeSubmit 在账户锁内签名交易并提前推进区块缓存，释放账户锁后再发送交易，
+// 同一账户的下一笔交易不必等待上一笔交易发送完成
+func (svc *lattice) unsafeSubmit(ctx context.Context, credentials *Credentials, chainId string, req *TxRequest) (*common.Hash, error) {
+	req, err := svc.resolveJoule(ctx, chainId, credentials.AccountAddress, req)
 	if err != nil {
 		return nil, err
 	}
-
-	transaction := block.NewTransactionBuilder(block.TransactionTypeCallContract).
-		SetLatestBlock(latestBlock).
-		SetOwner(credentials.AccountAddress).
-		SetLinker(contractAddress).
-		SetCode(data).
-		SetPayload(payload).
-		SetAmount(amount).
-		SetJoule(joule).
-		Build()
-
-	cryptoInstance := crypto.NewCrypto(svc.chainConfig.Curve)
-	dataHash := cryptoInstance.Hash(hexutil.MustDecode(data))
-	transaction.CodeHash = dataHash
-
-	hash, err := svc.handleTransaction(ctx, credentials, chainId, transaction, latestBlock)
+	difficulty, err := svc.powDifficulty(ctx, chainId)
 	if err != nil {
 		return nil, err
 	}
-	log.Debug().Msgf("结束调用合约，哈希为：%s", hash.String())
-	return hash, nil
-}
-
-func (svc *lattice) UnsafeCallContract(ctx context.Context, credentials *Credentials, chainId, contractAddress, data, payload string, amount, joule uint64) (*common.Hash, error) {
-	log.Debug().Msgf("开始发起调用合约交易，chainId: %s, contractAddress: %s, data: %s, payload: %s, amount: %d, joule: %d", chainId, contractAddress, data, payload, amount, joule)
 
 	start := time.Now()
-	svc.accountLock.Obtain(chainId, credentials.AccountAddress)
-	log.Debug().Msgf("调用合约获取账户锁耗时：%d ms", time.Since(start).Milliseconds())
+	if err := svc.obtainAccountLock(ctx, chainId, credentials.AccountAddress); err != nil {
+		return nil, err
+	}
+	log.Debug().Msgf("获取账户锁耗时：%d ms", time.Since(start).Milliseconds())
 
 	latestBlock, err := svc.blockCache.GetBlock(chainId, credentials.AccountAddress)
 	if err != nil {
@@ -685,19 +1044,7 @@ func (svc *lattice) UnsafeCallContract(ctx context.Context, credentials *Credent
 		return nil, err
 	}
 
-	transaction := block.NewTransactionBuilder(block.TransactionTypeCallContract).
-		SetLatestBlock(latestBlock).
-		SetOwner(credentials.AccountAddress).
-		SetLinker(contractAddress).
-		SetCode(data).
-		SetPayload(payload).
-		SetAmount(amount).
-		SetJoule(joule).
-		Build()
-
-	cryptoInstance := crypto.NewCrypto(svc.chainConfig.Curve)
-	dataHash := cryptoInstance.Hash(hexutil.MustDecode(data))
-	transaction.CodeHash = dataHash
+	transaction := svc.buildTransaction(latestBlock, credentials.AccountAddress, req)
 
 	chainIdAsInt, err := strconv.Atoi(chainId)
 	if err != nil {
@@ -706,14 +1053,7 @@ func (svc *lattice) UnsafeCallContract(ctx context.Context, credentials *Credent
 		log.Error().Err(err)
 		return nil, err
 	}
-	sk, err := credentials.GetSK()
-	if err != nil {
-		// ⚠️Warning don't delete this line of code
-		svc.accountLock.Unlock(chainId, credentials.AccountAddress)
-		log.Error().Err(err)
-		return nil, err
-	}
-	if err = transaction.SignTX(uint64(chainIdAsInt), svc.chainConfig.Curve, sk); err != nil {
+	if err = svc.signTransaction(ctx, credentials, uint64(chainIdAsInt), transaction, difficulty); err != nil {
 		// ⚠️Warning don't delete this line of code
 		svc.accountLock.Unlock(chainId, credentials.AccountAddress)
 		log.Error().Err(err)
@@ -729,6 +1069,11 @@ func (svc *lattice) UnsafeCallContract(ctx context.Context, credentials *Credent
 		return nil, err
 	}
 	log.Debug().Msgf("计算出交易哈希为：%s", calculatedHash.String())
+	if err := svc.checkAccountLockLease(chainId, credentials.AccountAddress); err != nil {
+		// ⚠️Warning don't delete this line of code
+		svc.accountLock.Unlock(chainId, credentials.AccountAddress)
+		return nil, err
+	}
 	latestBlock.Hash = calculatedHash
 	latestBlock.IncrHeight()
 	if err = svc.blockCache.SetBlock(chainId, credentials.AccountAddress, latestBlock); err != nil {
@@ -743,14 +1088,34 @@ func (svc *lattice) UnsafeCallContract(ctx context.Context, credentials *Credent
 	hash, err := svc.httpApi.SendSignedTransaction(cancelCtx, chainId, transaction)
 	if err != nil {
 		log.Error().Err(err)
-		return nil, err
+		// 缓存中的高度已提前增长，发送失败后需要在账户锁内重新同步
+		if lockErr := svc.obtainAccountLock(ctx, chainId, credentials.AccountAddress); lockErr != nil {
+			return nil, err
+		}
+		hash, err = svc.recoverTransaction(ctx, credentials, chainId, transaction, difficulty, err)
+		svc.accountLock.Unlock(chainId, credentials.AccountAddress)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	log.Debug().Msgf("结束调用合约，哈希为：%s", hash.String())
 	return hash, nil
 }
 
 func (svc *lattice) WaitReceipt(ctx context.Context, chainId string, hash *common.Hash, retryStrategy *RetryStrategy) (*common.Hash, *types.Receipt, error) {
+	return svc.waitReceipt(ctx, chainId, "", hash, retryStrategy)
+}
+
+// waitReceipt 等待交易回执，开启 Options.ReturnRevertError 且回执为失败时使用合约地址注册的ABI解码失败原因
+func (svc *lattice) waitReceipt(ctx context.Context, chainId, contractAddress string, hash *common.Hash, retryStrategy *RetryStrategy) (*common.Hash, *types.Receipt, error) {
+	if svc.receiptWatcher != nil {
+		hash, receipt, err := svc.watchReceipt(ctx, chainId, hash)
+		if err != nil {
+			return hash, nil, err
+		}
+		return hash, receipt, svc.receiptError(contractAddress, receipt)
+	}
+
 	var err error
 	var receipt *types.Receipt
 	err = retry.Do(
@@ -778,34 +1143,31 @@ func (svc *lattice) WaitReceipt(ctx context.Context, chainId string, hash *commo
 		log.Error().Err(err)
 		return hash, nil, err
 	}
-	return hash, receipt, nil
+	return hash, receipt, svc.receiptError(contractAddress, receipt)
 }
 
-func (svc *lattice) TransferWaitReceipt(ctx context.Context, credentials *Credentials, chainId, linker, payload string, amount, joule uint64, retryStrategy *RetryStrategy) (*common.Hash, *types.Receipt, error) {
-	hash, err := svc.Transfer(ctx, credentials, chainId, linker, payload, amount, joule)
+// watchReceipt 通过回执监听器等待回执，最多等待 ReceiptWatcherConfig.Timeout
+func (svc *lattice) watchReceipt(ctx context.Context, chainId string, hash *common.Hash) (*common.Hash, *types.Receipt, error) {
+	cancelCtx, cancelFunc := context.WithTimeout(ctx, svc.options.ReceiptWatcher.Timeout)
+	defer cancelFunc()
+	receipt, err := svc.receiptWatcher.Wait(cancelCtx, chainId, *hash)
 	if err != nil {
-		return nil, nil, err
+		log.Error().Err(err).Msgf("等待交易【%s】的回执失败", hash.String())
+		return hash, nil, fmt.Errorf("等待交易【%s】的回执失败：%w", hash.String(), err)
 	}
+	return hash, receipt, nil
+}
 
-	return svc.WaitReceipt(ctx, chainId, hash, retryStrategy)
+func (svc *lattice) TransferWaitReceipt(ctx context.Context, credentials *Credentials, chainId, linker, payload string, amount, joule uint64, retryStrategy *RetryStrategy) (*common.Hash, *types.Receipt, error) {
+	return svc.submitWaitReceipt(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeSend, Linker: linker, Payload: payload, Amount: amount, Joule: joule}, retryStrategy)
 }
 
 func (svc *lattice) DeployContractWaitReceipt(ctx context.Context, credentials *Credentials, chainId, data, payload string, amount, joule uint64, retryStrategy *RetryStrategy) (*common.Hash, *types.Receipt, error) {
-	hash, err := svc.DeployContract(ctx, credentials, chainId, data, payload, amount, joule)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return svc.WaitReceipt(ctx, chainId, hash, retryStrategy)
+	return svc.submitWaitReceipt(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeDeployContract, Code: data, Payload: payload, Amount: amount, Joule: joule}, retryStrategy)
 }
 
 func (svc *lattice) CallContractWaitReceipt(ctx context.Context, credentials *Credentials, chainId, contractAddress, data, payload string, amount, joule uint64, retryStrategy *RetryStrategy) (*common.Hash, *types.Receipt, error) {
-	hash, err := svc.CallContract(ctx, credentials, chainId, contractAddress, data, payload, amount, joule)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return svc.WaitReceipt(ctx, chainId, hash, retryStrategy)
+	return svc.submitWaitReceipt(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeCallContract, Linker: contractAddress, Code: data, Payload: payload, Amount: amount, Joule: joule}, retryStrategy)
 }
 
 func (svc *lattice) PreCallContract(ctx context.Context, chainId, owner, contractAddress, data, payload string) (*types.Receipt, error) {
@@ -829,330 +1191,110 @@ func (svc *lattice) PreCallContract(ctx context.Context, chainId, owner, contrac
 		return nil, err
 	}
 	log.Debug().Msgf("结束预调用合约，回执为：%+v", receipt)
-	return receipt, nil
+	return receipt, svc.receiptError(contractAddress, receipt)
 }
 
 func (svc *lattice) UpgradeContract(ctx context.Context, credentials *Credentials, chainId, contractAddress, data, payload string, amount, joule uint64) (*common.Hash, error) {
-	log.Debug().Msgf("开始发起升级合约交易，chainId: %s, contractAddress: %s, data: %s, payload: %s, amount: %d, joule: %d", chainId, contractAddress, data, payload, amount, joule)
-
-	svc.accountLock.Obtain(chainId, credentials.AccountAddress)
-	defer svc.accountLock.Unlock(chainId, credentials.AccountAddress)
-
-	latestBlock, err := svc.blockCache.GetBlock(chainId, credentials.AccountAddress)
+	result, err := svc.Submit(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeUpgradeContract, Linker: contractAddress, Code: data, Payload: payload, Amount: amount, Joule: joule})
 	if err != nil {
 		return nil, err
 	}
-
-	transaction := block.NewTransactionBuilder(block.TransactionTypeUpgradeContract).
-		SetLatestBlock(latestBlock).
-		SetOwner(credentials.AccountAddress).
-		SetLinker(contractAddress).
-		SetCode(data).
-		SetPayload(payload).
-		SetAmount(amount).
-		SetJoule(joule).
-		Build()
-
-	cryptoInstance := crypto.NewCrypto(svc.chainConfig.Curve)
-	dataHash := cryptoInstance.Hash(hexutil.MustDecode(data))
-	transaction.CodeHash = dataHash
-
-	hash, err := svc.handleTransaction(ctx, credentials, chainId, transaction, latestBlock)
-	if err != nil {
-		return nil, err
-	}
-	log.Debug().Msgf("结束升级合约，哈希为：%s", hash.String())
-	return hash, nil
+	return result.Hash, nil
 }
 
 func (svc *lattice) UpgradeContractWaitReceipt(ctx context.Context, credentials *Credentials, chainId, contractAddress, data, payload string, amount, joule uint64, retryStrategy *RetryStrategy) (*common.Hash, *types.Receipt, error) {
-	hash, err := svc.UpgradeContract(ctx, credentials, chainId, contractAddress, data, payload, amount, joule)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return svc.WaitReceipt(ctx, chainId, hash, retryStrategy)
+	return svc.submitWaitReceipt(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeUpgradeContract, Linker: contractAddress, Code: data, Payload: payload, Amount: amount, Joule: joule}, retryStrategy)
 }
 
-func (svc *lattice) deployMultilingualContract(ctx context.Context, credentials *Credentials, chainId string, lang types.ContractLang, data types.DeployMultilingualContractCode, payload string, amount, joule uint64) (*common.Hash, error) {
-	log.Debug().Msgf("开始发起部署%s合约交易，chainId: %s, data: %+v, payload: %s, amount: %d, joule: %d", lang, chainId, data, payload, amount, joule)
-
-	svc.accountLock.Obtain(chainId, credentials.AccountAddress)
-	defer svc.accountLock.Unlock(chainId, credentials.AccountAddress)
-
-	latestBlock, err := svc.blockCache.GetBlock(chainId, credentials.AccountAddress)
+func (svc *lattice) DeployGoContract(ctx context.Context, credentials *Credentials, chainId string, data types.DeployMultilingualContractCode, payload string, amount, joule uint64) (*common.Hash, error) {
+	result, err := svc.Submit(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeDeployGoContract, Code: data.Encode(), Payload: payload, Amount: amount, Joule: joule})
 	if err != nil {
-		log.Error().Err(err)
 		return nil, err
 	}
+	return result.Hash, nil
+}
 
-	var transactionType block.TransactionType
-	switch lang {
-	case types.ContractLangGo:
-		transactionType = block.TransactionTypeDeployGoContract
-	case types.ContractLangJava:
-		transactionType = block.TransactionTypeDeployJavaContract
-	default:
-	}
-	code := data.Encode()
-	transaction := block.NewTransactionBuilder(transactionType).
-		SetLatestBlock(latestBlock).
-		SetOwner(credentials.AccountAddress).
-		SetLinker(constant.ZeroAddress).
-		SetCode(code).
-		SetPayload(payload).
-		SetAmount(amount).
-		SetJoule(joule).
-		Build()
-
-	cryptoInstance := crypto.NewCrypto(svc.chainConfig.Curve)
-	dataHash := cryptoInstance.Hash(hexutil.MustDecode(code))
-	transaction.CodeHash = dataHash
-
-	hash, err := svc.handleTransaction(ctx, credentials, chainId, transaction, latestBlock)
+func (svc *lattice) UpgradeGoContract(ctx context.Context, credentials *Credentials, chainId, contractAddress string, data types.UpgradeMultilingualContractCode, payload string, amount, joule uint64) (*common.Hash, error) {
+	result, err := svc.Submit(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeUpgradeGoContract, Linker: contractAddress, Code: data.Encode(), Payload: payload, Amount: amount, Joule: joule})
 	if err != nil {
 		return nil, err
 	}
-	log.Debug().Msgf("结束部署%s合约，哈希为：%s", lang, hash.String())
-	return hash, nil
+	return result.Hash, nil
 }
 
-func (svc *lattice) upgradeMultilingualContract(ctx context.Context, credentials *Credentials, chainId, contractAddress string, lang types.ContractLang, data types.UpgradeMultilingualContractCode, payload string, amount, joule uint64) (*common.Hash, error) {
-	log.Debug().Msgf("开始发起升级%s合约交易，chainId: %s, contractAddress: %s, data: %s, payload: %s, amount: %d, joule: %d", lang, chainId, contractAddress, data, payload, amount, joule)
-
-	svc.accountLock.Obtain(chainId, credentials.AccountAddress)
-	defer svc.accountLock.Unlock(chainId, credentials.AccountAddress)
-
-	latestBlock, err := svc.blockCache.GetBlock(chainId, credentials.AccountAddress)
+func (svc *lattice) CallGoContract(ctx context.Context, credentials *Credentials, chainId, contractAddress string, data types.CallMultilingualContractCode, payload string, amount, joule uint64) (*common.Hash, error) {
+	result, err := svc.Submit(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeCallGoContract, Linker: contractAddress, Code: data.Encode(), Payload: payload, Amount: amount, Joule: joule})
 	if err != nil {
 		return nil, err
 	}
+	return result.Hash, nil
+}
 
-	var transactionType block.TransactionType
-	switch lang {
-	case types.ContractLangGo:
-		transactionType = block.TransactionTypeUpgradeGoContract
-	case types.ContractLangJava:
-		transactionType = block.TransactionTypeUpgradeJavaContract
-	default:
-	}
-	code := data.Encode()
-	transaction := block.NewTransactionBuilder(transactionType).
-		SetLatestBlock(latestBlock).
-		SetOwner(credentials.AccountAddress).
-		SetLinker(contractAddress).
-		SetCode(code).
-		SetPayload(payload).
-		SetAmount(amount).
-		SetJoule(joule).
-		Build()
-
-	cryptoInstance := crypto.NewCrypto(svc.chainConfig.Curve)
-	dataHash := cryptoInstance.Hash(hexutil.MustDecode(code))
-	transaction.CodeHash = dataHash
-
-	hash, err := svc.handleTransaction(ctx, credentials, chainId, transaction, latestBlock)
+func (svc *lattice) DeployJavaContract(ctx context.Context, credentials *Credentials, chainId string, data types.DeployMultilingualContractCode, payload string, amount, joule uint64) (*common.Hash, error) {
+	result, err := svc.Submit(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeDeployJavaContract, Code: data.Encode(), Payload: payload, Amount: amount, Joule: joule})
 	if err != nil {
 		return nil, err
 	}
-	log.Debug().Msgf("结束升级%s合约，哈希为：%s", lang, hash.String())
-	return hash, nil
+	return result.Hash, nil
 }
 
-func (svc *lattice) callMultilingualContract(ctx context.Context, credentials *Credentials, chainId, contractAddress string, lang types.ContractLang, data types.CallMultilingualContractCode, payload string, amount, joule uint64) (*common.Hash, error) {
-	log.Debug().Msgf("开始发起调用%s合约交易，chainId: %s, contractAddress: %s, data: %s, payload: %s, amount: %d, joule: %d", lang, chainId, contractAddress, data, payload, amount, joule)
-
-	svc.accountLock.Obtain(chainId, credentials.AccountAddress)
-	defer svc.accountLock.Unlock(chainId, credentials.AccountAddress)
-
-	latestBlock, err := svc.blockCache.GetBlock(chainId, credentials.AccountAddress)
+func (svc *lattice) UpgradeJavaContract(ctx context.Context, credentials *Credentials, chainId, contractAddress string, data types.UpgradeMultilingualContractCode, payload string, amount, joule uint64) (*common.Hash, error) {
+	result, err := svc.Submit(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeUpgradeJavaContract, Linker: contractAddress, Code: data.Encode(), Payload: payload, Amount: amount, Joule: joule})
 	if err != nil {
 		return nil, err
 	}
+	return result.Hash, nil
+}
 
-	var transactionType block.TransactionType
-	switch lang {
-	case types.ContractLangGo:
-		transactionType = block.TransactionTypeCallGoContract
-	case types.ContractLangJava:
-		transactionType = block.TransactionTypeCallJavaContract
-	default:
-	}
-	code := data.Encode()
-	transaction := block.NewTransactionBuilder(transactionType).
-		SetLatestBlock(latestBlock).
-		SetOwner(credentials.AccountAddress).
-		SetLinker(contractAddress).
-		SetCode(code).
-		SetPayload(payload).
-		SetAmount(amount).
-		SetJoule(joule).
-		Build()
-
-	cryptoInstance := crypto.NewCrypto(svc.chainConfig.Curve)
-	dataHash := cryptoInstance.Hash(hexutil.MustDecode(code))
-	transaction.CodeHash = dataHash
-
-	hash, err := svc.handleTransaction(ctx, credentials, chainId, transaction, latestBlock)
+func (svc *lattice) CallJavaContract(ctx context.Context, credentials *Credentials, chainId, contractAddress string, data types.CallMultilingualContractCode, payload string, amount, joule uint64) (*common.Hash, error) {
+	result, err := svc.Submit(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeCallJavaContract, Linker: contractAddress, Code: data.Encode(), Payload: payload, Amount: amount, Joule: joule})
 	if err != nil {
 		return nil, err
 	}
-	log.Debug().Msgf("结束调用%s合约，哈希为：%s", lang, hash.String())
-	return hash, nil
-}
-
-func (svc *lattice) DeployGoContract(ctx context.Context, credentials *Credentials, chainId string, data types.DeployMultilingualContractCode, payload string, amount, joule uint64) (*common.Hash, error) {
-	return svc.deployMultilingualContract(ctx, credentials, chainId, types.ContractLangGo, data, payload, amount, joule)
-}
-
-func (svc *lattice) UpgradeGoContract(ctx context.Context, credentials *Credentials, chainId, contractAddress string, data types.UpgradeMultilingualContractCode, payload string, amount, joule uint64) (*common.Hash, error) {
-	return svc.upgradeMultilingualContract(ctx, credentials, chainId, contractAddress, types.ContractLangGo, data, payload, amount, joule)
-}
-
-func (svc *lattice) CallGoContract(ctx context.Context, credentials *Credentials, chainId, contractAddress string, data types.CallMultilingualContractCode, payload string, amount, joule uint64) (*common.Hash, error) {
-	return svc.callMultilingualContract(ctx, credentials, chainId, contractAddress, types.ContractLangGo, data, payload, amount, joule)
-}
-
-func (svc *lattice) DeployJavaContract(ctx context.Context, credentials *Credentials, chainId string, data types.DeployMultilingualContractCode, payload string, amount, joule uint64) (*common.Hash, error) {
-	return svc.deployMultilingualContract(ctx, credentials, chainId, types.ContractLangJava, data, payload, amount, joule)
-}
-
-func (svc *lattice) UpgradeJavaContract(ctx context.Context, credentials *Credentials, chainId, contractAddress string, data types.UpgradeMultilingualContractCode, payload string, amount, joule uint64) (*common.Hash, error) {
-	return svc.upgradeMultilingualContract(ctx, credentials, chainId, contractAddress, types.ContractLangJava, data, payload, amount, joule)
-}
-
-func (svc *lattice) CallJavaContract(ctx context.Context, credentials *Credentials, chainId, contractAddress string, data types.CallMultilingualContractCode, payload string, amount, joule uint64) (*common.Hash, error) {
-	return svc.callMultilingualContract(ctx, credentials, chainId, contractAddress, types.ContractLangJava, data, payload, amount, joule)
+	return result.Hash, nil
 }
 
 func (svc *lattice) DeployGoContractWaitReceipt(ctx context.Context, credentials *Credentials, chainId string, data types.DeployMultilingualContractCode, payload string, amount, joule uint64, retryStrategy *RetryStrategy) (*common.Hash, *types.Receipt, error) {
-	hash, err := svc.DeployGoContract(ctx, credentials, chainId, data, payload, amount, joule)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return svc.WaitReceipt(ctx, chainId, hash, retryStrategy)
+	return svc.submitWaitReceipt(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeDeployGoContract, Code: data.Encode(), Payload: payload, Amount: amount, Joule: joule}, retryStrategy)
 }
 
 func (svc *lattice) UpgradeGoContractWaitReceipt(ctx context.Context, credentials *Credentials, chainId, contractAddress string, data types.UpgradeMultilingualContractCode, payload string, amount, joule uint64, retryStrategy *RetryStrategy) (*common.Hash, *types.Receipt, error) {
-	hash, err := svc.UpgradeGoContract(ctx, credentials, chainId, contractAddress, data, payload, amount, joule)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return svc.WaitReceipt(ctx, chainId, hash, retryStrategy)
+	return svc.submitWaitReceipt(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeUpgradeGoContract, Linker: contractAddress, Code: data.Encode(), Payload: payload, Amount: amount, Joule: joule}, retryStrategy)
 }
 
 func (svc *lattice) CallGoContractWaitReceipt(ctx context.Context, credentials *Credentials, chainId, contractAddress string, data types.CallMultilingualContractCode, payload string, amount, joule uint64, retryStrategy *RetryStrategy) (*common.Hash, *types.Receipt, error) {
-	hash, err := svc.CallGoContract(ctx, credentials, chainId, contractAddress, data, payload, amount, joule)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return svc.WaitReceipt(ctx, chainId, hash, retryStrategy)
+	return svc.submitWaitReceipt(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeCallGoContract, Linker: contractAddress, Code: data.Encode(), Payload: payload, Amount: amount, Joule: joule}, retryStrategy)
 }
 
 func (svc *lattice) DeployJavaContractWaitReceipt(ctx context.Context, credentials *Credentials, chainId string, data types.DeployMultilingualContractCode, payload string, amount, joule uint64, retryStrategy *RetryStrategy) (*common.Hash, *types.Receipt, error) {
-	hash, err := svc.DeployJavaContract(ctx, credentials, chainId, data, payload, amount, joule)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return svc.WaitReceipt(ctx, chainId, hash, retryStrategy)
+	return svc.submitWaitReceipt(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeDeployJavaContract, Code: data.Encode(), Payload: payload, Amount: amount, Joule: joule}, retryStrategy)
 }
 
 func (svc *lattice) UpgradeJavaContractWaitReceipt(ctx context.Context, credentials *Credentials, chainId, contractAddress string, data types.UpgradeMultilingualContractCode, payload string, amount, joule uint64, retryStrategy *RetryStrategy) (*common.Hash, *types.Receipt, error) {
-	hash, err := svc.UpgradeJavaContract(ctx, credentials, chainId, contractAddress, data, payload, amount, joule)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return svc.WaitReceipt(ctx, chainId, hash, retryStrategy)
+	return svc.submitWaitReceipt(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeUpgradeJavaContract, Linker: contractAddress, Code: data.Encode(), Payload: payload, Amount: amount, Joule: joule}, retryStrategy)
 }
 
 func (svc *lattice) CallJavaContractWaitReceipt(ctx context.Context, credentials *Credentials, chainId, contractAddress string, data types.CallMultilingualContractCode, payload string, amount, joule uint64, retryStrategy *RetryStrategy) (*common.Hash, *types.Receipt, error) {
-	hash, err := svc.CallJavaContract(ctx, credentials, chainId, contractAddress, data, payload, amount, joule)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return svc.WaitReceipt(ctx, chainId, hash, retryStrategy)
+	return svc.submitWaitReceipt(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeCallJavaContract, Linker: contractAddress, Code: data.Encode(), Payload: payload, Amount: amount, Joule: joule}, retryStrategy)
 }
 
-func (svc *lattice) NewCallContractTx(_ context.Context, credentials *Credentials, chainId, contractAddress, data, payload string, amount, joule uint64) (unsignedTx *block.Transaction, unsignedHash common.Hash, err error) {
+func (svc *lattice) NewCallContractTx(ctx context.Context, credentials *Credentials, chainId, contractAddress, data, payload string, amount, joule uint64) (unsignedTx *block.Transaction, unsignedHash common.Hash, err error) {
 	log.Debug().Msgf("开始构造调用合约交易，chainId: %s, contractAddress: %s, data: %s, payload: %s, amount: %d, joule: %d", chainId, contractAddress, data, payload, amount, joule)
 
-	svc.accountLock.Obtain(chainId, credentials.AccountAddress)
-	defer svc.accountLock.Unlock(chainId, credentials.AccountAddress)
-
-	latestBlock, err := svc.blockCache.GetBlock(chainId, credentials.AccountAddress)
+	unsignedTx, unsignedHash, err = svc.newUnsignedTx(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeCallContract, Linker: contractAddress, Code: data, Payload: payload, Amount: amount, Joule: joule})
 	if err != nil {
 		return nil, common.Hash{}, err
 	}
 
-	unsignedTx = block.NewTransactionBuilder(block.TransactionTypeCallContract).
-		SetLatestBlock(latestBlock).
-		SetOwner(credentials.AccountAddress).
-		SetLinker(contractAddress).
-		SetCode(data).
-		SetPayload(payload).
-		SetAmount(amount).
-		SetJoule(joule).
-		Build()
-
-	cryptoInstance := crypto.NewCrypto(svc.chainConfig.Curve)
-	dataHash := cryptoInstance.Hash(hexutil.MustDecode(data))
-	unsignedTx.CodeHash = dataHash
-
-	chainIdAsInt, err := strconv.Atoi(chainId)
-	if err != nil {
-		log.Error().Err(err)
-		return nil, common.Hash{}, err
-	}
-	unsignedHash, err = unsignedTx.RlpEncodeHash(uint64(chainIdAsInt), svc.chainConfig.Curve)
-	if err != nil {
-		log.Error().Err(err)
-		return nil, common.Hash{}, err
-	}
-
 	log.Debug().Msgf("结束构造调用合约交易，待签名消息为：%s", unsignedHash)
 	return unsignedTx, unsignedHash, nil
 }
 
-func (svc *lattice) NewDeployContractTx(_ context.Context, credentials *Credentials, chainId, data, payload string, amount, joule uint64) (unsignedTx *block.Transaction, unsignedHash common.Hash, err error) {
+func (svc *lattice) NewDeployContractTx(ctx context.Context, credentials *Credentials, chainId, data, payload string, amount, joule uint64) (unsignedTx *block.Transaction, unsignedHash common.Hash, err error) {
 	log.Debug().Msgf("开始构造部署合约交易，chainId: %s, data: %s, payload: %s, amount: %d, joule: %d", chainId, data, payload, amount, joule)
 
-	svc.accountLock.Obtain(chainId, credentials.AccountAddress)
-	defer svc.accountLock.Unlock(chainId, credentials.AccountAddress)
-
-	latestBlock, err := svc.blockCache.GetBlock(chainId, credentials.AccountAddress)
+	unsignedTx, unsignedHash, err = svc.newUnsignedTx(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeDeployContract, Code: data, Payload: payload, Amount: amount, Joule: joule})
 	if err != nil {
-		log.Error().Err(err)
-		return nil, common.Hash{}, err
-	}
-
-	unsignedTx = block.NewTransactionBuilder(block.TransactionTypeDeployContract).
-		SetLatestBlock(latestBlock).
-		SetOwner(credentials.AccountAddress).
-		SetLinker(constant.ZeroAddress).
-		SetCode(data).
-		SetPayload(payload).
-		SetAmount(amount).
-		SetJoule(joule).
-		Build()
-
-	cryptoInstance := crypto.NewCrypto(svc.chainConfig.Curve)
-	dataHash := cryptoInstance.Hash(hexutil.MustDecode(data))
-	unsignedTx.CodeHash = dataHash
-
-	chainIdAsInt, err := strconv.Atoi(chainId)
-	if err != nil {
-		log.Error().Err(err)
-		return nil, common.Hash{}, err
-	}
-	unsignedHash, err = unsignedTx.RlpEncodeHash(uint64(chainIdAsInt), svc.chainConfig.Curve)
-	if err != nil {
-		log.Error().Err(err)
 		return nil, common.Hash{}, err
 	}
 
@@ -0,0 +1,92 @@
+package lattice
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsIdempotentMethod(t *testing.T) {
+	assert.True(t, client.IsIdempotentMethod("latc_getReceipt"))
+	assert.True(t, client.IsIdempotentMethod("wallet_preExecuteContract"))
+	assert.False(t, client.IsIdempotentMethod("wallet_sendRawTBlock"))
+	assert.False(t, client.IsIdempotentMethod("wallet_sendRawBatchTBlock"))
+	assert.False(t, client.IsIdempotentMethod("wallet_importRawKey"))
+}
+
+func TestRequestMiddlewares(t *testing.T) {
+	ctx := context.Background()
+	node := newMockNode(t)
+	node.handle("latc_getReceipt", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.Receipt{}, nil
+	})
+	node.handle("wallet_sendRawTBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return common.HexToHash("0x01"), nil
+	})
+	tx := &block.Transaction{Amount: big.NewInt(0), Joule: big.NewInt(0)}
+
+	t.Run("retry idempotent method", func(t *testing.T) {
+		svc := newMockLattice(t, node, &Options{RequestRetryStrategy: NewFixedRetryStrategy(3, time.Millisecond)})
+		node.respondWithStatus(http.StatusBadGateway, http.StatusServiceUnavailable)
+		_, err := svc.HttpApi().GetReceipt(ctx, chainId, "0x01")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, node.callCount("latc_getReceipt"))
+	})
+
+	t.Run("never retry send transaction", func(t *testing.T) {
+		svc := newMockLattice(t, node, &Options{RequestRetryStrategy: NewFixedRetryStrategy(3, time.Millisecond)})
+		node.respondWithStatus(http.StatusBadGateway)
+		_, err := svc.HttpApi().SendSignedTransaction(ctx, chainId, tx)
+		var statusErr *client.HttpStatusError
+		assert.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, http.StatusBadGateway, statusErr.StatusCode)
+		assert.Equal(t, 1, node.callCount("wallet_sendRawTBlock"))
+	})
+
+	t.Run("method timeout", func(t *testing.T) {
+		slow := newMockNode(t)
+		slow.handle("latc_getReceipt", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+			time.Sleep(200 * time.Millisecond)
+			return &types.Receipt{}, nil
+		})
+		svc := newMockLattice(t, slow, &Options{
+			DisableRequestRetry: true,
+			MethodTimeouts:      map[string]time.Duration{"latc_getReceipt": 20 * time.Millisecond},
+		})
+		start := time.Now()
+		_, err := svc.HttpApi().GetReceipt(ctx, chainId, "0x01")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), 150*time.Millisecond)
+	})
+
+	t.Run("circuit breaker", func(t *testing.T) {
+		down := newMockNode(t)
+		svc := newMockLattice(t, down, &Options{
+			DisableRequestRetry: true,
+			CircuitBreaker:      &client.CircuitBreakerConfig{FailureThreshold: 2, OpenDuration: 50 * time.Millisecond},
+		})
+		down.server.Close()
+		for i := 0; i < 2; i++ {
+			_, err := svc.HttpApi().GetReceipt(ctx, chainId, "0x01")
+			assert.True(t, client.IsRetryableError(err))
+		}
+		_, err := svc.HttpApi().GetReceipt(ctx, chainId, "0x01")
+		assert.ErrorIs(t, err, client.ErrCircuitOpen)
+
+		// 打开时间过后放行试探请求，节点仍不可用时重新打开
+		time.Sleep(60 * time.Millisecond)
+		_, err = svc.HttpApi().GetReceipt(ctx, chainId, "0x01")
+		assert.NotErrorIs(t, err, client.ErrCircuitOpen)
+		_, err = svc.HttpApi().GetReceipt(ctx, chainId, "0x01")
+		assert.ErrorIs(t, err, client.ErrCircuitOpen)
+	})
+}
@@ -0,0 +1,356 @@
+package lattice
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LatticeBCLab/go-lattice/common/convert"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/crypto"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockHandler 模拟节点的JsonRpc方法，返回 result 或 JsonRpcError
+type mockHandler func(params []json.RawMessage) (any, *client.JsonRpcError)
+
+type mockRequest struct {
+	Id      int               `json:"id"`
+	JsonRpc string            `json:"jsonrpc"`
+	Method  string            `json:"method"`
+	Params  []json.RawMessage `json:"params"`
+}
+
+type mockResponse struct {
+	Id      int                  `json:"id"`
+	JsonRpc string               `json:"jsonrpc"`
+	Result  any                  `json:"result,omitempty"`
+	Error   *client.JsonRpcError `json:"error,omitempty"`
+}
+
+// mockNode 基于 httptest 的模拟节点，按方法名分发JsonRpc请求，支持批量请求
+type mockNode struct {
+	server   *httptest.Server
+	mu       sync.Mutex
+	handlers map[string]mockHandler
+	calls    map[string]int
+	statuses []int // 依次作为后续请求的http状态码返回，不处理请求
+}
+
+func newMockNode(t *testing.T) *mockNode {
+	node := &mockNode{
+		handlers: make(map[string]mockHandler),
+		calls:    make(map[string]int),
+	}
+	node.server = httptest.NewServer(http.HandlerFunc(node.serveHTTP))
+	t.Cleanup(node.server.Close)
+	return node
+}
+
+func (n *mockNode) handle(method string, handler mockHandler) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.handlers[method] = handler
+}
+
+// respondWithStatus 后续的请求依次直接返回给定的http状态码
+func (n *mockNode) respondWithStatus(statusCodes ...int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.statuses = append(n.statuses, statusCodes...)
+}
+
+func (n *mockNode) callCount(method string) int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.calls[method]
+}
+
+func (n *mockNode) dispatch(req *mockRequest) *mockResponse {
+	n.mu.Lock()
+	handler, ok := n.handlers[req.Method]
+	n.calls[req.Method]++
+	n.mu.Unlock()
+
+	resp := &mockResponse{Id: req.Id, JsonRpc: "2.0"}
+	if !ok {
+		resp.Error = &client.JsonRpcError{Code: -32601, Message: "the method " + req.Method + " does not exist"}
+		return resp
+	}
+	resp.Result, resp.Error = handler(req.Params)
+	return resp
+}
+
+func (n *mockNode) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	n.mu.Lock()
+	if len(n.statuses) > 0 {
+		statusCode := n.statuses[0]
+		n.statuses = n.statuses[1:]
+		var req mockRequest
+		_ = json.Unmarshal(raw, &req)
+		n.calls[req.Method]++
+		n.mu.Unlock()
+		w.WriteHeader(statusCode)
+		return
+	}
+	n.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+	if len(raw) > 0 && raw[0] == '[' {
+		var reqs []*mockRequest
+		_ = json.Unmarshal(raw, &reqs)
+		resps := make([]*mockResponse, len(reqs))
+		for i, req := range reqs {
+			resps[i] = n.dispatch(req)
+		}
+		_ = json.NewEncoder(w).Encode(resps)
+		return
+	}
+	var req mockRequest
+	_ = json.Unmarshal(raw, &req)
+	_ = json.NewEncoder(w).Encode(n.dispatch(&req))
+}
+
+// connectingNodeConfig 返回连接模拟节点的配置
+func (n *mockNode) connectingNodeConfig(t *testing.T) *ConnectingNodeConfig {
+	host, port, err := net.SplitHostPort(n.server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := strconv.Atoi(port)
+	return &ConnectingNodeConfig{Ip: host, HttpPort: uint16(p), WebsocketPort: uint16(p)}
+}
+
+// newMockLattice 初始化连接模拟节点的 Lattice，使用国密曲线
+func newMockLattice(t *testing.T, node *mockNode, options *Options) *lattice {
+	if options == nil {
+		options = &Options{}
+	}
+	return NewLattice(
+		&ChainConfig{Curve: types.Sm2p256v1, TokenLess: true},
+		node.connectingNodeConfig(t),
+		NewMemoryBlockCache(10*time.Second, time.Minute, time.Minute),
+		NewAccountLock(),
+		options,
+	).(*lattice)
+}
+
+// newTestCredentials 生成一个随机的国密账户
+func newTestCredentials(t *testing.T) *Credentials {
+	api := crypto.NewCrypto(types.Sm2p256v1)
+	sk, err := api.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	skHex, err := api.SKToHexString(sk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, err := api.PKToAddress(&sk.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Credentials{AccountAddress: convert.AddressToZltc(address), PrivateKey: skHex}
+}
+
+// mockWsNode 基于 httptest 的模拟websocket节点，处理订阅和取消订阅请求，并可向订阅推送数据
+type mockWsNode struct {
+	server   *httptest.Server
+	upgrader websocket.Upgrader
+
+	mu             sync.Mutex
+	nextSubId      int
+	conns          map[*websocket.Conn]*sync.Mutex // 连接和连接的写锁
+	subs           map[string]*websocket.Conn      // key: 订阅ID
+	subParams      map[string][]json.RawMessage    // key: 订阅ID
+	unsubscribed   []string
+	connectedTotal int
+}
+
+func newMockWsNode(t *testing.T) *mockWsNode {
+	node := &mockWsNode{
+		conns:     make(map[*websocket.Conn]*sync.Mutex),
+		subs:      make(map[string]*websocket.Conn),
+		subParams: make(map[string][]json.RawMessage),
+	}
+	node.server = httptest.NewServer(http.HandlerFunc(node.serveWs))
+	t.Cleanup(func() {
+		node.dropConnections()
+		node.server.Close()
+	})
+	return node
+}
+
+func (n *mockWsNode) url() string {
+	return "ws" + strings.TrimPrefix(n.server.URL, "http")
+}
+
+func (n *mockWsNode) serveWs(w http.ResponseWriter, r *http.Request) {
+	conn, err := n.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	writeMu := &sync.Mutex{}
+	n.mu.Lock()
+	n.conns[conn] = writeMu
+	n.connectedTotal++
+	n.mu.Unlock()
+	defer n.removeConn(conn)
+
+	for {
+		var req mockRequest
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		resp := &mockResponse{Id: req.Id, JsonRpc: "2.0"}
+		n.mu.Lock()
+		switch {
+		case strings.HasSuffix(req.Method, "_unsubscribe"):
+			var id string
+			_ = json.Unmarshal(req.Params[0], &id)
+			delete(n.subs, id)
+			n.unsubscribed = append(n.unsubscribed, id)
+			resp.Result = true
+		case strings.HasSuffix(req.Method, "_subscribe"):
+			n.nextSubId++
+			id := "0x" + strconv.Itoa(n.nextSubId)
+			n.subs[id] = conn
+			n.subParams[id] = req.Params
+			resp.Result = id
+		default:
+			resp.Error = &client.JsonRpcError{Code: -32601, Message: "the method " + req.Method + " does not exist"}
+		}
+		n.mu.Unlock()
+		writeMu.Lock()
+		err := conn.WriteJSON(resp)
+		writeMu.Unlock()
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (n *mockWsNode) removeConn(conn *websocket.Conn) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.conns, conn)
+	for id, c := range n.subs {
+		if c == conn {
+			delete(n.subs, id)
+		}
+	}
+	_ = conn.Close()
+}
+
+// subscriptionIds 当前有效的订阅ID
+func (n *mockWsNode) subscriptionIds() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	ids := make([]string, 0, len(n.subs))
+	for id := range n.subs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// waitSubscriptions 等待节点上有n个有效的订阅
+func (n *mockWsNode) waitSubscriptions(t *testing.T, count int) []string {
+	var ids []string
+	assert.Eventually(t, func() bool {
+		ids = n.subscriptionIds()
+		return len(ids) == count
+	}, 5*time.Second, 10*time.Millisecond)
+	return ids
+}
+
+// subscriptionParams 订阅请求的参数
+func (n *mockWsNode) subscriptionParams(id string) []json.RawMessage {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.subParams[id]
+}
+
+// subscriptionName 订阅的第一个参数，如 newDBlock、workflow
+func (n *mockWsNode) subscriptionName(id string) string {
+	var name string
+	if params := n.subscriptionParams(id); len(params) > 0 {
+		_ = json.Unmarshal(params[0], &name)
+	}
+	return name
+}
+
+// notify 向订阅推送一条数据
+func (n *mockWsNode) notify(t *testing.T, id string, result any) {
+	n.mu.Lock()
+	conn, ok := n.subs[id]
+	writeMu := n.conns[conn]
+	n.mu.Unlock()
+	if !ok {
+		t.Fatalf("subscription %s not found", id)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	err = conn.WriteJSON(&client.SubscribeResponse{
+		JsonRpc: "2.0",
+		Method:  "node_subscription",
+		Params:  client.SubscriptionResult{ID: id, Result: data},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// dropConnections 断开所有连接
+func (n *mockWsNode) dropConnections() {
+	n.mu.Lock()
+	conns := make([]*websocket.Conn, 0, len(n.conns))
+	for conn := range n.conns {
+		conns = append(conns, conn)
+	}
+	n.mu.Unlock()
+	for _, conn := range conns {
+		_ = conn.Close()
+	}
+}
+
+func (n *mockWsNode) connections() (current, total int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.conns), n.connectedTotal
+}
+
+func (n *mockWsNode) unsubscribedIds() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]string{}, n.unsubscribed...)
+}
+
+// newMockWebSocketApi 初始化连接模拟websocket节点的客户端，使用较短的重连间隔
+func newMockWebSocketApi(t *testing.T, node *mockWsNode) client.WebSocketApi {
+	api := client.NewWebSocketApi(&client.WebSocketApiInitParam{
+		WebSocketUrl:      node.url(),
+		PingInterval:      time.Second,
+		ReconnectMinDelay: 10 * time.Millisecond,
+		ReconnectMaxDelay: 50 * time.Millisecond,
+	})
+	t.Cleanup(func() { _ = api.Close() })
+	return api
+}
@@ -0,0 +1,175 @@
+package lattice
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/LatticeBCLab/go-lattice/common/constant"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newHealthyMockNode 初始化一个能通过健康检查的模拟节点
+func newHealthyMockNode(t *testing.T, daemonBlockHeight int64) *mockNode {
+	node := newMockNode(t)
+	node.handle("sync_status", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.SyncStatus{}, nil
+	})
+	node.handle("latc_getCurrentDBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return map[string]any{"number": daemonBlockHeight}, nil
+	})
+	node.handle("latc_getReceipt", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.Receipt{}, nil
+	})
+	return node
+}
+
+func newMultiNodeHttpApi(t *testing.T, policy client.LoadBalancePolicy, nodes ...*mockNode) client.MultiNodeHttpApi {
+	params := make([]*client.HttpApiInitParam, len(nodes))
+	for i, node := range nodes {
+		params[i] = &client.HttpApiInitParam{
+			NodeAddress: node.server.Listener.Addr().String(),
+			HttpUrl:     node.server.URL,
+		}
+	}
+	api := client.NewMultiNodeHttpApi(&client.MultiNodeHttpApiInitParam{
+		Nodes:               params,
+		Policy:              policy,
+		HealthCheckInterval: time.Hour,
+		HealthCheckTimeout:  time.Second,
+		MaxDaemonBlockLag:   5,
+	})
+	t.Cleanup(api.Close)
+	api.CheckHealth(context.Background())
+	return api
+}
+
+func TestMultiNodeHttpApi(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("round robin", func(t *testing.T) {
+		nodes := []*mockNode{newHealthyMockNode(t, 100), newHealthyMockNode(t, 100), newHealthyMockNode(t, 100)}
+		api := newMultiNodeHttpApi(t, client.RoundRobin, nodes...)
+		for i := 0; i < 6; i++ {
+			_, err := api.GetReceipt(ctx, chainId, "0x01")
+			require.NoError(t, err)
+		}
+		for _, node := range nodes {
+			assert.Equal(t, 2, node.callCount("latc_getReceipt"))
+		}
+	})
+
+	t.Run("least latency", func(t *testing.T) {
+		slow, fast := newHealthyMockNode(t, 100), newHealthyMockNode(t, 100)
+		slow.handle("latc_getCurrentDBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+			time.Sleep(50 * time.Millisecond)
+			return map[string]any{"number": 100}, nil
+		})
+		api := newMultiNodeHttpApi(t, client.LeastLatency, slow, fast)
+		for i := 0; i < 3; i++ {
+			_, err := api.GetReceipt(ctx, chainId, "0x01")
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 0, slow.callCount("latc_getReceipt"))
+		assert.Equal(t, 3, fast.callCount("latc_getReceipt"))
+	})
+
+	t.Run("lagging node is unhealthy", func(t *testing.T) {
+		lagging, healthy := newHealthyMockNode(t, 90), newHealthyMockNode(t, 100)
+		api := newMultiNodeHttpApi(t, client.RoundRobin, lagging, healthy)
+		statuses := api.NodeStatuses()
+		assert.False(t, statuses[0].Healthy)
+		assert.Equal(t, uint64(90), statuses[0].DaemonBlockHeight)
+		assert.True(t, statuses[1].Healthy)
+		for i := 0; i < 3; i++ {
+			_, err := api.GetReceipt(ctx, chainId, "0x01")
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 0, lagging.callCount("latc_getReceipt"))
+	})
+
+	t.Run("failover", func(t *testing.T) {
+		down, up := newHealthyMockNode(t, 100), newHealthyMockNode(t, 100)
+		api := newMultiNodeHttpApi(t, client.RoundRobin, down, up)
+		down.server.Close()
+		for i := 0; i < 4; i++ {
+			_, err := api.GetReceipt(ctx, chainId, "0x01")
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 4, up.callCount("latc_getReceipt"))
+		assert.False(t, api.NodeStatuses()[0].Healthy)
+
+		api.CheckHealth(ctx)
+		assert.False(t, api.NodeStatuses()[0].Healthy)
+		assert.Error(t, api.NodeStatuses()[0].LastError)
+	})
+
+	t.Run("writes pinned per account", func(t *testing.T) {
+		nodes := []*mockNode{newHealthyMockNode(t, 100), newHealthyMockNode(t, 100), newHealthyMockNode(t, 100)}
+		for _, node := range nodes {
+			node.handle("wallet_sendRawTBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+				return common.HexToHash("0x01"), nil
+			})
+		}
+		api := newMultiNodeHttpApi(t, client.RoundRobin, nodes...)
+		tx := &block.Transaction{Owner: "zltc_owner", Amount: big.NewInt(0), Joule: big.NewInt(0)}
+		for i := 0; i < 5; i++ {
+			_, err := api.SendSignedTransaction(ctx, chainId, tx)
+			require.NoError(t, err)
+			_, err = api.GetReceipt(ctx, chainId, "0x01")
+			require.NoError(t, err)
+		}
+		pinnedIndex := -1
+		for i, node := range nodes {
+			if node.callCount("wallet_sendRawTBlock") > 0 {
+				assert.Equal(t, -1, pinnedIndex, "同一账户的交易被发送到了多个节点")
+				assert.Equal(t, 5, node.callCount("wallet_sendRawTBlock"))
+				pinnedIndex = i
+			}
+		}
+		require.NotEqual(t, -1, pinnedIndex)
+
+		// 绑定的节点宕机后，交易发送失败且不重试，下一笔交易绑定到其他节点
+		nodes[pinnedIndex].server.Close()
+		_, err := api.SendSignedTransaction(ctx, chainId, tx)
+		assert.Error(t, err)
+		_, err = api.SendSignedTransaction(ctx, chainId, tx)
+		assert.NoError(t, err)
+	})
+}
+
+func TestMultiNodeLattice(t *testing.T) {
+	down, up := newHealthyMockNode(t, 100), newHealthyMockNode(t, 100)
+	for _, node := range []*mockNode{down, up} {
+		node.handle("latc_getCurrentTBDB", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+			return &types.LatestBlock{Height: 1, Hash: common.HexToHash("0x01")}, nil
+		})
+		node.handle("wallet_sendRawTBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+			return common.HexToHash("0x02"), nil
+		})
+	}
+	down.server.Close()
+
+	svc := NewMultiNodeLattice(
+		&ChainConfig{Curve: types.Sm2p256v1, TokenLess: true},
+		[]*ConnectingNodeConfig{down.connectingNodeConfig(t), up.connectingNodeConfig(t)},
+		nil,
+		nil,
+		&Options{HealthCheckInterval: time.Hour},
+	)
+	t.Cleanup(svc.Close)
+	api := svc.HttpApi().(client.MultiNodeHttpApi)
+	api.CheckHealth(context.Background())
+
+	hash, err := svc.Transfer(context.Background(), newTestCredentials(t), chainId, constant.ZeroAddress, constant.ZeroPayload, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToHash("0x02"), *hash)
+	assert.Equal(t, 1, up.callCount("wallet_sendRawTBlock"))
+}
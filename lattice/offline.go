@@ -0,0 +1,150 @@
+package lattice
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+)
+
+// newUnsignedTx 基于账户的最新区块构造未签名的交易，开启工作量证明时计算工作量证明，并计算待签名哈希
+//
+// Parameters:
+//   - ctx context.Context
+//   - credentials *Credentials: 只使用 AccountAddress
+//   - chainId string
+//   - req *TxRequest
+//
+// Returns:
+//   - *block.Transaction: 未签名的交易
+//   - common.Hash: 待签名哈希
+//   - error
+func (svc *lattice) newUnsignedTx(ctx context.Context, credentials *Credentials, chainId string, req *TxRequest) (*block.Transaction, common.Hash, error) {
+	chainIdAsInt, err := strconv.ParseUint(chainId, 10, 64)
+	if err != nil {
+		log.Error().Err(err)
+		return nil, common.Hash{}, err
+	}
+	difficulty, err := svc.powDifficulty(ctx, chainId)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+
+	if err := svc.obtainAccountLock(ctx, chainId, credentials.AccountAddress); err != nil {
+		return nil, common.Hash{}, err
+	}
+	defer svc.accountLock.Unlock(chainId, credentials.AccountAddress)
+
+	latestBlock, err := svc.blockCache.GetBlock(chainId, credentials.AccountAddress)
+	if err != nil {
+		log.Error().Err(err)
+		return nil, common.Hash{}, err
+	}
+
+	unsignedTx := svc.buildTransaction(latestBlock, credentials.AccountAddress, req)
+	// 工作量证明包含在待签名哈希中，需要在计算待签名哈希之前计算
+	if err := svc.solveProofOfWork(ctx, chainIdAsInt, unsignedTx, difficulty); err != nil {
+		log.Error().Err(err)
+		return nil, common.Hash{}, err
+	}
+	unsignedHash, err := unsignedTx.RlpEncodeHash(chainIdAsInt, svc.chainConfig.Curve)
+	if err != nil {
+		log.Error().Err(err)
+		return nil, common.Hash{}, err
+	}
+	return unsignedTx, unsignedHash, nil
+}
+
+func (svc *lattice) NewTransferTx(ctx context.Context, credentials *Credentials, chainId, linker, payload string, amount, joule uint64) (unsignedTx *block.Transaction, unsignedHash common.Hash, err error) {
+	log.Debug().Msgf("开始构造转账交易，chainId: %s, linker: %s, payload: %s, amount: %d, joule: %d", chainId, linker, payload, amount, joule)
+	return svc.newUnsignedTx(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeSend, Linker: linker, Payload: payload, Amount: amount, Joule: joule})
+}
+
+func (svc *lattice) NewUpgradeContractTx(ctx context.Context, credentials *Credentials, chainId, contractAddress, data, payload string, amount, joule uint64) (unsignedTx *block.Transaction, unsignedHash common.Hash, err error) {
+	log.Debug().Msgf("开始构造升级合约交易，chainId: %s, contractAddress: %s, data: %s, payload: %s, amount: %d, joule: %d", chainId, contractAddress, data, payload, amount, joule)
+	return svc.newUnsignedTx(ctx, credentials, chainId, &TxRequest{Type: block.TransactionTypeUpgradeContract, Linker: contractAddress, Code: data, Payload: payload, Amount: amount, Joule: joule})
+}
+
+func (svc *lattice) NewDeployMultilingualContractTx(ctx context.Context, credentials *Credentials, chainId string, lang types.ContractLang, data types.DeployMultilingualContractCode, payload string, amount, joule uint64) (unsignedTx *block.Transaction, unsignedHash common.Hash, err error) {
+	log.Debug().Msgf("开始构造部署%s合约交易，chainId: %s, data: %+v, payload: %s, amount: %d, joule: %d", lang, chainId, data, payload, amount, joule)
+	transactionType, err := multilingualTransactionType(lang, block.TransactionTypeDeployGoContract, block.TransactionTypeDeployJavaContract)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	return svc.newUnsignedTx(ctx, credentials, chainId, &TxRequest{Type: transactionType, Code: data.Encode(), Payload: payload, Amount: amount, Joule: joule})
+}
+
+func (svc *lattice) NewUpgradeMultilingualContractTx(ctx context.Context, credentials *Credentials, chainId, contractAddress string, lang types.ContractLang, data types.UpgradeMultilingualContractCode, payload string, amount, joule uint64) (unsignedTx *block.Transaction, unsignedHash common.Hash, err error) {
+	log.Debug().Msgf("开始构造升级%s合约交易，chainId: %s, contractAddress: %s, data: %+v, payload: %s, amount: %d, joule: %d", lang, chainId, contractAddress, data, payload, amount, joule)
+	transactionType, err := multilingualTransactionType(lang, block.TransactionTypeUpgradeGoContract, block.TransactionTypeUpgradeJavaContract)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	return svc.newUnsignedTx(ctx, credentials, chainId, &TxRequest{Type: transactionType, Linker: contractAddress, Code: data.Encode(), Payload: payload, Amount: amount, Joule: joule})
+}
+
+func (svc *lattice) NewCallMultilingualContractTx(ctx context.Context, credentials *Credentials, chainId, contractAddress string, lang types.ContractLang, data types.CallMultilingualContractCode, payload string, amount, joule uint64) (unsignedTx *block.Transaction, unsignedHash common.Hash, err error) {
+	log.Debug().Msgf("开始构造调用%s合约交易，chainId: %s, contractAddress: %s, data: %+v, payload: %s, amount: %d, joule: %d", lang, chainId, contractAddress, data, payload, amount, joule)
+	transactionType, err := multilingualTransactionType(lang, block.TransactionTypeCallGoContract, block.TransactionTypeCallJavaContract)
+	if err != nil {
+		return nil, common.Hash{}, err
+	}
+	return svc.newUnsignedTx(ctx, credentials, chainId, &TxRequest{Type: transactionType, Linker: contractAddress, Code: data.Encode(), Payload: payload, Amount: amount, Joule: joule})
+}
+
+func (svc *lattice) NewTransactionEnvelope(chainId string, unsignedTx *block.Transaction) (*block.TransactionEnvelope, error) {
+	chainIdAsInt, err := strconv.ParseUint(chainId, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return block.NewTransactionEnvelope(chainIdAsInt, svc.chainConfig.Curve, unsignedTx)
+}
+
+func (svc *lattice) SendOfflineSignedTransaction(ctx context.Context, envelope *block.TransactionEnvelope) (*common.Hash, error) {
+	if envelope.Curve != svc.chainConfig.Curve {
+		return nil, fmt.Errorf("交易信封的曲线%s与链配置的曲线%s不一致", envelope.Curve, svc.chainConfig.Curve)
+	}
+	if _, err := envelope.Verify(); err != nil {
+		log.Error().Err(err).Msgf("离线签名的交易校验失败，hash: %s", envelope.Hash)
+		return nil, fmt.Errorf("离线签名的交易校验失败：%w", err)
+	}
+
+	chainId := strconv.FormatUint(envelope.ChainId, 10)
+	tx := envelope.Transaction
+	if err := svc.obtainAccountLock(ctx, chainId, tx.Owner); err != nil {
+		return nil, err
+	}
+	defer svc.accountLock.Unlock(chainId, tx.Owner)
+
+	if err := svc.checkAccountLockLease(chainId, tx.Owner); err != nil {
+		return nil, err
+	}
+	cancelCtx, cancelFunc := context.WithTimeout(ctx, defaultHttpRequestTimeout)
+	defer cancelFunc()
+	hash, err := svc.httpApi.SendSignedTransaction(cancelCtx, chainId, tx)
+	if err != nil {
+		log.Error().Err(err).Msgf("发送离线签名的交易失败，hash: %s", envelope.Hash)
+		if isBlockMismatchError(err) {
+			if err := svc.blockCache.Invalidate(chainId, tx.Owner); err != nil {
+				log.Error().Err(err)
+			}
+		}
+		return nil, err
+	}
+
+	// 交易基于缓存中的最新区块构造时，推进缓存，避免下一笔交易使用相同的高度
+	latestBlock, err := svc.blockCache.GetBlock(chainId, tx.Owner)
+	if err == nil && latestBlock.Height+1 == tx.Height && latestBlock.Hash == tx.ParentHash {
+		latestBlock.Hash = *hash
+		latestBlock.IncrHeight()
+		if err := svc.blockCache.SetBlock(chainId, tx.Owner, latestBlock); err != nil {
+			log.Error().Err(err)
+		}
+	}
+	log.Debug().Msgf("结束发送离线签名的交易，哈希为：%s", hash.String())
+	return hash, nil
+}
@@ -0,0 +1,119 @@
+package lattice
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/LatticeBCLab/go-lattice/common/constant"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfflineSignedTransaction(t *testing.T) {
+	node := newMockNode(t)
+	node.handle("latc_getCurrentTBDB", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.LatestBlock{Height: 3, Hash: common.HexToHash("0x03"), DaemonBlockHash: common.HexToHash("0xd3")}, nil
+	})
+	node.handle("wallet_sendRawTBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return common.HexToHash("0x04"), nil
+	})
+	svc := newMockLattice(t, node, nil)
+	credentials := newTestCredentials(t)
+	// 联网环境只需要账户地址
+	online := &Credentials{AccountAddress: credentials.AccountAddress}
+
+	newEnvelope := func(t *testing.T) *block.TransactionEnvelope {
+		unsignedTx, unsignedHash, err := svc.NewTransferTx(context.Background(), online, chainId, constant.ZeroAddress, constant.ZeroPayload, 10, 0)
+		require.NoError(t, err)
+		envelope, err := svc.NewTransactionEnvelope(chainId, unsignedTx)
+		require.NoError(t, err)
+		assert.Equal(t, unsignedHash, envelope.Hash)
+		return envelope
+	}
+
+	t.Run("json round trip", func(t *testing.T) {
+		b, err := json.Marshal(newEnvelope(t))
+		require.NoError(t, err)
+		envelope, err := block.UnmarshalTransactionEnvelopeJSON(b)
+		require.NoError(t, err)
+		require.NoError(t, envelope.Sign(credentials.PrivateKey))
+
+		b, err = json.Marshal(envelope)
+		require.NoError(t, err)
+		signed, err := block.UnmarshalTransactionEnvelopeJSON(b)
+		require.NoError(t, err)
+		hash, err := svc.SendOfflineSignedTransaction(context.Background(), signed)
+		require.NoError(t, err)
+		assert.Equal(t, common.HexToHash("0x04"), *hash)
+
+		// 发送后推进缓存
+		cached, err := svc.blockCache.GetBlock(chainId, credentials.AccountAddress)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(4), cached.Height)
+		assert.Equal(t, common.HexToHash("0x04"), cached.Hash)
+		require.NoError(t, svc.blockCache.Invalidate(chainId, credentials.AccountAddress))
+	})
+
+	t.Run("rlp round trip", func(t *testing.T) {
+		b, err := newEnvelope(t).MarshalRLP()
+		require.NoError(t, err)
+		envelope, err := block.UnmarshalTransactionEnvelopeRLP(b)
+		require.NoError(t, err)
+		require.NoError(t, envelope.CheckHash())
+		require.NoError(t, envelope.Sign(credentials.PrivateKey))
+
+		b, err = envelope.MarshalRLP()
+		require.NoError(t, err)
+		signed, err := block.UnmarshalTransactionEnvelopeRLP(b)
+		require.NoError(t, err)
+		signer, err := signed.Verify()
+		require.NoError(t, err)
+		assert.Equal(t, signed.Transaction.GetOwnerAddress(), signer)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		envelope := newEnvelope(t)
+		envelope.Transaction.Linker = credentials.AccountAddress
+		assert.ErrorIs(t, envelope.Sign(credentials.PrivateKey), block.ErrEnvelopeHashMismatch)
+
+		envelope = newEnvelope(t)
+		require.NoError(t, envelope.Sign(credentials.PrivateKey))
+		envelope.Transaction.Amount.SetUint64(1000)
+		_, err := svc.SendOfflineSignedTransaction(context.Background(), envelope)
+		assert.ErrorIs(t, err, block.ErrEnvelopeHashMismatch)
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		for _, payload := range []string{"abc", "0x123"} {
+			envelope := newEnvelope(t)
+			envelope.Transaction.Payload = payload
+			assert.ErrorIs(t, envelope.Sign(credentials.PrivateKey), block.ErrTransactionPayloadInvalid)
+			_, err := svc.SendOfflineSignedTransaction(context.Background(), envelope)
+			assert.ErrorIs(t, err, block.ErrTransactionPayloadInvalid)
+
+			b, err := json.Marshal(envelope)
+			require.NoError(t, err)
+			_, err = block.UnmarshalTransactionEnvelopeJSON(b)
+			assert.ErrorIs(t, err, block.ErrTransactionPayloadInvalid)
+			b, err = envelope.MarshalRLP()
+			require.NoError(t, err)
+			_, err = block.UnmarshalTransactionEnvelopeRLP(b)
+			assert.ErrorIs(t, err, block.ErrTransactionPayloadInvalid)
+		}
+	})
+
+	t.Run("unsigned or wrong signer", func(t *testing.T) {
+		envelope := newEnvelope(t)
+		_, err := svc.SendOfflineSignedTransaction(context.Background(), envelope)
+		assert.ErrorIs(t, err, block.ErrEnvelopeNotSigned)
+
+		require.NoError(t, envelope.Sign(newTestCredentials(t).PrivateKey))
+		_, err = svc.SendOfflineSignedTransaction(context.Background(), envelope)
+		assert.ErrorIs(t, err, block.ErrEnvelopeSignerMismatch)
+	})
+}
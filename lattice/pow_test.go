@@ -0,0 +1,124 @@
+package lattice
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/LatticeBCLab/go-lattice/common/constant"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProofOfWork(t *testing.T) {
+	const difficulty = 64
+
+	t.Run("solve and verify", func(t *testing.T) {
+		tx := block.NewTransactionBuilder(block.TransactionTypeSend).
+			SetLatestBlock(&types.LatestBlock{Height: 1, Hash: common.HexToHash("0x01")}).
+			SetOwner(constant.ZeroAddress).
+			SetLinker(constant.ZeroAddress).
+			SetPayload(constant.ZeroPayload).
+			SetAmount(0).
+			SetJoule(0).
+			Build()
+		require.NoError(t, tx.SolvePoW(context.Background(), 1, types.Sm2p256v1, difficulty, 4))
+		assert.Equal(t, uint64(difficulty), tx.Difficulty)
+		ok, err := tx.VerifyPoW(1, types.Sm2p256v1)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("context cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		tx := block.NewTransactionBuilder(block.TransactionTypeSend).SetPayload(constant.ZeroPayload).SetAmount(0).SetJoule(0).Build()
+		err := tx.SolvePoW(ctx, 1, types.Sm2p256v1, 1<<62, 2)
+		assert.ErrorIs(t, err, context.Canceled)
+
+		_, err = block.NewTransactionBuilder(block.TransactionTypeSend).SetPayload(constant.ZeroPayload).
+			BuildWithPoW(ctx, 1, types.Sm2p256v1, 1<<62, 2)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("submit on pow chain", func(t *testing.T) {
+		node := newMockNode(t)
+		node.handle("latc_getCurrentTBDB", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+			return &types.LatestBlock{Height: 3, Hash: common.HexToHash("0x03")}, nil
+		})
+		node.handle("latc_getCurrentDBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+			return &types.DaemonBlock{Height: big.NewInt(10), Difficulty: big.NewInt(difficulty)}, nil
+		})
+		var sent *block.Transaction
+		node.handle("wallet_sendRawTBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+			sent = &block.Transaction{}
+			_ = json.Unmarshal(params[0], sent)
+			return common.HexToHash("0x04"), nil
+		})
+		credentials := newTestCredentials(t)
+
+		svc := newMockLattice(t, node, &Options{EnableProofOfWork: true, ProofOfWorkWorkers: 2})
+		_, err := svc.Transfer(context.Background(), credentials, chainId, constant.ZeroAddress, constant.ZeroPayload, 0, 0)
+		require.NoError(t, err)
+		require.NotNil(t, sent)
+		assert.Equal(t, uint64(difficulty), sent.Difficulty)
+		ok, err := sent.VerifyPoW(1, types.Sm2p256v1)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		sent = nil
+		svc = newMockLattice(t, node, nil)
+		_, err = svc.Transfer(context.Background(), credentials, chainId, constant.ZeroAddress, constant.ZeroPayload, 0, 0)
+		require.NoError(t, err)
+		require.NotNil(t, sent)
+		assert.Zero(t, sent.Difficulty)
+	})
+
+	t.Run("batch and offline on pow chain", func(t *testing.T) {
+		node := newMockNode(t)
+		node.handle("latc_getCurrentTBDB", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+			return &types.LatestBlock{Height: 3, Hash: common.HexToHash("0x03")}, nil
+		})
+		node.handle("latc_getCurrentDBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+			return &types.DaemonBlock{Height: big.NewInt(10), Difficulty: big.NewInt(difficulty)}, nil
+		})
+		var sent []*block.Transaction
+		node.handle("wallet_sendRawBatchTBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+			_ = json.Unmarshal(params[0], &sent)
+			hashes := make([]*common.Hash, len(sent))
+			for i, tx := range sent {
+				hash, _ := tx.CalculateTransactionHash(types.Sm2p256v1)
+				hashes[i] = &hash
+			}
+			return hashes, nil
+		})
+		credentials := newTestCredentials(t)
+		svc := newMockLattice(t, node, &Options{EnableProofOfWork: true, ProofOfWorkWorkers: 2})
+
+		req := &TxRequest{Type: block.TransactionTypeSend, Linker: constant.ZeroAddress, Payload: constant.ZeroPayload}
+		_, err := svc.SubmitBatch(context.Background(), credentials, chainId, []*TxRequest{req, req, req})
+		require.NoError(t, err)
+		require.Len(t, sent, 3)
+		for _, tx := range sent {
+			ok, err := tx.VerifyPoW(1, types.Sm2p256v1)
+			require.NoError(t, err)
+			assert.True(t, ok)
+		}
+		assert.Equal(t, 1, node.callCount("latc_getCurrentDBlock"))
+
+		unsignedTx, unsignedHash, err := svc.NewTransferTx(context.Background(), credentials, chainId, constant.ZeroAddress, constant.ZeroPayload, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(difficulty), unsignedTx.Difficulty)
+		ok, err := unsignedTx.VerifyPoW(1, types.Sm2p256v1)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		envelope, err := svc.NewTransactionEnvelope(chainId, unsignedTx)
+		require.NoError(t, err)
+		assert.Equal(t, unsignedHash, envelope.Hash)
+	})
+}
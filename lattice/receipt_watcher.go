@@ -0,0 +1,272 @@
+package lattice
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+	"github.com/samber/lo"
+)
+
+const (
+	defaultReceiptWaitTimeout         = time.Minute
+	defaultReceiptPollInterval        = time.Second
+	defaultReceiptMaxCheckInterval    = 10 * time.Second
+	defaultReceiptResubscribeInterval = 10 * time.Second
+	defaultReceiptBatchSize           = 100
+)
+
+// ReceiptWatcherConfig 回执监听器的配置
+type ReceiptWatcherConfig struct {
+	Timeout             time.Duration // *WaitReceipt 等待回执的最长时长，默认为1分钟
+	PollInterval        time.Duration // 无法订阅守护区块时轮询回执的间隔，默认为1秒
+	MaxCheckInterval    time.Duration // 订阅正常时，超过该时长未收到守护区块也会查询一次回执，默认为10秒
+	ResubscribeInterval time.Duration // 订阅守护区块失败后重新订阅的间隔，默认为10秒
+	BatchSize           int           // 每次批量查询回执的交易数量，默认为100
+}
+
+// ReceiptWatcher 通过websocket订阅守护区块等待交易回执，每产生一个守护区块时批量查询所有等待中的交易的回执，
+// 无法订阅时退化为按固定间隔轮询，没有等待中的交易时自动取消订阅
+type ReceiptWatcher interface {
+	// Wait 等待交易回执，直到查询到上链的回执或ctx取消
+	//
+	// Parameters:
+	//   - ctx context.Context
+	//   - chainId string
+	//   - hash common.Hash: 交易哈希
+	//
+	// Returns:
+	//   - *types.Receipt
+	//   - error
+	Wait(ctx context.Context, chainId string, hash common.Hash) (*types.Receipt, error)
+}
+
+// NewReceiptWatcher 初始化回执监听器
+//
+// Parameters:
+//   - httpApi client.HttpApi: 批量查询回执
+//   - websocketApi client.WebSocketApi: 订阅守护区块，为nil时只轮询
+//   - config *ReceiptWatcherConfig: 为nil时使用默认配置
+//
+// Returns:
+//   - ReceiptWatcher
+func NewReceiptWatcher(httpApi client.HttpApi, websocketApi client.WebSocketApi, config *ReceiptWatcherConfig) ReceiptWatcher {
+	if config == nil {
+		config = &ReceiptWatcherConfig{}
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultReceiptWaitTimeout
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = defaultReceiptPollInterval
+	}
+	if config.MaxCheckInterval <= 0 {
+		config.MaxCheckInterval = defaultReceiptMaxCheckInterval
+	}
+	if config.ResubscribeInterval <= 0 {
+		config.ResubscribeInterval = defaultReceiptResubscribeInterval
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultReceiptBatchSize
+	}
+	return &receiptWatcher{
+		httpApi:      httpApi,
+		websocketApi: websocketApi,
+		config:       config,
+		pending:      make(map[string]map[common.Hash][]chan *types.Receipt),
+		kick:         make(chan struct{}, 1),
+	}
+}
+
+type receiptWatcher struct {
+	httpApi      client.HttpApi
+	websocketApi client.WebSocketApi
+	config       *ReceiptWatcherConfig
+
+	mu      sync.Mutex
+	running bool                                             // 监听循环是否在运行
+	pending map[string]map[common.Hash][]chan *types.Receipt // key: chainId -> 交易哈希
+	kick    chan struct{}                                    // 有新的等待时触发一次查询
+}
+
+func (w *receiptWatcher) Wait(ctx context.Context, chainId string, hash common.Hash) (*types.Receipt, error) {
+	ch := make(chan *types.Receipt, 1)
+	w.add(chainId, hash, ch)
+	defer w.remove(chainId, hash, ch)
+
+	select {
+	case receipt := <-ch:
+		return receipt, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// add 登记等待的交易，监听循环未运行时启动
+func (w *receiptWatcher) add(chainId string, hash common.Hash, ch chan *types.Receipt) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	hashes, ok := w.pending[chainId]
+	if !ok {
+		hashes = make(map[common.Hash][]chan *types.Receipt)
+		w.pending[chainId] = hashes
+	}
+	hashes[hash] = append(hashes[hash], ch)
+	if !w.running {
+		w.running = true
+		go w.run()
+	}
+	w.notify()
+}
+
+func (w *receiptWatcher) remove(chainId string, hash common.Hash, ch chan *types.Receipt) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	hashes := w.pending[chainId]
+	waiters := lo.Without(hashes[hash], ch)
+	if len(waiters) == 0 {
+		delete(hashes, hash)
+	} else {
+		hashes[hash] = waiters
+	}
+	if len(hashes) == 0 {
+		delete(w.pending, chainId)
+	}
+	if len(w.pending) == 0 {
+		// 唤醒监听循环，使其取消订阅并退出
+		w.notify()
+	}
+}
+
+// notify 唤醒监听循环查询一次回执
+func (w *receiptWatcher) notify() {
+	select {
+	case w.kick <- struct{}{}:
+	default:
+	}
+}
+
+// resolve 将回执交给等待该交易的所有调用方
+func (w *receiptWatcher) resolve(chainId string, hash common.Hash, receipt *types.Receipt) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	hashes := w.pending[chainId]
+	for _, ch := range hashes[hash] {
+		ch <- receipt
+	}
+	delete(hashes, hash)
+	if len(hashes) == 0 {
+		delete(w.pending, chainId)
+	}
+}
+
+// stopIfIdle 没有等待的交易时标记监听循环停止
+func (w *receiptWatcher) stopIfIdle() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.pending) == 0 {
+		w.running = false
+		return true
+	}
+	return false
+}
+
+// snapshot 获取所有等待中的交易哈希，没有等待的交易时停止监听循环
+func (w *receiptWatcher) snapshot() (map[string][]common.Hash, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.pending) == 0 {
+		w.running = false
+		return nil, false
+	}
+	snapshot := make(map[string][]common.Hash, len(w.pending))
+	for chainId, hashes := range w.pending {
+		snapshot[chainId] = lo.Keys(hashes)
+	}
+	return snapshot, true
+}
+
+// run 订阅守护区块，每收到一个守护区块或轮询间隔到期时查询回执，没有等待的交易时退出
+func (w *receiptWatcher) run() {
+	var sub client.Subscribe[types.DaemonBlock]
+	var blocks <-chan types.DaemonBlock
+	var subscribedAt, checkedAt time.Time
+	defer func() {
+		if sub != nil {
+			_ = sub.Close()
+		}
+	}()
+
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+	for {
+		if sub == nil && w.websocketApi != nil && time.Since(subscribedAt) >= w.config.ResubscribeInterval {
+			subscribedAt = time.Now()
+			var err error
+			if sub, err = w.subscribe(); err != nil {
+				log.Warn().Err(err).Msgf("订阅守护区块失败，每%s轮询一次回执", w.config.PollInterval)
+			} else {
+				blocks = sub.Chan()
+			}
+		}
+
+		select {
+		case _, ok := <-blocks:
+			if !ok {
+				log.Warn().Err(sub.Err()).Msg("守护区块的订阅已结束，改为轮询回执")
+				sub, blocks = nil, nil
+				continue
+			}
+		case <-ticker.C:
+			if sub != nil && time.Since(checkedAt) < w.config.MaxCheckInterval {
+				continue
+			}
+		case <-w.kick:
+		}
+
+		snapshot, ok := w.snapshot()
+		if !ok {
+			return
+		}
+		checkedAt = time.Now()
+		for chainId, hashes := range snapshot {
+			w.check(chainId, hashes)
+		}
+		if w.stopIfIdle() {
+			return
+		}
+	}
+}
+
+func (w *receiptWatcher) subscribe() (client.Subscribe[types.DaemonBlock], error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultHttpRequestTimeout)
+	defer cancel()
+	return w.websocketApi.DaemonBlocks(ctx)
+}
+
+// check 分批查询回执，已上链的回执交给等待的调用方
+func (w *receiptWatcher) check(chainId string, hashes []common.Hash) {
+	for _, chunk := range lo.Chunk(hashes, w.config.BatchSize) {
+		ctx, cancel := context.WithTimeout(context.Background(), defaultHttpRequestTimeout)
+		receipts, err := w.httpApi.GetReceipts(ctx, chainId, lo.Map(chunk, func(hash common.Hash, _ int) string { return hash.String() }))
+		cancel()
+		if err != nil {
+			log.Warn().Err(err).Msgf("批量查询回执失败，chainId: %s, 交易数量: %d", chainId, len(chunk))
+			continue
+		}
+		for i, receipt := range receipts {
+			if receipt == nil || receipt.DBlockNumber == 0 {
+				continue
+			}
+			hash := receipt.TBlockHash
+			if hash == (common.Hash{}) && len(receipts) == len(chunk) {
+				hash = chunk[i]
+			}
+			w.resolve(chainId, hash, receipt)
+		}
+	}
+}
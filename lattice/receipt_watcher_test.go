@@ -0,0 +1,130 @@
+package lattice
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockReceipts 模拟节点上已上链的回执
+type mockReceipts struct {
+	mu        sync.Mutex
+	confirmed map[common.Hash]bool
+}
+
+func (m *mockReceipts) confirm(hashes ...common.Hash) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, hash := range hashes {
+		m.confirmed[hash] = true
+	}
+}
+
+// handleReceipts 注册 latc_getTBlockReceipts，只返回已上链的回执
+func handleReceipts(node *mockNode) *mockReceipts {
+	receipts := &mockReceipts{confirmed: make(map[common.Hash]bool)}
+	node.handle("latc_getTBlockReceipts", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		var hashes []common.Hash
+		_ = json.Unmarshal(params[0], &hashes)
+		receipts.mu.Lock()
+		defer receipts.mu.Unlock()
+		result := make([]*types.Receipt, 0, len(hashes))
+		for _, hash := range hashes {
+			if receipts.confirmed[hash] {
+				result = append(result, &types.Receipt{TBlockHash: hash, DBlockNumber: 10, Success: true})
+			}
+		}
+		return result, nil
+	})
+	return receipts
+}
+
+func TestReceiptWatcher(t *testing.T) {
+	hashes := []common.Hash{common.HexToHash("0x01"), common.HexToHash("0x02"), common.HexToHash("0x03")}
+
+	t.Run("resolve on new daemon block", func(t *testing.T) {
+		node := newMockNode(t)
+		receipts := handleReceipts(node)
+		wsNode := newMockWsNode(t)
+		httpApi := client.NewHttpApi(&client.HttpApiInitParam{HttpUrl: node.server.URL})
+		watcher := NewReceiptWatcher(httpApi, newMockWebSocketApi(t, wsNode), &ReceiptWatcherConfig{PollInterval: time.Hour, MaxCheckInterval: time.Hour})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		var wg sync.WaitGroup
+		results := make([]*types.Receipt, len(hashes))
+		for i, hash := range hashes {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				receipt, err := watcher.Wait(ctx, chainId, hash)
+				assert.NoError(t, err)
+				results[i] = receipt
+			}()
+		}
+		ids := wsNode.waitSubscriptions(t, 1)
+		require.Len(t, ids, 1)
+		assert.Equal(t, "newDBlock", wsNode.subscriptionName(ids[0]))
+		callsBeforeBlock := node.callCount("latc_getTBlockReceipts")
+
+		receipts.confirm(hashes...)
+		wsNode.notify(t, ids[0], &types.DaemonBlock{Hash: common.HexToHash("0xd1")})
+		wg.Wait()
+		for i, hash := range hashes {
+			require.NotNil(t, results[i])
+			assert.Equal(t, hash, results[i].TBlockHash)
+		}
+		// 一个守护区块只批量查询一次回执
+		assert.Equal(t, callsBeforeBlock+1, node.callCount("latc_getTBlockReceipts"))
+		// 没有等待的交易后取消订阅
+		assert.Eventually(t, func() bool { return len(wsNode.subscriptionIds()) == 0 }, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("fall back to polling", func(t *testing.T) {
+		node := newMockNode(t)
+		receipts := handleReceipts(node)
+		httpApi := client.NewHttpApi(&client.HttpApiInitParam{HttpUrl: node.server.URL})
+		// 模拟节点不支持websocket，订阅失败
+		wsApi := client.NewWebSocketApi(&client.WebSocketApiInitParam{WebSocketUrl: "ws" + node.server.URL[len("http"):]})
+		t.Cleanup(func() { _ = wsApi.Close() })
+		watcher := NewReceiptWatcher(httpApi, wsApi, &ReceiptWatcherConfig{PollInterval: 10 * time.Millisecond})
+
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			receipts.confirm(hashes[0])
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		receipt, err := watcher.Wait(ctx, chainId, hashes[0])
+		require.NoError(t, err)
+		assert.Equal(t, hashes[0], receipt.TBlockHash)
+
+		ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = watcher.Wait(ctx, chainId, hashes[1])
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("wait receipt with watcher", func(t *testing.T) {
+		node := newMockNode(t)
+		receipts := handleReceipts(node)
+		receipts.confirm(hashes[0])
+		svc := newMockLattice(t, node, &Options{ReceiptWatcher: &ReceiptWatcherConfig{PollInterval: 10 * time.Millisecond, Timeout: 100 * time.Millisecond}})
+
+		_, receipt, err := svc.WaitReceipt(context.Background(), chainId, &hashes[0], nil)
+		require.NoError(t, err)
+		assert.Equal(t, hashes[0], receipt.TBlockHash)
+		assert.Equal(t, 0, node.callCount("latc_getReceipt"))
+
+		_, _, err = svc.WaitReceipt(context.Background(), chainId, &hashes[1], nil)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,112 @@
+package lattice
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+)
+
+// isBlockMismatchError 判断节点返回的错误是否为父哈希或高度不匹配
+//
+// Parameters:
+//   - err error: 发送交易时节点返回的错误
+//
+// Returns:
+//   - bool
+func isBlockMismatchError(err error) bool {
+	return errors.Is(err, client.ErrParentHashMismatch)
+}
+
+// resyncBlock 清除账户的区块缓存，并通过 GetLatestBlockWithPending 从节点获取最新区块后重新写入缓存
+//
+// Parameters:
+//   - ctx context.Context
+//   - chainId string: 链ID
+//   - address string: 账户地址
+//
+// Returns:
+//   - *types.LatestBlock: 节点上包括pending交易在内的最新区块
+//   - error
+func (svc *lattice) resyncBlock(ctx context.Context, chainId, address string) (*types.LatestBlock, error) {
+	if err := svc.blockCache.Invalidate(chainId, address); err != nil {
+		log.Error().Err(err).Msgf("清除区块缓存失败，chainId: %s, accountAddress: %s", chainId, address)
+	}
+
+	cancelCtx, cancelFunc := context.WithTimeout(ctx, defaultHttpRequestTimeout)
+	defer cancelFunc()
+	latestBlock, err := svc.httpApi.GetLatestBlockWithPending(cancelCtx, chainId, address)
+	if err != nil {
+		log.Error().Err(err).Msgf("重新同步最新区块失败，chainId: %s, accountAddress: %s", chainId, address)
+		return nil, err
+	}
+	if err := svc.blockCache.SetBlock(chainId, address, latestBlock); err != nil {
+		log.Error().Err(err)
+	}
+	log.Debug().Msgf("已重新同步账户区块，chainId: %s, accountAddress: %s, height: %d", chainId, address, latestBlock.Height)
+	return latestBlock, nil
+}
+
+// recoverTransaction 交易发送失败后修复区块缓存，若错误为父哈希或高度不匹配且开启了 Options.ResendOnBlockMismatch，
+// 则基于最新区块重新签名并发送一次交易，调用方需持有账户锁
+//
+// Parameters:
+//   - ctx context.Context
+//   - credentials *Credentials: 发交易的身份凭证
+//   - chainId string: 链ID
+//   - transaction *block.Transaction: 发送失败的交易
+//   - difficulty uint64: 重新签名时工作量证明的难度
+//   - sendErr error: 发送交易时节点返回的错误
+//
+// Returns:
+//   - *common.Hash: 重新发送成功后的交易哈希
+//   - error: 未重新发送时返回 sendErr
+func (svc *lattice) recoverTransaction(ctx context.Context, credentials *Credentials, chainId string, transaction *block.Transaction, difficulty uint64, sendErr error) (*common.Hash, error) {
+	latestBlock, err := svc.resyncBlock(ctx, chainId, credentials.AccountAddress)
+	if err != nil {
+		return nil, sendErr
+	}
+	if !svc.options.ResendOnBlockMismatch || !isBlockMismatchError(sendErr) {
+		return nil, sendErr
+	}
+
+	log.Warn().Err(sendErr).Msgf("交易的父哈希或高度与链上不一致，重新签名并发送交易，chainId: %s, accountAddress: %s", chainId, credentials.AccountAddress)
+	transaction.Height = latestBlock.Height + 1
+	transaction.ParentHash = latestBlock.Hash
+	transaction.DaemonHash = latestBlock.DaemonBlockHash
+
+	chainIdAsInt, err := strconv.Atoi(chainId)
+	if err != nil {
+		log.Error().Err(err)
+		return nil, err
+	}
+	if err = svc.signTransaction(ctx, credentials, uint64(chainIdAsInt), transaction, difficulty); err != nil {
+		log.Error().Err(err)
+		return nil, err
+	}
+	if err := svc.checkAccountLockLease(chainId, credentials.AccountAddress); err != nil {
+		return nil, err
+	}
+
+	cancelCtx, cancelFunc := context.WithTimeout(ctx, defaultHttpRequestTimeout)
+	defer cancelFunc()
+	hash, err := svc.httpApi.SendSignedTransaction(cancelCtx, chainId, transaction)
+	if err != nil {
+		log.Error().Err(err)
+		if _, resyncErr := svc.resyncBlock(ctx, chainId, credentials.AccountAddress); resyncErr != nil {
+			log.Error().Err(resyncErr)
+		}
+		return nil, err
+	}
+	latestBlock.Hash = *hash
+	latestBlock.IncrHeight()
+	if err := svc.blockCache.SetBlock(chainId, credentials.AccountAddress, latestBlock); err != nil {
+		log.Error().Err(err)
+	}
+	return hash, nil
+}
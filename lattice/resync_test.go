@@ -0,0 +1,80 @@
+package lattice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/LatticeBCLab/go-lattice/common/constant"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsBlockMismatchError(t *testing.T) {
+	assert.True(t, isBlockMismatchError((&client.JsonRpcError{Code: -32000, Message: "invalid parent hash"}).Error()))
+	assert.True(t, isBlockMismatchError(fmt.Errorf("发送交易失败：%w", client.NewRpcError(-32000, "TBlock Number Mismatch"))))
+	assert.False(t, isBlockMismatchError(client.NewRpcError(-32000, "insufficient balance")))
+	assert.False(t, isBlockMismatchError(errors.New("-32000:invalid parent hash")))
+	assert.False(t, isBlockMismatchError(nil))
+}
+
+func TestResendOnBlockMismatch(t *testing.T) {
+	node := newMockNode(t)
+	pending := &types.LatestBlock{Height: 5, Hash: common.HexToHash("0x05"), DaemonBlockHash: common.HexToHash("0xd5")}
+	node.handle("latc_getCurrentTBDB", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.LatestBlock{Height: 3, Hash: common.HexToHash("0x03")}, nil
+	})
+	node.handle("latc_getPendingTBDB", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return pending, nil
+	})
+	sent := 0
+	node.handle("wallet_sendRawTBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		sent++
+		var tx struct {
+			Height     uint64      `json:"number"`
+			ParentHash common.Hash `json:"parentHash"`
+		}
+		_ = json.Unmarshal(params[0], &tx)
+		if tx.Height != pending.Height+1 || tx.ParentHash != pending.Hash {
+			return nil, &client.JsonRpcError{Code: -32000, Message: "invalid parent hash"}
+		}
+		return common.HexToHash("0x06"), nil
+	})
+
+	credentials := newTestCredentials(t)
+	t.Run("disabled", func(t *testing.T) {
+		svc := newMockLattice(t, node, nil)
+		_, err := svc.Transfer(context.Background(), credentials, chainId, constant.ZeroAddress, constant.ZeroPayload, 0, 0)
+		assert.Error(t, err)
+		// 发送失败后缓存被重新同步为节点的pending区块
+		cached, err := svc.blockCache.GetBlock(chainId, credentials.AccountAddress)
+		assert.NoError(t, err)
+		assert.Equal(t, pending.Height, cached.Height)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		sent = 0
+		svc := newMockLattice(t, node, &Options{ResendOnBlockMismatch: true})
+		hash, err := svc.Transfer(context.Background(), credentials, chainId, constant.ZeroAddress, constant.ZeroPayload, 0, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, common.HexToHash("0x06"), *hash)
+		assert.Equal(t, 2, sent)
+		cached, err := svc.blockCache.GetBlock(chainId, credentials.AccountAddress)
+		assert.NoError(t, err)
+		assert.Equal(t, pending.Height+1, cached.Height)
+		assert.Equal(t, common.HexToHash("0x06"), cached.Hash)
+	})
+
+	t.Run("unsafe call contract", func(t *testing.T) {
+		sent = 0
+		svc := newMockLattice(t, node, &Options{ResendOnBlockMismatch: true})
+		hash, err := svc.UnsafeCallContract(context.Background(), credentials, chainId, constant.ZeroAddress, "0x01", constant.ZeroPayload, 0, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, common.HexToHash("0x06"), *hash)
+		assert.Equal(t, 2, sent)
+	})
+}
@@ -0,0 +1,31 @@
+package lattice
+
+import (
+	"github.com/LatticeBCLab/go-lattice/abi"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+)
+
+func (svc *lattice) RegisterContractAbi(contractAddress string, contractAbi abi.LatticeAbi) {
+	svc.contractAbis.Store(contractAddress, contractAbi)
+}
+
+func (svc *lattice) RevertError(contractAddress string, receipt *types.Receipt) error {
+	if receipt == nil || receipt.Success {
+		return nil
+	}
+	if contractAddress == "" {
+		contractAddress = receipt.ContractAddress
+	}
+	if value, ok := svc.contractAbis.Load(contractAddress); ok {
+		return abi.DecodeRevertError(value.(abi.LatticeAbi).RawAbi(), receipt.ContractRet)
+	}
+	return abi.DecodeRevertError(nil, receipt.ContractRet)
+}
+
+// receiptError 开启 Options.ReturnRevertError 时，回执为失败时返回 *abi.ContractRevertError，否则返回nil
+func (svc *lattice) receiptError(contractAddress string, receipt *types.Receipt) error {
+	if !svc.options.ReturnRevertError {
+		return nil
+	}
+	return svc.RevertError(contractAddress, receipt)
+}
@@ -0,0 +1,100 @@
+package lattice
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/LatticeBCLab/go-lattice/abi"
+	"github.com/LatticeBCLab/go-lattice/common/constant"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractRevertError(t *testing.T) {
+	contractAbi := abi.NewAbi("[{\"inputs\":[{\"internalType\":\"uint256\",\"name\":\"available\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"required\",\"type\":\"uint256\"}],\"name\":\"InsufficientBalance\",\"type\":\"error\"}]")
+	abiErr := contractAbi.RawAbi().Errors["InsufficientBalance"]
+	payload, err := abiErr.Inputs.Pack(big.NewInt(1), big.NewInt(2))
+	require.NoError(t, err)
+	contractRet := hexutil.Encode(append(abiErr.ID[:4], payload...))
+	contractAddress := "zltc_QLbz7JHiBTspS962RLKV8GndWFwjA5K66"
+
+	node := newMockNode(t)
+	node.handle("latc_getCurrentTBDB", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.LatestBlock{Height: 3, Hash: common.HexToHash("0x03")}, nil
+	})
+	node.handle("wallet_sendRawTBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return common.HexToHash("0x04"), nil
+	})
+	node.handle("wallet_preExecuteContract", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.Receipt{Success: false, ContractRet: contractRet}, nil
+	})
+	node.handle("latc_getReceipt", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		var hash common.Hash
+		_ = json.Unmarshal(params[0], &hash)
+		return &types.Receipt{TBlockHash: hash, DBlockNumber: 10, Success: false, ContractRet: contractRet}, nil
+	})
+	credentials := newTestCredentials(t)
+	svc := newMockLattice(t, node, nil)
+	svc.RegisterContractAbi(contractAddress, contractAbi)
+
+	t.Run("failed receipt without error by default", func(t *testing.T) {
+		hash, receipt, err := svc.CallContractWaitReceipt(context.Background(), credentials, chainId, contractAddress, "0x01", constant.ZeroPayload, 0, 0, NewBackOffRetryStrategy(3, 10*time.Millisecond))
+		require.NoError(t, err)
+		assert.NotNil(t, hash)
+		require.NotNil(t, receipt)
+		assert.False(t, receipt.Success)
+
+		var revertErr *abi.ContractRevertError
+		require.True(t, errors.As(svc.RevertError(contractAddress, receipt), &revertErr))
+		assert.Equal(t, "InsufficientBalance", revertErr.Name)
+		assert.Equal(t, "InsufficientBalance(available=1, required=2)", revertErr.Message)
+
+		receipt, err = svc.PreCallContract(context.Background(), chainId, credentials.AccountAddress, contractAddress, "0x01", constant.ZeroPayload)
+		require.NoError(t, err)
+		assert.False(t, receipt.Success)
+	})
+
+	t.Run("submit result", func(t *testing.T) {
+		req := &TxRequest{Type: block.TransactionTypeCallContract, Linker: contractAddress, Code: "0x01", Payload: constant.ZeroPayload}
+		result, err := svc.Submit(context.Background(), credentials, chainId, req, WithRetryStrategy(NewBackOffRetryStrategy(3, 10*time.Millisecond)))
+		require.NoError(t, err)
+		var revertErr *abi.ContractRevertError
+		require.True(t, errors.As(result.RevertErr, &revertErr))
+		assert.Equal(t, "InsufficientBalance", revertErr.Name)
+	})
+
+	t.Run("return revert error", func(t *testing.T) {
+		svc := newMockLattice(t, node, &Options{ReturnRevertError: true})
+		svc.RegisterContractAbi(contractAddress, contractAbi)
+
+		hash, receipt, err := svc.CallContractWaitReceipt(context.Background(), credentials, chainId, contractAddress, "0x01", constant.ZeroPayload, 0, 0, NewBackOffRetryStrategy(3, 10*time.Millisecond))
+		var revertErr *abi.ContractRevertError
+		require.True(t, errors.As(err, &revertErr))
+		assert.Equal(t, "InsufficientBalance", revertErr.Name)
+		assert.NotNil(t, hash)
+		require.NotNil(t, receipt)
+		assert.False(t, receipt.Success)
+
+		receipt, err = svc.PreCallContract(context.Background(), chainId, credentials.AccountAddress, contractAddress, "0x01", constant.ZeroPayload)
+		require.True(t, errors.As(err, &revertErr))
+		assert.Equal(t, big.NewInt(2), revertErr.Args["required"])
+		assert.NotNil(t, receipt)
+	})
+
+	t.Run("submit with pre-execute", func(t *testing.T) {
+		req := &TxRequest{Type: block.TransactionTypeCallContract, Linker: contractAddress, Code: "0x01", Payload: constant.ZeroPayload}
+		_, err := svc.Submit(context.Background(), credentials, chainId, req, WithPreExecute())
+		assert.ErrorIs(t, err, ErrPreExecuteFailed)
+		var revertErr *abi.ContractRevertError
+		assert.True(t, errors.As(err, &revertErr))
+	})
+}
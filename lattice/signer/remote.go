@@ -0,0 +1,129 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/LatticeBCLab/go-lattice/common/convert"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const defaultRemoteSignTimeout = 10 * time.Second
+
+// RemoteSignerConfig 远程签名服务的配置
+//
+// 签名请求为 POST Url，请求体为 RemoteSignRequest，服务返回2xx状态码和 RemoteSignResponse，
+// 其它状态码视为签名失败，响应体作为错误信息
+type RemoteSignerConfig struct {
+	Url       string            // 签名接口的地址，如 https://signer.example.com/sign
+	Address   string            // 签名者的账户地址，zltc_开头
+	Curve     types.Curve       // 签名使用的椭圆曲线
+	Headers   map[string]string // 每个请求附带的请求头，如鉴权的 Authorization
+	Timeout   time.Duration     // 单次签名请求的超时时长，默认为10秒
+	Transport http.RoundTripper // http transport，默认为 http.DefaultTransport
+}
+
+// RemoteSignRequest 远程签名的请求体
+type RemoteSignRequest struct {
+	Address string      `json:"address"`
+	Curve   types.Curve `json:"curve"`
+	Hash    common.Hash `json:"hash"`
+}
+
+// RemoteSignResponse 远程签名的响应体
+type RemoteSignResponse struct {
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// NewRemoteSigner 初始化远程签名服务的客户端，私钥只保存在签名服务中，返回的签名会校验是否由 Address 签发
+//
+// Parameters:
+//   - config *RemoteSignerConfig
+//
+// Returns:
+//   - Signer
+//   - error
+func NewRemoteSigner(config *RemoteSignerConfig) (Signer, error) {
+	if config.Url == "" {
+		return nil, errors.New("远程签名服务的地址不能为空")
+	}
+	address, err := convert.ZltcToAddress(config.Address)
+	if err != nil {
+		return nil, fmt.Errorf("签名者的地址不合法：%w", err)
+	}
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultRemoteSignTimeout
+	}
+	transport := config.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &remoteSigner{
+		config:  config,
+		address: address,
+		client:  &http.Client{Transport: transport, Timeout: timeout},
+	}, nil
+}
+
+type remoteSigner struct {
+	config  *RemoteSignerConfig
+	address common.Address
+	client  *http.Client
+}
+
+func (s *remoteSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *remoteSigner) Curve() types.Curve {
+	return s.config.Curve
+}
+
+func (s *remoteSigner) SignHash(hash common.Hash) ([]byte, error) {
+	body, err := json.Marshal(&RemoteSignRequest{Address: s.config.Address, Curve: s.config.Curve, Hash: hash})
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequestWithContext(context.Background(), http.MethodPost, s.config.Url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	for k, v := range s.config.Headers {
+		request.Header.Set(k, v)
+	}
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("请求远程签名服务失败：%w", err)
+	}
+	defer response.Body.Close()
+	respBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return nil, fmt.Errorf("远程签名服务返回错误，status: %d, body: %s", response.StatusCode, bytes.TrimSpace(respBody))
+	}
+
+	var result RemoteSignResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("无法解析远程签名服务的响应：%w", err)
+	}
+	if len(result.Signature) == 0 {
+		return nil, errors.New("远程签名服务返回的签名为空")
+	}
+	if err := VerifySignature(s.config.Curve, s.address, hash, result.Signature); err != nil {
+		return nil, err
+	}
+	return result.Signature, nil
+}
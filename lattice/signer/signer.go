@@ -0,0 +1,175 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/LatticeBCLab/go-lattice/common/convert"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/crypto"
+	"github.com/LatticeBCLab/go-lattice/wallet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/samber/lo"
+)
+
+// ErrSignerMismatch 签名恢复出的地址与签名者的地址不一致
+var ErrSignerMismatch = errors.New("签名者的地址不一致")
+
+// Signer 交易签名者，私钥可以保存在内存、FileKey、硬件加密机或远程签名服务中，调用方只能拿到签名结果
+type Signer interface {
+	// Address 签名者的账户地址
+	//
+	// Returns:
+	//   - common.Address
+	Address() common.Address
+
+	// Curve 签名使用的椭圆曲线
+	//
+	// Returns:
+	//   - types.Curve
+	Curve() types.Curve
+
+	// SignHash 对哈希签名
+	//
+	// Parameters:
+	//   - hash common.Hash: 交易的待签名哈希
+	//
+	// Returns:
+	//   - []byte: 签名
+	//   - error
+	SignHash(hash common.Hash) ([]byte, error)
+}
+
+// NewPrivateKeySigner 使用内存中的私钥初始化签名者
+//
+// Parameters:
+//   - curve types.Curve
+//   - skHex string: 带0x前缀的16进制的私钥
+//
+// Returns:
+//   - Signer
+//   - error
+func NewPrivateKeySigner(curve types.Curve, skHex string) (Signer, error) {
+	sk, err := crypto.NewCrypto(curve).HexToSK(skHex)
+	if err != nil {
+		return nil, err
+	}
+	return newPrivateKeySigner(curve, sk)
+}
+
+func newPrivateKeySigner(curve types.Curve, sk *ecdsa.PrivateKey) (*privateKeySigner, error) {
+	address, err := crypto.NewCrypto(curve).PKToAddress(&sk.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &privateKeySigner{curve: curve, sk: sk, address: address}, nil
+}
+
+type privateKeySigner struct {
+	curve   types.Curve
+	sk      *ecdsa.PrivateKey
+	address common.Address
+}
+
+func (s *privateKeySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *privateKeySigner) Curve() types.Curve {
+	return s.curve
+}
+
+func (s *privateKeySigner) SignHash(hash common.Hash) ([]byte, error) {
+	return crypto.NewCrypto(s.curve).Sign(hash.Bytes(), s.sk)
+}
+
+// NewFileKeySigner 使用FileKey初始化签名者，第一次签名时才解密私钥，解密后的私钥只保存在签名者中
+//
+// Parameters:
+//   - fileKeyJson string: FileKey 的json字符串
+//   - passphrase string: 身份密码
+//
+// Returns:
+//   - Signer
+//   - error
+func NewFileKeySigner(fileKeyJson, passphrase string) (Signer, error) {
+	fileKey := wallet.NewFileKey(fileKeyJson)
+	if fileKey == nil {
+		return nil, errors.New("无法解析FileKey")
+	}
+	address, err := convert.ZltcToAddress(fileKey.Address)
+	if err != nil {
+		return nil, fmt.Errorf("FileKey的地址不合法：%w", err)
+	}
+	return &fileKeySigner{
+		fileKey:    fileKey,
+		passphrase: passphrase,
+		curve:      lo.Ternary(fileKey.IsGM, types.Sm2p256v1, types.Secp256k1),
+		address:    address,
+	}, nil
+}
+
+type fileKeySigner struct {
+	fileKey    *wallet.FileKey
+	passphrase string
+	curve      types.Curve
+	address    common.Address
+
+	once   sync.Once
+	signer *privateKeySigner
+	err    error
+}
+
+func (s *fileKeySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *fileKeySigner) Curve() types.Curve {
+	return s.curve
+}
+
+func (s *fileKeySigner) SignHash(hash common.Hash) ([]byte, error) {
+	s.once.Do(func() {
+		sk, err := s.fileKey.Decrypt(s.passphrase)
+		if err != nil {
+			s.err = err
+			return
+		}
+		s.signer, s.err = newPrivateKeySigner(s.curve, sk)
+	})
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.signer.SignHash(hash)
+}
+
+// VerifySignature 从签名恢复公钥，校验签名是否由该地址签发
+//
+// Parameters:
+//   - curve types.Curve
+//   - address common.Address: 签名者的地址
+//   - hash common.Hash
+//   - signature []byte
+//
+// Returns:
+//   - error
+func VerifySignature(curve types.Curve, address common.Address, hash common.Hash, signature []byte) error {
+	cryptoInstance := crypto.NewCrypto(curve)
+	pk, err := cryptoInstance.SignatureToPK(hash.Bytes(), signature)
+	if err != nil {
+		return fmt.Errorf("无法从签名恢复公钥：%w", err)
+	}
+	if pk == nil {
+		return errors.New("无法从签名恢复公钥")
+	}
+	signer, err := cryptoInstance.PKToAddress(pk)
+	if err != nil {
+		return err
+	}
+	if signer != address {
+		return fmt.Errorf("%w，expect %s, got %s", ErrSignerMismatch, convert.AddressToZltc(address), convert.AddressToZltc(signer))
+	}
+	return nil
+}
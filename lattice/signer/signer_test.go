@@ -0,0 +1,133 @@
+package signer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/LatticeBCLab/go-lattice/common/convert"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/crypto"
+	"github.com/LatticeBCLab/go-lattice/wallet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSigner(t *testing.T) (Signer, string) {
+	api := crypto.NewCrypto(types.Sm2p256v1)
+	sk, err := api.GenerateKeyPair()
+	require.NoError(t, err)
+	skHex, err := api.SKToHexString(sk)
+	require.NoError(t, err)
+	s, err := NewPrivateKeySigner(types.Sm2p256v1, skHex)
+	require.NoError(t, err)
+	return s, skHex
+}
+
+// newStubSignServer 模拟远程签名服务，使用 s 签名，只接受携带 token 的请求
+func newStubSignServer(t *testing.T, s Signer, token string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != token {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var req RemoteSignRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		signature, err := s.SignHash(req.Hash)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(&RemoteSignResponse{Signature: signature})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPrivateKeySigner(t *testing.T) {
+	s, _ := newTestSigner(t)
+	hash := common.HexToHash("0x1234")
+	signature, err := s.SignHash(hash)
+	require.NoError(t, err)
+	assert.NoError(t, VerifySignature(s.Curve(), s.Address(), hash, signature))
+
+	other, _ := newTestSigner(t)
+	assert.ErrorIs(t, VerifySignature(s.Curve(), other.Address(), hash, signature), ErrSignerMismatch)
+}
+
+func TestFileKeySigner(t *testing.T) {
+	s, skHex := newTestSigner(t)
+	fileKey, err := wallet.GenerateFileKey(skHex, "Root1234", types.Sm2p256v1)
+	require.NoError(t, err)
+	fileKeyJson, err := json.Marshal(fileKey)
+	require.NoError(t, err)
+
+	fileKeySigner, err := NewFileKeySigner(string(fileKeyJson), "Root1234")
+	require.NoError(t, err)
+	assert.Equal(t, s.Address(), fileKeySigner.Address())
+	assert.Equal(t, types.Sm2p256v1, fileKeySigner.Curve())
+	hash := common.HexToHash("0x1234")
+	signature, err := fileKeySigner.SignHash(hash)
+	require.NoError(t, err)
+	assert.NoError(t, VerifySignature(types.Sm2p256v1, s.Address(), hash, signature))
+
+	wrongPassphrase, err := NewFileKeySigner(string(fileKeyJson), "wrong")
+	require.NoError(t, err)
+	_, err = wrongPassphrase.SignHash(hash)
+	assert.Error(t, err)
+
+	_, err = NewFileKeySigner("not a file key", "Root1234")
+	assert.Error(t, err)
+}
+
+func TestRemoteSigner(t *testing.T) {
+	s, _ := newTestSigner(t)
+	server := newStubSignServer(t, s, "Bearer token")
+	hash := common.HexToHash("0x1234")
+
+	t.Run("sign", func(t *testing.T) {
+		remote, err := NewRemoteSigner(&RemoteSignerConfig{
+			Url:     server.URL,
+			Address: convert.AddressToZltc(s.Address()),
+			Curve:   types.Sm2p256v1,
+			Headers: map[string]string{"Authorization": "Bearer token"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, s.Address(), remote.Address())
+		signature, err := remote.SignHash(hash)
+		require.NoError(t, err)
+		assert.NoError(t, VerifySignature(types.Sm2p256v1, s.Address(), hash, signature))
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		remote, err := NewRemoteSigner(&RemoteSignerConfig{Url: server.URL, Address: convert.AddressToZltc(s.Address()), Curve: types.Sm2p256v1})
+		require.NoError(t, err)
+		_, err = remote.SignHash(hash)
+		assert.ErrorContains(t, err, "401")
+	})
+
+	t.Run("signed by another key", func(t *testing.T) {
+		other, _ := newTestSigner(t)
+		remote, err := NewRemoteSigner(&RemoteSignerConfig{
+			Url:     server.URL,
+			Address: convert.AddressToZltc(other.Address()),
+			Curve:   types.Sm2p256v1,
+			Headers: map[string]string{"Authorization": "Bearer token"},
+		})
+		require.NoError(t, err)
+		_, err = remote.SignHash(hash)
+		assert.ErrorIs(t, err, ErrSignerMismatch)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewRemoteSigner(&RemoteSignerConfig{Address: convert.AddressToZltc(s.Address())})
+		assert.Error(t, err)
+		_, err = NewRemoteSigner(&RemoteSignerConfig{Url: server.URL, Address: "0x01"})
+		assert.Error(t, err)
+	})
+}
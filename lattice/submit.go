@@ -0,0 +1,246 @@
+package lattice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/LatticeBCLab/go-lattice/common/constant"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/crypto"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrPreExecuteFailed 预执行交易的回执为失败，交易未发送
+var ErrPreExecuteFailed = errors.New("预执行交易失败")
+
+// TxRequest 交易请求
+//
+//   - Type    交易类型，如 block.TransactionTypeSend、block.TransactionTypeCallGoContract
+//   - Linker  转账接收者或合约地址，为空时使用零地址
+//   - Code    合约数据，不为空时计算 CodeHash
+//   - Payload 交易备注
+//   - Amount  转账金额
+//   - Joule   交易手续费，为 AutoJoule 时发送前通过 EstimateJoule 估算
+type TxRequest struct {
+	Type    block.TransactionType
+	Linker  string
+	Code    string
+	Payload string
+	Amount  uint64
+	Joule   uint64
+}
+
+// SubmitResult 提交交易的结果
+//
+//   - Hash        交易哈希，dry-run时为本地计算的交易哈希
+//   - Receipt     交易回执，使用 WithWaitReceipt 时为上链的回执，dry-run并预执行时为预执行的回执
+//   - RevertErr   回执为失败时解码的 *abi.ContractRevertError，回执为成功时为nil
+//   - Transaction 已签名的交易
+type SubmitResult struct {
+	Hash        *common.Hash
+	Receipt     *types.Receipt
+	RevertErr   error
+	Transaction *block.Transaction
+}
+
+// SubmitOption 提交交易的选项
+type SubmitOption func(*submitOptions)
+
+type submitOptions struct {
+	waitReceipt   bool
+	retryStrategy *RetryStrategy
+	preExecute    bool
+	dryRun        bool
+}
+
+// WithWaitReceipt 发送交易后等待回执，未指定重试策略时使用 DefaultBackOffRetryStrategy
+func WithWaitReceipt() SubmitOption {
+	return func(options *submitOptions) {
+		options.waitReceipt = true
+	}
+}
+
+// WithRetryStrategy 等待回执时使用的重试策略，同时开启等待回执
+func WithRetryStrategy(retryStrategy *RetryStrategy) SubmitOption {
+	return func(options *submitOptions) {
+		options.waitReceipt = true
+		options.retryStrategy = retryStrategy
+	}
+}
+
+// WithPreExecute 签名前先预执行交易，预执行失败时不发送交易并返回 ErrPreExecuteFailed
+func WithPreExecute() SubmitOption {
+	return func(options *submitOptions) {
+		options.preExecute = true
+	}
+}
+
+// WithDryRun 只构造并签名交易，不发送交易，也不更新账户的区块缓存
+func WithDryRun() SubmitOption {
+	return func(options *submitOptions) {
+		options.dryRun = true
+	}
+}
+
+func (svc *lattice) Submit(ctx context.Context, credentials *Credentials, chainId string, req *TxRequest, opts ...SubmitOption) (*SubmitResult, error) {
+	options := &submitOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	log.Debug().Msgf("开始提交%s交易，chainId: %s, linker: %s, payload: %s, amount: %d, joule: %d", req.Type, chainId, req.Linker, req.Payload, req.Amount, req.Joule)
+
+	result, err := svc.submit(ctx, credentials, chainId, req, options)
+	if err != nil {
+		return result, err
+	}
+	log.Debug().Msgf("结束提交%s交易，哈希为：%s", req.Type, result.Hash.String())
+	if options.dryRun || !options.waitReceipt {
+		return result, nil
+	}
+
+	retryStrategy := options.retryStrategy
+	if retryStrategy == nil {
+		retryStrategy = DefaultBackOffRetryStrategy()
+	}
+	_, receipt, err := svc.waitReceipt(ctx, chainId, req.Linker, result.Hash, retryStrategy)
+	result.Receipt = receipt
+	result.RevertErr = svc.RevertError(req.Linker, receipt)
+	return result, err
+}
+
+// submit 预执行交易后，在账户锁内构造、签名并发送交易
+func (svc *lattice) submit(ctx context.Context, credentials *Credentials, chainId string, req *TxRequest, options *submitOptions) (*SubmitResult, error) {
+	chainIdAsInt, err := strconv.ParseUint(chainId, 10, 64)
+	if err != nil {
+		log.Error().Err(err)
+		return nil, err
+	}
+	// 在获取账户锁之前估算手续费，避免预执行占用账户锁
+	if req, err = svc.resolveJoule(ctx, chainId, credentials.AccountAddress, req); err != nil {
+		return nil, err
+	}
+
+	difficulty, err := svc.powDifficulty(ctx, chainId)
+	if err != nil {
+		return nil, err
+	}
+
+	// 预执行基于零区块构造交易，与账户的最新区块无关，在获取账户锁之前执行
+	result := &SubmitResult{}
+	if options.preExecute {
+		receipt, err := svc.preExecute(ctx, chainId, credentials.AccountAddress, req)
+		if err != nil {
+			return nil, err
+		}
+		result.Receipt = receipt
+	}
+
+	if err := svc.obtainAccountLock(ctx, chainId, credentials.AccountAddress); err != nil {
+		return nil, err
+	}
+	defer svc.accountLock.Unlock(chainId, credentials.AccountAddress)
+
+	latestBlock, err := svc.blockCache.GetBlock(chainId, credentials.AccountAddress)
+	if err != nil {
+		log.Error().Err(err)
+		return nil, err
+	}
+	transaction := svc.buildTransaction(latestBlock, credentials.AccountAddress, req)
+	result.Transaction = transaction
+
+	if options.dryRun {
+		if err := svc.signTransaction(ctx, credentials, chainIdAsInt, transaction, difficulty); err != nil {
+			log.Error().Err(err)
+			return nil, err
+		}
+		hash, err := transaction.CalculateTransactionHash(svc.chainConfig.Curve)
+		if err != nil {
+			return nil, err
+		}
+		result.Hash = &hash
+		return result, nil
+	}
+
+	hash, err := svc.handleTransaction(ctx, credentials, chainId, transaction, latestBlock, difficulty)
+	if err != nil {
+		return nil, err
+	}
+	result.Hash = hash
+	return result, nil
+}
+
+// buildTransaction 基于账户的最新区块构造未签名的交易
+func (svc *lattice) buildTransaction(latestBlock *types.LatestBlock, owner string, req *TxRequest) *block.Transaction {
+	linker := req.Linker
+	if linker == "" {
+		linker = constant.ZeroAddress
+	}
+	transaction := block.NewTransactionBuilder(req.Type).
+		SetLatestBlock(latestBlock).
+		SetOwner(owner).
+		SetLinker(linker).
+		SetCode(req.Code).
+		SetPayload(req.Payload).
+		SetAmount(req.Amount).
+		SetJoule(req.Joule).
+		Build()
+
+	if req.Code != "" {
+		transaction.CodeHash = crypto.NewCrypto(svc.chainConfig.Curve).Hash(hexutil.MustDecode(req.Code))
+	}
+	return transaction
+}
+
+// preExecute 预执行交易，回执为失败时返回包装了 *abi.ContractRevertError 的 ErrPreExecuteFailed
+func (svc *lattice) preExecute(ctx context.Context, chainId, owner string, req *TxRequest) (*types.Receipt, error) {
+	transaction := svc.buildTransaction(&types.LatestBlock{
+		Height:          0,
+		Hash:            common.HexToHash(constant.ZeroHash),
+		DaemonBlockHash: common.HexToHash(constant.ZeroHash),
+	}, owner, req)
+
+	receipt, err := svc.httpApi.PreCallContract(ctx, chainId, transaction)
+	if err != nil {
+		log.Error().Err(err)
+		return nil, err
+	}
+	if revertErr := svc.RevertError(req.Linker, receipt); revertErr != nil {
+		return receipt, fmt.Errorf("%w：%w", ErrPreExecuteFailed, revertErr)
+	}
+	return receipt, nil
+}
+
+// submitWaitReceipt 提交交易并等待回执，供 *WaitReceipt 方法使用
+func (svc *lattice) submitWaitReceipt(ctx context.Context, credentials *Credentials, chainId string, req *TxRequest, retryStrategy *RetryStrategy) (*common.Hash, *types.Receipt, error) {
+	result, err := svc.Submit(ctx, credentials, chainId, req, WithRetryStrategy(retryStrategy))
+	if result == nil {
+		return nil, nil, err
+	}
+	return result.Hash, result.Receipt, err
+}
+
+// multilingualTransactionType 根据合约语言获取部署、升级或调用合约的交易类型
+//
+// Parameters:
+//   - lang types.ContractLang
+//   - goType block.TransactionType: Go合约的交易类型
+//   - javaType block.TransactionType: Java合约的交易类型
+//
+// Returns:
+//   - block.TransactionType
+//   - error
+func multilingualTransactionType(lang types.ContractLang, goType, javaType block.TransactionType) (block.TransactionType, error) {
+	switch lang {
+	case types.ContractLangGo:
+		return goType, nil
+	case types.ContractLangJava:
+		return javaType, nil
+	default:
+		return "", fmt.Errorf("不支持的合约语言：%s", lang)
+	}
+}
@@ -0,0 +1,148 @@
+package lattice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// ErrBatchPartiallyFailed 批量交易中有交易发送失败，失败原因见 BatchTxResult.Err
+	ErrBatchPartiallyFailed = errors.New("批量交易部分发送失败")
+	// ErrBatchTxRejected 节点未接受批量交易中的该笔交易
+	ErrBatchTxRejected = errors.New("节点未接受该笔交易")
+	// ErrBatchPreviousTxFailed 批量交易中的前序交易发送失败，该笔交易的父哈希在链上不存在
+	ErrBatchPreviousTxFailed = errors.New("前序交易发送失败")
+)
+
+// BatchTxResult 批量交易中单笔交易的结果
+//
+//   - Hash        交易哈希，发送失败时为nil
+//   - Transaction 已签名的交易
+//   - Err         发送失败的原因
+type BatchTxResult struct {
+	Hash        *common.Hash
+	Transaction *block.Transaction
+	Err         error
+}
+
+func (svc *lattice) SubmitBatch(ctx context.Context, credentials *Credentials, chainId string, reqs []*TxRequest) ([]*BatchTxResult, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+	log.Debug().Msgf("开始提交批量交易，chainId: %s, accountAddress: %s, 交易数量: %d", chainId, credentials.AccountAddress, len(reqs))
+
+	chainIdAsInt, err := strconv.ParseUint(chainId, 10, 64)
+	if err != nil {
+		log.Error().Err(err)
+		return nil, err
+	}
+
+	resolvedReqs, err := svc.resolveBatchJoule(ctx, chainId, credentials.AccountAddress, reqs)
+	if err != nil {
+		return nil, err
+	}
+	difficulty, err := svc.powDifficulty(ctx, chainId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := svc.obtainAccountLock(ctx, chainId, credentials.AccountAddress); err != nil {
+		return nil, err
+	}
+	defer svc.accountLock.Unlock(chainId, credentials.AccountAddress)
+
+	latestBlock, err := svc.blockCache.GetBlock(chainId, credentials.AccountAddress)
+	if err != nil {
+		log.Error().Err(err)
+		return nil, err
+	}
+
+	transactions := make([]*block.Transaction, len(reqs))
+	for i, req := range resolvedReqs {
+		transactions[i] = svc.buildTransaction(latestBlock, credentials.AccountAddress, req)
+	}
+	if err := svc.chainTransactions(ctx, credentials, chainIdAsInt, latestBlock, transactions, difficulty); err != nil {
+		log.Error().Err(err)
+		return nil, err
+	}
+
+	results := make([]*BatchTxResult, len(transactions))
+	for i, transaction := range transactions {
+		results[i] = &BatchTxResult{Transaction: transaction}
+	}
+
+	if err := svc.checkAccountLockLease(chainId, credentials.AccountAddress); err != nil {
+		return nil, err
+	}
+
+	cancelCtx, cancelFunc := context.WithTimeout(ctx, defaultHttpRequestTimeout)
+	defer cancelFunc()
+	hashes, err := svc.httpApi.SendSignedTransactions(cancelCtx, chainId, transactions)
+	if err != nil {
+		log.Error().Err(err).Msgf("发送批量交易失败，chainId: %s, accountAddress: %s", chainId, credentials.AccountAddress)
+		// 超时或连接断开时节点可能已接受部分交易，同 recoverTransaction 重新同步区块缓存
+		if _, resyncErr := svc.resyncBlock(ctx, chainId, credentials.AccountAddress); resyncErr != nil {
+			log.Error().Err(resyncErr)
+		}
+		return nil, err
+	}
+
+	// 交易按高度依次衔接，第一笔失败的交易之后的交易都无法上链
+	succeeded := 0
+	for i, result := range results {
+		switch {
+		case succeeded < i:
+			result.Err = ErrBatchPreviousTxFailed
+		case i >= len(hashes) || hashes[i] == nil:
+			result.Err = ErrBatchTxRejected
+		default:
+			result.Hash = hashes[i]
+			succeeded++
+		}
+	}
+
+	if succeeded > 0 {
+		last := transactions[succeeded-1]
+		latestBlock.Height = last.Height
+		latestBlock.Hash = *results[succeeded-1].Hash
+		if err := svc.blockCache.SetBlock(chainId, credentials.AccountAddress, latestBlock); err != nil {
+			log.Error().Err(err)
+		}
+	}
+	if succeeded < len(results) {
+		if succeeded == 0 {
+			if err := svc.blockCache.Invalidate(chainId, credentials.AccountAddress); err != nil {
+				log.Error().Err(err)
+			}
+		}
+		return results, fmt.Errorf("%w，成功%d笔，失败%d笔", ErrBatchPartiallyFailed, succeeded, len(results)-succeeded)
+	}
+	log.Debug().Msgf("结束提交批量交易，chainId: %s, accountAddress: %s, 交易数量: %d", chainId, credentials.AccountAddress, len(results))
+	return results, nil
+}
+
+// chainTransactions 将交易按高度依次衔接并签名，每笔交易的父哈希为前一笔交易签名后的哈希，
+// 交易哈希包含签名，后一笔交易的待签名哈希又包含父哈希，所以只能按顺序签名，所有交易使用同一个工作量证明的难度
+func (svc *lattice) chainTransactions(ctx context.Context, credentials *Credentials, chainId uint64, latestBlock *types.LatestBlock, transactions []*block.Transaction, difficulty uint64) error {
+	height, parentHash := latestBlock.Height, latestBlock.Hash
+	for _, transaction := range transactions {
+		transaction.Height = height + 1
+		transaction.ParentHash = parentHash
+		if err := svc.signTransaction(ctx, credentials, chainId, transaction, difficulty); err != nil {
+			return err
+		}
+		hash, err := transaction.CalculateTransactionHash(svc.chainConfig.Curve)
+		if err != nil {
+			return err
+		}
+		height, parentHash = transaction.Height, hash
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package lattice
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/LatticeBCLab/go-lattice/common/constant"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmitBatch(t *testing.T) {
+	node := newMockNode(t)
+	node.handle("latc_getCurrentTBDB", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.LatestBlock{Height: 3, Hash: common.HexToHash("0x03")}, nil
+	})
+	// rejectFrom 之后的交易不被节点接受
+	rejectFrom := -1
+	var batches [][]*block.Transaction
+	node.handle("wallet_sendRawBatchTBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		var txs []*block.Transaction
+		_ = json.Unmarshal(params[0], &txs)
+		batches = append(batches, txs)
+		hashes := make([]*common.Hash, len(txs))
+		for i, tx := range txs {
+			if rejectFrom >= 0 && i >= rejectFrom {
+				continue
+			}
+			hash, _ := tx.CalculateTransactionHash(types.Sm2p256v1)
+			hashes[i] = &hash
+		}
+		return hashes, nil
+	})
+	credentials := newTestCredentials(t)
+	reqs := make([]*TxRequest, 5)
+	for i := range reqs {
+		reqs[i] = &TxRequest{Type: block.TransactionTypeCallContract, Linker: constant.ZeroAddress, Code: "0x01", Payload: constant.ZeroPayload}
+	}
+
+	t.Run("chain and send in one call", func(t *testing.T) {
+		batches = nil
+		svc := newMockLattice(t, node, nil)
+		results, err := svc.SubmitBatch(context.Background(), credentials, chainId, reqs)
+		require.NoError(t, err)
+		require.Len(t, results, len(reqs))
+		require.Len(t, batches, 1)
+		assert.Equal(t, 0, node.callCount("wallet_sendRawTBlock"))
+
+		parentHash := common.HexToHash("0x03")
+		for i, result := range results {
+			require.NoError(t, result.Err)
+			assert.Equal(t, uint64(4+i), batches[0][i].Height)
+			assert.Equal(t, parentHash, batches[0][i].ParentHash)
+			assert.NotEmpty(t, batches[0][i].Sign)
+			parentHash = *result.Hash
+		}
+		cached, err := svc.blockCache.GetBlock(chainId, credentials.AccountAddress)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(8), cached.Height)
+		assert.Equal(t, parentHash, cached.Hash)
+	})
+
+	t.Run("partial failure", func(t *testing.T) {
+		rejectFrom = 2
+		defer func() { rejectFrom = -1 }()
+		svc := newMockLattice(t, node, nil)
+		results, err := svc.SubmitBatch(context.Background(), credentials, chainId, reqs)
+		assert.ErrorIs(t, err, ErrBatchPartiallyFailed)
+		require.Len(t, results, len(reqs))
+		assert.NoError(t, results[0].Err)
+		assert.NoError(t, results[1].Err)
+		assert.ErrorIs(t, results[2].Err, ErrBatchTxRejected)
+		assert.ErrorIs(t, results[3].Err, ErrBatchPreviousTxFailed)
+		assert.Nil(t, results[4].Hash)
+
+		// 缓存停在最后一笔成功的交易
+		cached, err := svc.blockCache.GetBlock(chainId, credentials.AccountAddress)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(5), cached.Height)
+		assert.Equal(t, *results[1].Hash, cached.Hash)
+	})
+
+	t.Run("send error resyncs cache", func(t *testing.T) {
+		node.handle("latc_getPendingTBDB", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+			return &types.LatestBlock{Height: 6, Hash: common.HexToHash("0x06")}, nil
+		})
+		svc := newMockLattice(t, node, nil)
+		_, err := svc.SubmitBatch(context.Background(), credentials, chainId, reqs)
+		require.NoError(t, err)
+
+		node.respondWithStatus(http.StatusBadGateway)
+		_, err = svc.SubmitBatch(context.Background(), credentials, chainId, reqs)
+		assert.Error(t, err)
+		assert.Equal(t, 1, node.callCount("latc_getPendingTBDB"))
+		cached, err := svc.blockCache.GetBlock(chainId, credentials.AccountAddress)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(6), cached.Height)
+		assert.Equal(t, common.HexToHash("0x06"), cached.Hash)
+	})
+}
@@ -0,0 +1,109 @@
+package lattice
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/LatticeBCLab/go-lattice/common/constant"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubmit(t *testing.T) {
+	node := newMockNode(t)
+	node.handle("latc_getCurrentTBDB", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.LatestBlock{Height: 3, Hash: common.HexToHash("0x03")}, nil
+	})
+	var sent []*block.Transaction
+	node.handle("wallet_sendRawTBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		var tx block.Transaction
+		_ = json.Unmarshal(params[0], &tx)
+		sent = append(sent, &tx)
+		return common.HexToHash("0x04"), nil
+	})
+	preExecuteSuccess := true
+	node.handle("wallet_preExecuteContract", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		return &types.Receipt{Success: preExecuteSuccess, ContractRet: "0x01"}, nil
+	})
+	node.handle("latc_getReceipt", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		var hash common.Hash
+		_ = json.Unmarshal(params[0], &hash)
+		return &types.Receipt{TBlockHash: hash, DBlockNumber: 10, Success: true}, nil
+	})
+	credentials := newTestCredentials(t)
+	req := &TxRequest{Type: block.TransactionTypeCallGoContract, Linker: constant.ZeroAddress, Code: "0x01", Payload: constant.ZeroPayload}
+
+	t.Run("wait receipt", func(t *testing.T) {
+		sent = nil
+		svc := newMockLattice(t, node, nil)
+		result, err := svc.Submit(context.Background(), credentials, chainId, req, WithRetryStrategy(NewBackOffRetryStrategy(3, 10*time.Millisecond)))
+		require.NoError(t, err)
+		assert.Equal(t, common.HexToHash("0x04"), *result.Hash)
+		require.NotNil(t, result.Receipt)
+		assert.Equal(t, *result.Hash, result.Receipt.TBlockHash)
+		require.Len(t, sent, 1)
+		assert.Equal(t, block.TransactionTypeCallGoContract, sent[0].Type)
+		assert.NotEqual(t, common.Hash{}, sent[0].CodeHash)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		sent = nil
+		svc := newMockLattice(t, node, nil)
+		result, err := svc.Submit(context.Background(), credentials, chainId, req, WithDryRun(), WithPreExecute())
+		require.NoError(t, err)
+		assert.Empty(t, sent)
+		assert.NotEmpty(t, result.Transaction.Sign)
+		assert.Equal(t, "0x01", result.Receipt.ContractRet)
+		// dry-run 不推进缓存
+		cached, err := svc.blockCache.GetBlock(chainId, credentials.AccountAddress)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(3), cached.Height)
+	})
+
+	t.Run("pre-execute failed", func(t *testing.T) {
+		sent = nil
+		preExecuteSuccess = false
+		defer func() { preExecuteSuccess = true }()
+		svc := newMockLattice(t, node, nil)
+		_, err := svc.Submit(context.Background(), credentials, chainId, req, WithPreExecute())
+		assert.ErrorIs(t, err, ErrPreExecuteFailed)
+		assert.Empty(t, sent)
+	})
+
+	t.Run("thin wrappers", func(t *testing.T) {
+		sent = nil
+		svc := newMockLattice(t, node, nil)
+		_, err := svc.DeployJavaContract(context.Background(), credentials, chainId, types.DeployMultilingualContractCode{}, constant.ZeroPayload, 0, 0)
+		require.NoError(t, err)
+		_, err = svc.Transfer(context.Background(), credentials, chainId, credentials.AccountAddress, constant.ZeroPayload, 1, 0)
+		require.NoError(t, err)
+		require.Len(t, sent, 2)
+		assert.Equal(t, block.TransactionTypeDeployJavaContract, sent[0].Type)
+		assert.Equal(t, constant.ZeroAddress, sent[0].Linker)
+		assert.Equal(t, block.TransactionTypeSend, sent[1].Type)
+		assert.Equal(t, sent[0].Height+1, sent[1].Height)
+	})
+
+	t.Run("pre-execute outside account lock", func(t *testing.T) {
+		svc := newMockLattice(t, node, nil)
+		lockFree := false
+		node.handle("wallet_preExecuteContract", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+			if svc.accountLock.ObtainContext(ctx, chainId, credentials.AccountAddress) == nil {
+				lockFree = true
+				svc.accountLock.Unlock(chainId, credentials.AccountAddress)
+			}
+			return &types.Receipt{Success: true}, nil
+		})
+		_, err := svc.Submit(context.Background(), credentials, chainId, req, WithPreExecute())
+		require.NoError(t, err)
+		assert.True(t, lockFree)
+	})
+}
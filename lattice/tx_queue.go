@@ -0,0 +1,313 @@
+package lattice
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultTxQueueSize               = 1024
+	defaultTxQueueConcurrentAccounts = 16
+	defaultTxQueueIdleTimeout        = time.Minute
+)
+
+// ErrTxQueueClosed 交易队列已关闭，不再接受新的交易，或关闭时未能在期限内发送的交易
+var ErrTxQueueClosed = errors.New("交易队列已关闭")
+
+// TxQueueConfig 异步交易队列的配置
+type TxQueueConfig struct {
+	QueueSize             int            // 每个账户的队列长度，队列已满时 Enqueue 阻塞，默认为1024
+	MaxConcurrentAccounts int            // 同时签名和发送交易的账户数量，默认为16
+	IdleTimeout           time.Duration  // 账户的队列空闲超过该时长后退出worker并释放队列，默认为1分钟
+	WaitReceipt           bool           // 发送交易后是否等待回执
+	ReceiptRetryStrategy  *RetryStrategy // 等待回执的重试策略，为nil时使用 DefaultBackOffRetryStrategy
+}
+
+// TxFuture 入队交易的结果，交易发送后 Sent 关闭，等待回执结束后 Done 关闭
+type TxFuture struct {
+	sent       chan struct{}
+	done       chan struct{}
+	hash       *common.Hash
+	sendErr    error // 发送交易的错误
+	receipt    *types.Receipt
+	receiptErr error // 等待回执的错误
+}
+
+func newTxFuture() *TxFuture {
+	return &TxFuture{sent: make(chan struct{}), done: make(chan struct{})}
+}
+
+// Sent 交易发送成功或失败后关闭
+func (f *TxFuture) Sent() <-chan struct{} {
+	return f.sent
+}
+
+// Done 交易处理结束后关闭，开启 TxQueueConfig.WaitReceipt 时包括等待回执
+func (f *TxFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Hash 等待交易发送并返回交易哈希
+//
+// Parameters:
+//   - ctx context.Context
+//
+// Returns:
+//   - *common.Hash
+//   - error
+func (f *TxFuture) Hash(ctx context.Context) (*common.Hash, error) {
+	select {
+	case <-f.sent:
+		return f.hash, f.sendErr
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Receipt 等待交易处理结束并返回回执，未开启 TxQueueConfig.WaitReceipt 时回执为nil
+//
+// Parameters:
+//   - ctx context.Context
+//
+// Returns:
+//   - *common.Hash
+//   - *types.Receipt
+//   - error
+func (f *TxFuture) Receipt(ctx context.Context) (*common.Hash, *types.Receipt, error) {
+	select {
+	case <-f.done:
+		if f.sendErr != nil {
+			return f.hash, nil, f.sendErr
+		}
+		return f.hash, f.receipt, f.receiptErr
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+func (f *TxFuture) resolveSent(hash *common.Hash, err error) {
+	f.hash, f.sendErr = hash, err
+	close(f.sent)
+}
+
+func (f *TxFuture) resolveDone(receipt *types.Receipt, err error) {
+	f.receipt, f.receiptErr = receipt, err
+	close(f.done)
+}
+
+// TxQueue 异步交易队列，每个账户一个按顺序处理的worker，在账户锁内签名交易并提前推进区块缓存，释放账户锁后再发送交易
+type TxQueue interface {
+	// Enqueue 将交易加入账户的队列，队列已满时阻塞直到有空位、ctx取消或队列关闭，ctx 只作用于入队
+	//
+	// Parameters:
+	//   - ctx context.Context
+	//   - credentials *Credentials: 发交易的身份凭证
+	//   - chainId string
+	//   - req *TxRequest
+	//
+	// Returns:
+	//   - *TxFuture
+	//   - error: 队列已关闭时为 ErrTxQueueClosed
+	Enqueue(ctx context.Context, credentials *Credentials, chainId string, req *TxRequest) (*TxFuture, error)
+
+	// Close 停止接受新的交易，等待已入队的交易处理结束，ctx 到期后剩余的交易以 ErrTxQueueClosed 结束
+	//
+	// Parameters:
+	//   - ctx context.Context
+	//
+	// Returns:
+	//   - error: 未能在 ctx 到期前处理完时为 ctx.Err()
+	Close(ctx context.Context) error
+}
+
+type queuedTx struct {
+	credentials *Credentials
+	chainId     string
+	req         *TxRequest
+	future      *TxFuture
+}
+
+// txWorker 一个账户的队列，pending为已入队或正在入队、尚未处理完的交易数量，由 txQueue.mu 保护
+type txWorker struct {
+	queue   chan *queuedTx
+	pending int
+}
+
+type txQueue struct {
+	svc    *lattice
+	config *TxQueueConfig
+	sem    chan struct{} // 限制同时处理的账户数量
+
+	ctx    context.Context // 关闭超时后取消，中止正在处理的交易
+	cancel context.CancelFunc
+
+	mu        sync.Mutex
+	closed    bool
+	workers   map[string]*txWorker // key: chainId + 账户地址，空闲超时的worker退出时删除
+	enqueuing sync.WaitGroup       // 正在入队的调用
+	running   sync.WaitGroup       // 运行中的worker和等待回执的协程
+}
+
+func (svc *lattice) NewTxQueue(config *TxQueueConfig) TxQueue {
+	if config == nil {
+		config = &TxQueueConfig{}
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaultTxQueueSize
+	}
+	if config.MaxConcurrentAccounts <= 0 {
+		config.MaxConcurrentAccounts = defaultTxQueueConcurrentAccounts
+	}
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = defaultTxQueueIdleTimeout
+	}
+	if config.ReceiptRetryStrategy == nil {
+		config.ReceiptRetryStrategy = DefaultBackOffRetryStrategy()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &txQueue{
+		svc:     svc,
+		config:  config,
+		sem:     make(chan struct{}, config.MaxConcurrentAccounts),
+		ctx:     ctx,
+		cancel:  cancel,
+		workers: make(map[string]*txWorker),
+	}
+}
+
+func (q *txQueue) Enqueue(ctx context.Context, credentials *Credentials, chainId string, req *TxRequest) (*TxFuture, error) {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil, ErrTxQueueClosed
+	}
+	key := chainId + credentials.AccountAddress
+	worker, ok := q.workers[key]
+	if !ok {
+		worker = &txWorker{queue: make(chan *queuedTx, q.config.QueueSize)}
+		q.workers[key] = worker
+		q.running.Add(1)
+		go q.work(key, worker)
+	}
+	worker.pending++
+	q.enqueuing.Add(1)
+	q.mu.Unlock()
+	defer q.enqueuing.Done()
+
+	tx := &queuedTx{credentials: credentials, chainId: chainId, req: req, future: newTxFuture()}
+	var err error
+	select {
+	case worker.queue <- tx:
+		return tx.future, nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-q.ctx.Done():
+		err = ErrTxQueueClosed
+	}
+	q.mu.Lock()
+	worker.pending--
+	q.mu.Unlock()
+	return nil, err
+}
+
+// work 按入队顺序处理一个账户的交易，空闲超过 TxQueueConfig.IdleTimeout 后退出，队列关闭后处理完剩余的交易再退出
+func (q *txQueue) work(key string, worker *txWorker) {
+	defer q.running.Done()
+	idle := time.NewTimer(q.config.IdleTimeout)
+	defer idle.Stop()
+	for {
+		select {
+		case tx, ok := <-worker.queue:
+			if !ok {
+				return
+			}
+			q.process(tx)
+			q.mu.Lock()
+			worker.pending--
+			q.mu.Unlock()
+			idle.Reset(q.config.IdleTimeout)
+		case <-idle.C:
+			if q.retire(key, worker) {
+				return
+			}
+			idle.Reset(q.config.IdleTimeout)
+		}
+	}
+}
+
+// retire 没有待处理和正在入队的交易时从 workers 中删除该worker，之后的入队会创建新的worker
+func (q *txQueue) retire(key string, worker *txWorker) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if worker.pending > 0 || q.closed {
+		return false
+	}
+	delete(q.workers, key)
+	return true
+}
+
+// process 签名并发送一笔交易，开启等待回执时在新的协程中等待回执
+func (q *txQueue) process(tx *queuedTx) {
+	if q.ctx.Err() != nil {
+		tx.future.resolveSent(nil, ErrTxQueueClosed)
+		tx.future.resolveDone(nil, nil)
+		return
+	}
+	q.sem <- struct{}{}
+	hash, err := q.svc.unsafeSubmit(q.ctx, tx.credentials, tx.chainId, tx.req)
+	<-q.sem
+	if err != nil {
+		log.Error().Err(err).Msgf("交易队列发送交易失败，chainId: %s, accountAddress: %s", tx.chainId, tx.credentials.AccountAddress)
+	}
+	tx.future.resolveSent(hash, err)
+	if err != nil || !q.config.WaitReceipt {
+		tx.future.resolveDone(nil, nil)
+		return
+	}
+
+	q.running.Add(1)
+	go func() {
+		defer q.running.Done()
+		_, receipt, err := q.svc.waitReceipt(q.ctx, tx.chainId, tx.req.Linker, hash, q.config.ReceiptRetryStrategy)
+		tx.future.resolveDone(receipt, err)
+	}()
+}
+
+func (q *txQueue) Close(ctx context.Context) error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return nil
+	}
+	q.closed = true
+	q.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		// 等待阻塞中的入队结束后才能关闭队列
+		q.enqueuing.Wait()
+		q.mu.Lock()
+		for _, worker := range q.workers {
+			close(worker.queue)
+		}
+		q.mu.Unlock()
+		q.running.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		// 中止正在处理的交易，剩余的交易由worker以 ErrTxQueueClosed 结束
+		q.cancel()
+		return ctx.Err()
+	}
+}
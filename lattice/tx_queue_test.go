@@ -0,0 +1,183 @@
+package lattice
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/LatticeBCLab/go-lattice/common/constant"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockChain 模拟节点按账户校验交易的高度和父哈希
+type mockChain struct {
+	mu      sync.Mutex
+	latest  map[string]*types.LatestBlock // key: 账户地址
+	release chan struct{}                 // 不为nil时，每发送一笔交易需要先从中取出一个信号
+}
+
+func handleChain(node *mockNode) *mockChain {
+	chain := &mockChain{latest: make(map[string]*types.LatestBlock)}
+	node.handle("latc_getCurrentTBDB", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		var address string
+		_ = json.Unmarshal(params[0], &address)
+		chain.mu.Lock()
+		defer chain.mu.Unlock()
+		if block, ok := chain.latest[address]; ok {
+			return block, nil
+		}
+		return &types.LatestBlock{}, nil
+	})
+	node.handle("wallet_sendRawTBlock", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+		if chain.release != nil {
+			<-chain.release
+		}
+		var tx block.Transaction
+		_ = json.Unmarshal(params[0], &tx)
+		chain.mu.Lock()
+		defer chain.mu.Unlock()
+		latest, ok := chain.latest[tx.Owner]
+		if !ok {
+			latest = &types.LatestBlock{}
+		}
+		if tx.Height != latest.Height+1 || tx.ParentHash != latest.Hash {
+			return nil, &client.JsonRpcError{Code: -32000, Message: "invalid parent hash"}
+		}
+		hash, _ := tx.CalculateTransactionHash(types.Sm2p256v1)
+		chain.latest[tx.Owner] = &types.LatestBlock{Height: tx.Height, Hash: hash}
+		return hash, nil
+	})
+	return chain
+}
+
+func (c *mockChain) height(address string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if block, ok := c.latest[address]; ok {
+		return block.Height
+	}
+	return 0
+}
+
+func TestTxQueue(t *testing.T) {
+	req := &TxRequest{Type: block.TransactionTypeCallContract, Linker: constant.ZeroAddress, Code: "0x01", Payload: constant.ZeroPayload}
+
+	t.Run("ordered per account", func(t *testing.T) {
+		node := newMockNode(t)
+		chain := handleChain(node)
+		node.handle("latc_getReceipt", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+			var hash common.Hash
+			_ = json.Unmarshal(params[0], &hash)
+			return &types.Receipt{TBlockHash: hash, DBlockNumber: 1, Success: true}, nil
+		})
+		svc := newMockLattice(t, node, nil)
+		queue := svc.NewTxQueue(&TxQueueConfig{MaxConcurrentAccounts: 2, WaitReceipt: true})
+
+		accounts := []*Credentials{newTestCredentials(t), newTestCredentials(t), newTestCredentials(t)}
+		var futures []*TxFuture
+		for i := 0; i < 10; i++ {
+			for _, credentials := range accounts {
+				future, err := queue.Enqueue(context.Background(), credentials, chainId, req)
+				require.NoError(t, err)
+				futures = append(futures, future)
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		for _, future := range futures {
+			hash, receipt, err := future.Receipt(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, *hash, receipt.TBlockHash)
+		}
+		for _, credentials := range accounts {
+			assert.Equal(t, uint64(10), chain.height(credentials.AccountAddress))
+		}
+		require.NoError(t, queue.Close(ctx))
+		_, err := queue.Enqueue(context.Background(), accounts[0], chainId, req)
+		assert.ErrorIs(t, err, ErrTxQueueClosed)
+	})
+
+	t.Run("backpressure and drain", func(t *testing.T) {
+		node := newMockNode(t)
+		chain := handleChain(node)
+		chain.release = make(chan struct{})
+		svc := newMockLattice(t, node, nil)
+		queue := svc.NewTxQueue(&TxQueueConfig{QueueSize: 1})
+		credentials := newTestCredentials(t)
+
+		// 第一笔交易阻塞在发送中，第二笔占满队列
+		first, err := queue.Enqueue(context.Background(), credentials, chainId, req)
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool { return node.callCount("wallet_sendRawTBlock") == 1 }, 5*time.Second, 10*time.Millisecond)
+		second, err := queue.Enqueue(context.Background(), credentials, chainId, req)
+		require.NoError(t, err)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = queue.Enqueue(ctx, credentials, chainId, req)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		closed := make(chan error, 1)
+		go func() { closed <- queue.Close(context.Background()) }()
+		chain.release <- struct{}{}
+		chain.release <- struct{}{}
+		require.NoError(t, <-closed)
+		for _, future := range []*TxFuture{first, second} {
+			_, err := future.Hash(context.Background())
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, uint64(2), chain.height(credentials.AccountAddress))
+	})
+
+	t.Run("idle workers exit", func(t *testing.T) {
+		node := newMockNode(t)
+		chain := handleChain(node)
+		svc := newMockLattice(t, node, nil)
+		queue := svc.NewTxQueue(&TxQueueConfig{IdleTimeout: 20 * time.Millisecond}).(*txQueue)
+		credentials := newTestCredentials(t)
+		workers := func() int {
+			queue.mu.Lock()
+			defer queue.mu.Unlock()
+			return len(queue.workers)
+		}
+
+		for i := 0; i < 2; i++ {
+			future, err := queue.Enqueue(context.Background(), credentials, chainId, req)
+			require.NoError(t, err)
+			_, err = future.Hash(context.Background())
+			require.NoError(t, err)
+			assert.Eventually(t, func() bool { return workers() == 0 }, 5*time.Second, 10*time.Millisecond)
+		}
+		assert.Equal(t, uint64(2), chain.height(credentials.AccountAddress))
+		require.NoError(t, queue.Close(context.Background()))
+	})
+
+	t.Run("close timeout", func(t *testing.T) {
+		node := newMockNode(t)
+		chain := handleChain(node)
+		chain.release = make(chan struct{})
+		svc := newMockLattice(t, node, nil)
+		queue := svc.NewTxQueue(nil)
+		credentials := newTestCredentials(t)
+
+		first, err := queue.Enqueue(context.Background(), credentials, chainId, req)
+		require.NoError(t, err)
+		second, err := queue.Enqueue(context.Background(), credentials, chainId, req)
+		require.NoError(t, err)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, queue.Close(ctx), context.DeadlineExceeded)
+		close(chain.release)
+
+		_, err = first.Hash(context.Background())
+		assert.Error(t, err)
+		_, err = second.Hash(context.Background())
+		assert.ErrorIs(t, err, ErrTxQueueClosed)
+	})
+}
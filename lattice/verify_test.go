@@ -0,0 +1,125 @@
+package lattice
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/LatticeBCLab/go-lattice/common/constant"
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyTransaction(t *testing.T) {
+	credentials := newTestCredentials(t)
+	signer, err := credentials.GetSigner(types.Sm2p256v1)
+	require.NoError(t, err)
+
+	newSignedBlock := func(t *testing.T) *types.TransactionBlock {
+		tx := block.NewTransactionBuilder(block.TransactionTypeSend).
+			SetLatestBlock(&types.LatestBlock{Height: 4, Hash: common.HexToHash("0x04"), DaemonBlockHash: common.HexToHash("0x0a")}).
+			SetOwner(credentials.AccountAddress).
+			SetLinker(constant.ZeroAddress).
+			SetPayload("0x0102").
+			SetAmount(10).
+			SetJoule(3).
+			Build()
+		tx.Hub = []common.Hash{common.HexToHash("0x0b")}
+		require.NoError(t, tx.SolvePoW(context.Background(), 1, types.Sm2p256v1, 16, 2))
+		require.NoError(t, tx.SignTXWithSigner(1, signer))
+		hash, err := tx.CalculateTransactionHash(types.Sm2p256v1)
+		require.NoError(t, err)
+
+		return &types.TransactionBlock{
+			Height:     big.NewInt(int64(tx.Height)),
+			Hash:       hash,
+			ParentHash: tx.ParentHash,
+			DaemonHash: tx.DaemonHash,
+			Payload:    tx.Payload,
+			Hub:        []string{tx.Hub[0].Hex()},
+			Timestamp:  tx.Timestamp,
+			Type:       string(tx.Type),
+			Owner:      tx.Owner,
+			Linker:     tx.Linker,
+			CodeHash:   tx.CodeHash.Hex(),
+			Amount:     "10",
+			Joule:      3,
+			Sign:       tx.Sign,
+			Difficulty: tx.Difficulty,
+			Pow:        tx.ProofOfWork.String(),
+		}
+	}
+
+	t.Run("valid block", func(t *testing.T) {
+		tx, err := block.TransactionFromBlock(newSignedBlock(t))
+		require.NoError(t, err)
+		assert.NoError(t, block.VerifyTransaction(tx, 1, types.Sm2p256v1))
+	})
+
+	t.Run("hex amount", func(t *testing.T) {
+		tblock := newSignedBlock(t)
+		tblock.Amount = "0xa"
+		tx, err := block.TransactionFromBlock(tblock)
+		require.NoError(t, err)
+		assert.NoError(t, block.VerifyTransaction(tx, 1, types.Sm2p256v1))
+	})
+
+	t.Run("tampered amount", func(t *testing.T) {
+		tblock := newSignedBlock(t)
+		tblock.Amount = "11"
+		tx, err := block.TransactionFromBlock(tblock)
+		require.NoError(t, err)
+		assert.ErrorIs(t, block.VerifyTransaction(tx, 1, types.Sm2p256v1), block.ErrTransactionSignatureInvalid)
+	})
+
+	t.Run("wrong chain id", func(t *testing.T) {
+		tx, err := block.TransactionFromBlock(newSignedBlock(t))
+		require.NoError(t, err)
+		assert.ErrorIs(t, block.VerifyTransaction(tx, 2, types.Sm2p256v1), block.ErrTransactionSignatureInvalid)
+	})
+
+	t.Run("tampered hash", func(t *testing.T) {
+		tblock := newSignedBlock(t)
+		tblock.Hash = common.HexToHash("0x01")
+		tx, err := block.TransactionFromBlock(tblock)
+		require.NoError(t, err)
+		assert.ErrorIs(t, block.VerifyTransaction(tx, 1, types.Sm2p256v1), block.ErrTransactionHashMismatch)
+	})
+
+	t.Run("not signed", func(t *testing.T) {
+		tblock := newSignedBlock(t)
+		tblock.Sign = ""
+		tx, err := block.TransactionFromBlock(tblock)
+		require.NoError(t, err)
+		assert.ErrorIs(t, block.VerifyTransaction(tx, 1, types.Sm2p256v1), block.ErrTransactionNotSigned)
+	})
+
+	t.Run("invalid block", func(t *testing.T) {
+		tblock := newSignedBlock(t)
+		tblock.Amount = "abc"
+		_, err := block.TransactionFromBlock(tblock)
+		assert.Error(t, err)
+
+		tblock = newSignedBlock(t)
+		tblock.Type = "unknown"
+		_, err = block.TransactionFromBlock(tblock)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid payload", func(t *testing.T) {
+		for _, payload := range []string{"0x123", "0102"} {
+			tblock := newSignedBlock(t)
+			tblock.Payload = payload
+			_, err := block.TransactionFromBlock(tblock)
+			assert.ErrorIs(t, err, block.ErrTransactionPayloadInvalid)
+
+			tx, err := block.TransactionFromBlock(newSignedBlock(t))
+			require.NoError(t, err)
+			tx.Payload = payload
+			assert.ErrorIs(t, block.VerifyTransaction(tx, 1, types.Sm2p256v1), block.ErrTransactionPayloadInvalid)
+		}
+	})
+}
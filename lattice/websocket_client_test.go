@@ -7,7 +7,9 @@ import (
 
 	"github.com/LatticeBCLab/go-lattice/common/types"
 	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func setupWebsocketClient() client.WebSocketApi {
@@ -69,3 +71,168 @@ func TestWorkflow(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestWebSocketSubscriptionManager(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("multiplex subscriptions on one connection", func(t *testing.T) {
+		node := newMockWsNode(t)
+		wsApi := newMockWebSocketApi(t, node)
+		subA, err := wsApi.Subscribe(ctx, "latc_subscribe", "monitorData")
+		require.NoError(t, err)
+		subB, err := wsApi.Subscribe(ctx, "node_subscribe", "workflow")
+		require.NoError(t, err)
+		assert.NotEqual(t, subA.ID(), subB.ID())
+		current, total := node.connections()
+		assert.Equal(t, 1, current)
+		assert.Equal(t, 1, total)
+
+		node.notify(t, subB.ID(), map[string]any{"name": "b"})
+		node.notify(t, subA.ID(), map[string]any{"name": "a"})
+		dataA, err := subA.Read()
+		assert.NoError(t, err)
+		assert.Equal(t, "a", dataA["name"])
+		select {
+		case dataB := <-subB.Chan():
+			assert.Equal(t, "b", dataB["name"])
+		case <-time.After(5 * time.Second):
+			t.Fatal("subscription data not received")
+		}
+	})
+
+	t.Run("resubscribe after reconnect", func(t *testing.T) {
+		node := newMockWsNode(t)
+		wsApi := newMockWebSocketApi(t, node)
+		sub, err := wsApi.Subscribe(ctx, "node_subscribe", "workflow")
+		require.NoError(t, err)
+		oldId := sub.ID()
+
+		node.dropConnections()
+		ids := node.waitSubscriptions(t, 1)
+		require.Len(t, ids, 1)
+		assert.NotEqual(t, oldId, ids[0])
+		assert.Equal(t, "workflow", node.subscriptionName(ids[0]))
+		_, total := node.connections()
+		assert.Equal(t, 2, total)
+
+		node.notify(t, ids[0], map[string]any{"name": "after reconnect"})
+		data, err := sub.Read()
+		assert.NoError(t, err)
+		assert.Equal(t, "after reconnect", data["name"])
+		assert.Equal(t, ids[0], sub.ID())
+	})
+
+	t.Run("close sends unsubscribe", func(t *testing.T) {
+		node := newMockWsNode(t)
+		wsApi := newMockWebSocketApi(t, node)
+		sub, err := wsApi.Subscribe(ctx, "latc_subscribe", "monitorData")
+		require.NoError(t, err)
+		id := sub.ID()
+
+		assert.NoError(t, sub.Close())
+		assert.Equal(t, []string{id}, node.unsubscribedIds())
+		assert.Empty(t, node.subscriptionIds())
+		_, err = sub.Read()
+		assert.ErrorIs(t, err, client.ErrSubscriptionClosed)
+		assert.ErrorIs(t, sub.Err(), client.ErrSubscriptionClosed)
+	})
+
+	t.Run("slow consumer does not block other subscriptions", func(t *testing.T) {
+		node := newMockWsNode(t)
+		wsApi := client.NewWebSocketApi(&client.WebSocketApiInitParam{
+			WebSocketUrl:           node.url(),
+			PingInterval:           time.Second,
+			SubscriptionBufferSize: 1,
+		})
+		t.Cleanup(func() { _ = wsApi.Close() })
+		slow, err := wsApi.Subscribe(ctx, "latc_subscribe", "monitorData")
+		require.NoError(t, err)
+		fast, err := wsApi.Subscribe(ctx, "node_subscribe", "workflow")
+		require.NoError(t, err)
+		slowId := slow.ID()
+
+		node.notify(t, slowId, map[string]any{"name": "1"})
+		node.notify(t, slowId, map[string]any{"name": "2"})
+		node.notify(t, fast.ID(), map[string]any{"name": "fast"})
+		data, err := fast.Read()
+		assert.NoError(t, err)
+		assert.Equal(t, "fast", data["name"])
+
+		assert.ErrorIs(t, slow.Err(), client.ErrSlowConsumer)
+		assert.Eventually(t, func() bool {
+			return len(node.unsubscribedIds()) == 1 && node.unsubscribedIds()[0] == slowId
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("close api ends subscriptions", func(t *testing.T) {
+		node := newMockWsNode(t)
+		wsApi := newMockWebSocketApi(t, node)
+		sub, err := wsApi.Subscribe(ctx, "latc_subscribe", "monitorData")
+		require.NoError(t, err)
+		ch := sub.Chan()
+
+		assert.NoError(t, wsApi.Close())
+		_, ok := <-ch
+		assert.False(t, ok)
+		assert.ErrorIs(t, sub.Err(), client.ErrWebSocketClosed)
+		_, err = wsApi.Subscribe(ctx, "latc_subscribe", "monitorData")
+		assert.ErrorIs(t, err, client.ErrWebSocketClosed)
+	})
+}
+
+func TestTypedSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	node := newMockWsNode(t)
+	wsApi := newMockWebSocketApi(t, node)
+
+	t.Run("daemon blocks", func(t *testing.T) {
+		sub, err := wsApi.DaemonBlocks(ctx)
+		require.NoError(t, err)
+		defer sub.Close()
+		assert.Equal(t, "newDBlock", node.subscriptionName(sub.ID()))
+
+		node.notify(t, sub.ID(), map[string]any{"hash": common.HexToHash("0x01"), "number": 100})
+		daemonBlock, err := sub.Read()
+		assert.NoError(t, err)
+		assert.Equal(t, common.HexToHash("0x01"), daemonBlock.Hash)
+		assert.Equal(t, int64(100), daemonBlock.Height.Int64())
+	})
+
+	t.Run("transaction blocks", func(t *testing.T) {
+		sub, err := wsApi.TransactionBlocks(ctx, "zltc_Z1pnS94bP4hQSYLs4aP4UwBP9pH8bEvhi")
+		require.NoError(t, err)
+		defer sub.Close()
+		params := node.subscriptionParams(sub.ID())
+		require.Len(t, params, 2)
+		assert.JSONEq(t, `"zltc_Z1pnS94bP4hQSYLs4aP4UwBP9pH8bEvhi"`, string(params[1]))
+
+		node.notify(t, sub.ID(), map[string]any{"hash": common.HexToHash("0x02"), "number": 3, "owner": "zltc_Z1pnS94bP4hQSYLs4aP4UwBP9pH8bEvhi"})
+		transactionBlock, err := sub.Read()
+		assert.NoError(t, err)
+		assert.Equal(t, common.HexToHash("0x02"), transactionBlock.Hash)
+		assert.Equal(t, "zltc_Z1pnS94bP4hQSYLs4aP4UwBP9pH8bEvhi", transactionBlock.Owner)
+	})
+
+	t.Run("contract logs", func(t *testing.T) {
+		topic := common.HexToHash("0x03")
+		sub, err := wsApi.Logs(ctx, &types.LogsSubscribeCondition{
+			Addresses: []string{"zltc_QLbz7JHiBTspS962RLKV8GndWFwjA5K66"},
+			Topics:    [][]common.Hash{{topic}},
+		})
+		require.NoError(t, err)
+		defer sub.Close()
+		params := node.subscriptionParams(sub.ID())
+		require.Len(t, params, 2)
+		assert.Equal(t, "logs", node.subscriptionName(sub.ID()))
+		assert.JSONEq(t, `{"address":["zltc_QLbz7JHiBTspS962RLKV8GndWFwjA5K66"],"topics":[["`+topic.Hex()+`"]]}`, string(params[1]))
+
+		node.notify(t, sub.ID(), &types.Event{Address: "zltc_QLbz7JHiBTspS962RLKV8GndWFwjA5K66", Topics: []common.Hash{topic}})
+		select {
+		case event := <-sub.Chan():
+			assert.Equal(t, "zltc_QLbz7JHiBTspS962RLKV8GndWFwjA5K66", event.Address)
+			assert.Equal(t, []common.Hash{topic}, event.Topics)
+		case <-time.After(5 * time.Second):
+			t.Fatal("contract log not received")
+		}
+	})
+}
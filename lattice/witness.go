@@ -0,0 +1,96 @@
+package lattice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrWitnessProofHashMismatch 见证证明的区块哈希与期望的区块哈希不一致
+var ErrWitnessProofHashMismatch = errors.New("见证证明的区块哈希与期望的哈希不一致")
+
+// TrustedWitnesses 校验见证证明时使用的可信的共识节点集合和共识类型，应来自节点之外的可信来源，
+// 如链的创世配置或独立维护的共识节点列表，不能来自提供见证证明的节点
+//
+//   - Consensus 共识类型，不区分大小写
+//   - Saints    共识节点的地址
+type TrustedWitnesses struct {
+	Consensus types.Consensus
+	Saints    []string
+}
+
+func (svc *lattice) VerifyDBlockProof(ctx context.Context, chainId string, height *big.Int, expectedHash common.Hash, trusted *TrustedWitnesses) (*block.WitnessProofVerdict, error) {
+	if err := trusted.validate(); err != nil {
+		return nil, err
+	}
+	if expectedHash == (common.Hash{}) {
+		if height == nil || !height.IsUint64() {
+			return nil, fmt.Errorf("无效的守护区块高度：%s", height)
+		}
+		daemonBlock, err := svc.httpApi.GetDaemonBlockByHeight(ctx, chainId, height.Uint64())
+		if err != nil {
+			log.Error().Err(err)
+			return nil, err
+		}
+		expectedHash = daemonBlock.Hash
+	}
+	proof, err := svc.httpApi.GetDBlockProof(ctx, chainId, height)
+	if err != nil {
+		log.Error().Err(err)
+		return nil, err
+	}
+	return svc.verifyWitnessProof(height, expectedHash, proof, trusted)
+}
+
+func (svc *lattice) VerifyTBlockProof(ctx context.Context, chainId, accountAddress string, height *big.Int, expectedHash common.Hash, trusted *TrustedWitnesses) (*block.WitnessProofVerdict, error) {
+	if err := trusted.validate(); err != nil {
+		return nil, err
+	}
+	if expectedHash == (common.Hash{}) {
+		if height == nil || !height.IsUint64() {
+			return nil, fmt.Errorf("无效的交易区块高度：%s", height)
+		}
+		tblock, err := svc.httpApi.GetTBlockByHeight(ctx, chainId, accountAddress, height.Uint64())
+		if err != nil {
+			log.Error().Err(err)
+			return nil, err
+		}
+		expectedHash = tblock.Hash
+	}
+	proof, err := svc.httpApi.GetTBlockProof(ctx, chainId, accountAddress, height)
+	if err != nil {
+		log.Error().Err(err)
+		return nil, err
+	}
+	if proof != nil && proof.Owner != "" && proof.Owner != accountAddress {
+		return nil, fmt.Errorf("见证证明的账户%s与查询的账户%s不一致", proof.Owner, accountAddress)
+	}
+	return svc.verifyWitnessProof(height, expectedHash, proof, trusted)
+}
+
+func (trusted *TrustedWitnesses) validate() error {
+	if trusted == nil || len(trusted.Saints) == 0 {
+		return errors.New("校验见证证明需要可信的共识节点集合")
+	}
+	return nil
+}
+
+// verifyWitnessProof 校验见证证明的区块高度和区块哈希，并以可信的共识节点集合校验见证签名
+func (svc *lattice) verifyWitnessProof(height *big.Int, expectedHash common.Hash, proof *types.WitnessProof, trusted *TrustedWitnesses) (*block.WitnessProofVerdict, error) {
+	if proof == nil {
+		return nil, fmt.Errorf("区块%s没有见证证明", height)
+	}
+	if proof.Number != nil && height != nil && proof.Number.Cmp(height) != 0 {
+		return nil, fmt.Errorf("见证证明的区块高度%s与查询的高度%s不一致", proof.Number, height)
+	}
+	if expectedHash == (common.Hash{}) || proof.Hash != expectedHash {
+		return nil, fmt.Errorf("%w，证明的哈希：%s，期望的哈希：%s", ErrWitnessProofHashMismatch, proof.Hash, expectedHash)
+	}
+	return block.VerifyWitnessProof(proof, svc.chainConfig.Curve, trusted.Consensus, trusted.Saints)
+}
@@ -0,0 +1,117 @@
+package lattice
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/LatticeBCLab/go-lattice/common/types"
+	"github.com/LatticeBCLab/go-lattice/lattice/block"
+	"github.com/LatticeBCLab/go-lattice/lattice/client"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyWitnessProof(t *testing.T) {
+	hash := common.HexToHash("0x0abc")
+	saints := make([]string, 4)
+	signatures := make([]*types.Signature, 4)
+	for i := range saints {
+		credentials := newTestCredentials(t)
+		s, err := credentials.GetSigner(types.Sm2p256v1)
+		require.NoError(t, err)
+		sign, err := s.SignHash(hash)
+		require.NoError(t, err)
+		saints[i] = credentials.AccountAddress
+		signatures[i] = &types.Signature{From: credentials.AccountAddress, Hash: hash, Number: big.NewInt(8), Sign: sign}
+	}
+	outsider := newTestCredentials(t)
+	outsiderSigner, err := outsider.GetSigner(types.Sm2p256v1)
+	require.NoError(t, err)
+	outsiderSign, err := outsiderSigner.SignHash(hash)
+	require.NoError(t, err)
+
+	t.Run("quorum", func(t *testing.T) {
+		assert.Equal(t, 3, block.WitnessQuorum(types.ConsensusPBFT, 4))
+		assert.Equal(t, 5, block.WitnessQuorum(types.ConsensusPBFT, 7))
+		assert.Equal(t, 3, block.WitnessQuorum(types.ConsensusPOA, 4))
+		assert.Equal(t, 1, block.WitnessQuorum(types.ConsensusRAFT, 1))
+		assert.Equal(t, 5, block.WitnessQuorum("pbft", 7))
+	})
+
+	t.Run("reach quorum", func(t *testing.T) {
+		proof := &types.WitnessProof{Hash: hash, Signers: signatures[:3]}
+		verdict, err := block.VerifyWitnessProof(proof, types.Sm2p256v1, types.ConsensusPBFT, saints)
+		require.NoError(t, err)
+		assert.True(t, verdict.Valid)
+		assert.Equal(t, 3, verdict.Required)
+		assert.Equal(t, saints[:3], verdict.Signers)
+	})
+
+	t.Run("invalid signatures are not counted", func(t *testing.T) {
+		forged := &types.Signature{From: saints[2], Hash: hash, Sign: outsiderSign}
+		duplicated := signatures[0]
+		notSaint := &types.Signature{From: outsider.AccountAddress, Hash: hash, Sign: outsiderSign}
+		otherHash := &types.Signature{From: saints[3], Hash: common.HexToHash("0x01"), Sign: signatures[3].Sign}
+		proof := &types.WitnessProof{Hash: hash, Signers: []*types.Signature{signatures[0], signatures[1], forged, duplicated, notSaint, otherHash}}
+
+		verdict, err := block.VerifyWitnessProof(proof, types.Sm2p256v1, types.ConsensusPBFT, saints)
+		assert.ErrorIs(t, err, block.ErrWitnessQuorumNotReached)
+		require.NotNil(t, verdict)
+		assert.False(t, verdict.Valid)
+		assert.Len(t, verdict.Signers, 2)
+		assert.Len(t, verdict.Rejected, 3)
+		assert.Contains(t, verdict.Rejected, outsider.AccountAddress)
+	})
+
+	t.Run("verify dblock proof from node", func(t *testing.T) {
+		node := newMockNode(t)
+		node.handle("latc_getDBlockProof", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+			return &types.WitnessProof{Hash: hash, Number: big.NewInt(8), Signers: signatures}, nil
+		})
+		node.handle("latc_getDBlockByNumber", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+			return &types.DaemonBlock{Hash: hash, Height: big.NewInt(8)}, nil
+		})
+		svc := newMockLattice(t, node, nil)
+		// 节点返回的共识类型为小写
+		trusted := &TrustedWitnesses{Consensus: "pbft", Saints: saints}
+
+		verdict, err := svc.VerifyDBlockProof(context.Background(), chainId, big.NewInt(8), hash, trusted)
+		require.NoError(t, err)
+		assert.True(t, verdict.Valid)
+		assert.Equal(t, 3, verdict.Required)
+		assert.Len(t, verdict.Signers, 4)
+
+		_, err = svc.VerifyDBlockProof(context.Background(), chainId, big.NewInt(8), common.Hash{}, trusted)
+		require.NoError(t, err)
+		assert.Equal(t, 1, node.callCount("latc_getDBlockByNumber"))
+
+		_, err = svc.VerifyDBlockProof(context.Background(), chainId, big.NewInt(9), hash, trusted)
+		assert.Error(t, err)
+
+		_, err = svc.VerifyDBlockProof(context.Background(), chainId, big.NewInt(8), common.HexToHash("0x0def"), trusted)
+		assert.ErrorIs(t, err, ErrWitnessProofHashMismatch)
+	})
+
+	t.Run("saints from the node are not trusted", func(t *testing.T) {
+		node := newMockNode(t)
+		node.handle("latc_getDBlockProof", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+			return &types.WitnessProof{Hash: hash, Number: big.NewInt(8), Signers: []*types.Signature{{From: outsider.AccountAddress, Hash: hash, Sign: outsiderSign}}}, nil
+		})
+		node.handle("wallet_getConfirmConfig", func(params []json.RawMessage) (any, *client.JsonRpcError) {
+			return &types.NodeConfirmedConfiguration{Consensus: string(types.ConsensusPBFT), LatcSaints: []string{outsider.AccountAddress}}, nil
+		})
+		svc := newMockLattice(t, node, nil)
+
+		verdict, err := svc.VerifyDBlockProof(context.Background(), chainId, big.NewInt(8), hash, &TrustedWitnesses{Consensus: types.ConsensusPBFT, Saints: saints})
+		assert.ErrorIs(t, err, block.ErrWitnessQuorumNotReached)
+		require.NotNil(t, verdict)
+		assert.False(t, verdict.Valid)
+		assert.Zero(t, node.callCount("wallet_getConfirmConfig"))
+
+		_, err = svc.VerifyDBlockProof(context.Background(), chainId, big.NewInt(8), hash, nil)
+		assert.Error(t, err)
+	})
+}